@@ -5,6 +5,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -23,6 +24,269 @@ var (
 	serverAddr = flag.String("server_addr", "localhost:10000", "The server address in the format of host:port")
 )
 
+// _DefaultDistance is the distance used by similar when none is given.
+const _DefaultDistance = 10
+
+// _DuplicateFlags maps the flags of up to the duplicate policies.
+var _DuplicateFlags = map[string]imgrepo.DuplicatePolicy{
+	"-skip":   imgrepo.SkipDuplicates,
+	"-reject": imgrepo.RejectDuplicates,
+}
+
+// _PrivacyFlags maps the flags of up to the privacy policies.
+var _PrivacyFlags = map[string]imgrepo.Privacy{
+	"-keep":           imgrepo.KeepMetadata,
+	"-strip-location": imgrepo.StripLocation,
+	"-strip":          imgrepo.StripMetadata,
+}
+
+// _Fits maps the fits accepted by convert to their values.
+var _Fits = map[string]imgrepo.Fit{
+	"contain": imgrepo.Contain,
+	"cover":   imgrepo.Cover,
+	"crop":    imgrepo.Crop,
+}
+
+// _GroupActions describe the subcommands of group in messages.
+var _GroupActions = map[string]string{
+	"new":     "create",
+	"show":    "show",
+	"add":     "add members to",
+	"kick":    "remove members from",
+	"admin":   "add admins to",
+	"unadmin": "remove admins from",
+	"leave":   "leave",
+	"del":     "delete",
+}
+
+// _AlbumActions describe the subcommands of album in messages.
+var _AlbumActions = map[string]string{
+	"new":     "create",
+	"add":     "add images to",
+	"rm":      "remove images from",
+	"share":   "share",
+	"unshare": "unshare",
+	"del":     "delete",
+}
+
+// _Roles maps the roles accepted by role to their values.
+var _Roles = map[string]imgrepo.Role{
+	"user":      imgrepo.UserRole,
+	"moderator": imgrepo.ModeratorRole,
+	"admin":     imgrepo.AdminRole,
+}
+
+// roleName returns the name of a role in _Roles.
+func roleName(role imgrepo.Role) string {
+	for name, r := range _Roles {
+		if r == role {
+			return name
+		}
+	}
+	return strconv.Itoa(int(role))
+}
+
+// _Formats are the formats accepted by convert.
+var _Formats = map[string]bool{"jpeg": true, "png": true, "webp": true, "gif": true}
+
+// parseTransform parses the arguments of convert, a [width]x[height] box
+// followed by a fit, a format and a quality, in any order.
+func parseTransform(args []string) (imgrepo.Transform, error) {
+	var t imgrepo.Transform
+
+	box := strings.SplitN(args[0], "x", 2)
+	if len(box) != 2 {
+		return t, fmt.Errorf("invalid size: %s", args[0])
+	}
+	for i, side := range []*int{&t.Width, &t.Height} {
+		if box[i] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(box[i])
+		if err != nil || n < 0 {
+			return t, fmt.Errorf("invalid size: %s", args[0])
+		}
+		*side = n
+	}
+
+	for _, arg := range args[1:] {
+		if fit, ok := _Fits[arg]; ok {
+			t.Fit = fit
+		} else if _Formats[arg] {
+			t.Format = arg
+		} else if q, err := strconv.Atoi(arg); err == nil && q > 0 && q <= 100 {
+			t.Quality = q
+		} else {
+			return t, fmt.Errorf("invalid argument: %s", arg)
+		}
+	}
+
+	return t, nil
+}
+
+// save writes the image downloaded by download to the directory, under the
+// name sent by the server.
+func save(dir string, download func(w io.Writer) (*imgrepo.Image, error)) (*imgrepo.Image, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to find path: %v", err)
+	}
+
+	// The name is only known once the download starts, so the image
+	// is written to a temporary file and renamed afterwards.
+	tmp, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create file: %v", err)
+	}
+
+	img, err := download(tmp)
+	tmp.Chmod(0644)
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("unable to download image: %v", err)
+	}
+
+	path := filepath.Join(dir, filepath.Base(img.Name))
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("unable to save file: %v", err)
+	}
+
+	return img, nil
+}
+
+// describe summarizes the metadata of an image, which is empty for images
+// uploaded before metadata was recorded.
+func describe(md imgrepo.Metadata) string {
+	if md.MimeType == "" {
+		return ""
+	}
+
+	fields := []string{md.MimeType, fmt.Sprintf("%dx%d", md.Width, md.Height)}
+	if !md.Taken.IsZero() {
+		fields = append(fields, "taken "+md.Taken.Format("2006-01-02T15:04:05"))
+	}
+	if camera := strings.TrimSpace(md.Make + " " + md.Model); camera != "" {
+		fields = append(fields, fmt.Sprintf("by %q", camera))
+	}
+	if md.Location != nil {
+		fields = append(fields, fmt.Sprintf("at %.5f,%.5f", md.Location.Latitude, md.Location.Longitude))
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// labels lists the tags of an image, or is empty if it has none.
+func labels(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "tagged " + strings.Join(tags, ",")
+}
+
+// sharing lists the users and groups an image is shared with, or is empty
+// if it is not shared, or the user does not own it.
+func sharing(g imgrepo.Grant) string {
+	if g.Empty() {
+		return ""
+	}
+	return "shared with " + grantees(g)
+}
+
+// grantees lists the users and groups of a grant, groups prefixed by
+// "group:".
+func grantees(g imgrepo.Grant) string {
+	names := append([]string(nil), g.Readers...)
+	for _, group := range g.Groups {
+		names = append(names, "group:"+group)
+	}
+	return strings.Join(names, ",")
+}
+
+// printGroup prints the admins and members of a group.
+func printGroup(g *imgrepo.Group) {
+	fmt.Printf("group %s: admins [%s], members [%s]\n", g.Name, strings.Join(g.Admins, ","), strings.Join(g.Members, ","))
+}
+
+// printAlbum prints an album and the images it holds.
+func printAlbum(album *imgrepo.Album) {
+	fmt.Println(album.Name, album.Owner, perm(album.Access), album.Id, fmt.Sprintf("%d image(s)", len(album.Images)), sharing(album.Grant))
+}
+
+// parseFilter parses the arguments of ls, -shared for the images shared with
+// the user, and tags separated by commas, which select the images with any
+// of them, or all of them if preceded by -all.
+func parseFilter(args []string) (imgrepo.ListFilter, error) {
+	var filter imgrepo.ListFilter
+
+	if len(args) > 0 && args[0] == "-shared" {
+		filter.Shared = true
+		args = args[1:]
+		if len(args) == 0 {
+			return filter, nil
+		}
+	}
+
+	all := len(args) > 0 && args[0] == "-all"
+	if all {
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return filter, fmt.Errorf("invalid filter: %s", strings.Join(args, " "))
+	}
+
+	if all {
+		filter.All = strings.Split(args[0], ",")
+	} else {
+		filter.Any = strings.Split(args[0], ",")
+	}
+
+	return filter, nil
+}
+
+// parseLink parses the arguments of link, the optional flags -expires with
+// a duration and -max with a number of downloads, followed by an image id.
+func parseLink(args []string) (id string, expires time.Duration, maxDownloads int, err error) {
+	for len(args) > 2 {
+		switch args[0] {
+		case "-expires":
+			expires, err = time.ParseDuration(args[1])
+			if err != nil || expires <= 0 {
+				return "", 0, 0, fmt.Errorf("invalid duration: %s", args[1])
+			}
+		case "-max":
+			maxDownloads, err = strconv.Atoi(args[1])
+			if err != nil || maxDownloads <= 0 {
+				return "", 0, 0, fmt.Errorf("invalid maximum downloads: %s", args[1])
+			}
+		default:
+			return "", 0, 0, fmt.Errorf("invalid flag: %s", args[0])
+		}
+		args = args[2:]
+	}
+	if len(args) != 1 {
+		return "", 0, 0, fmt.Errorf("invalid arguments: %s", strings.Join(args, " "))
+	}
+
+	return args[0], expires, maxDownloads, nil
+}
+
+// usage describes how much of a share link was used, and when it expires.
+func usage(link *imgrepo.ShareLink) string {
+	fields := []string{fmt.Sprintf("%d download(s)", link.Downloads)}
+	if link.MaxDownloads > 0 {
+		fields[0] = fmt.Sprintf("%d/%d download(s)", link.Downloads, link.MaxDownloads)
+	}
+	if !link.Expires.IsZero() {
+		fields = append(fields, "expires "+link.Expires.Local().Format("2006-01-02T15:04:05"))
+	}
+	if link.Expired(time.Now()) {
+		fields = append(fields, "expired")
+	}
+
+	return strings.Join(fields, " ")
+}
+
 func perm(p imgrepo.Permission) string {
 	return [...]string{"Public", "Private"}[p]
 }
@@ -68,11 +332,13 @@ func main() {
 	defer conn.Close()
 
 	client := proto.NewRepoClient(conn)
-	irc := proto.NewImageRepoClient(client)
+	irc := proto.NewImageRepoClient(client, proto.NewAdminClient(conn))
 
 	log.Printf("connected to server")
 
 	var lastId string
+	var filter imgrepo.ListFilter
+	var lastAlbumId, lastAlbumImageId string
 
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
@@ -98,6 +364,25 @@ func main() {
 		} else if cmd == "up" && len(input) >= 4 {
 			var files []string
 
+			// Optional flags determine what happens to duplicates, what
+			// metadata is stripped, and the tags of the uploaded images.
+			policy := imgrepo.AllowDuplicates
+			privacy := imgrepo.DefaultPrivacy
+			var tags []string
+			for len(input) >= 5 {
+				if p, ok := _DuplicateFlags[input[1]]; ok {
+					policy = p
+				} else if p, ok := _PrivacyFlags[input[1]]; ok {
+					privacy = p
+				} else if input[1] == "-tags" && len(input) >= 6 {
+					tags = strings.Split(input[2], ",")
+					input = input[1:]
+				} else {
+					break
+				}
+				input = input[1:]
+			}
+
 			val, err := strconv.Atoi(input[1])
 			if err != nil {
 				fmt.Printf("invalid permission: %v\n\n", err)
@@ -121,47 +406,110 @@ func main() {
 			}
 
 			start := time.Now()
+			var uploaded, skipped int
 
 			fmt.Printf("found %d file(s)\n", len(files))
 			for _, file := range files {
-				data, err := os.ReadFile(file)
+				f, err := os.Open(file)
 				if err != nil {
 					fmt.Printf("unable to open file %s: %v\n\n", file, err)
 					continue
 				}
 
-				base := filepath.Base(file)
-				err = irc.Upload(&imgrepo.Image{Name: base, Owner: irc.Owner, Access: access, Raw: data})
+				img := &imgrepo.Image{Name: filepath.Base(file), Owner: irc.Owner, Access: access, Tags: tags}
+				dups, err := irc.Upload(img, f, policy, privacy)
+				f.Close()
 				if err != nil {
 					fmt.Printf("unable to upload file %s: %v\n\n", file, err)
 					continue
 				}
 
-				fmt.Printf("uploaded file: %s\n", file)
+				if img.Id == "" {
+					skipped++
+					fmt.Printf("skipped file: %s, duplicate of %s\n", file, strings.Join(dups, " "))
+					continue
+				}
+
+				uploaded++
+				if len(dups) > 0 {
+					fmt.Printf("uploaded file: %s, possible duplicate of %s\n", file, strings.Join(dups, " "))
+				} else {
+					fmt.Printf("uploaded file: %s\n", file)
+				}
 			}
 
-			fmt.Printf("uploaded %d files in %v\n", len(files), time.Since(start))
-		} else if cmd == "down" && len(input) == 3 {
-			img, err := irc.Download(input[1])
+			fmt.Printf("uploaded %d files in %v\n", uploaded, time.Since(start))
+			if skipped > 0 {
+				fmt.Printf("skipped %d duplicates\n", skipped)
+			}
+		} else if cmd == "resume" && len(input) == 3 {
+			f, err := os.Open(input[2])
 			if err != nil {
-				fmt.Printf("unable to download image: %v\n\n", err)
+				fmt.Printf("unable to open file %s: %v\n\n", input[2], err)
 				continue
 			}
 
-			if _, err := os.Stat(input[2]); os.IsNotExist(err) {
-				fmt.Printf("unable to find path: %v\n\n", err)
+			dups, err := irc.ResumeUpload(input[1], f, imgrepo.AllowDuplicates, imgrepo.DefaultPrivacy)
+			f.Close()
+			if err != nil {
+				fmt.Printf("unable to resume upload: %v\n\n", err)
 				continue
 			}
 
-			path := filepath.Join(input[2], img.Name)
-			os.WriteFile(path, img.Raw, 0666)
+			if len(dups) > 0 {
+				fmt.Printf("uploaded file: %s, possible duplicate of %s\n", input[2], strings.Join(dups, " "))
+			} else {
+				fmt.Printf("uploaded file: %s\n", input[2])
+			}
+		} else if cmd == "down" && (len(input) == 3 || len(input) == 4) {
+			size := 0
+			if len(input) == 4 {
+				var err error
+				size, err = strconv.Atoi(input[3])
+				if err != nil || size <= 0 {
+					fmt.Printf("invalid size: %s\n\n", input[3])
+					continue
+				}
+			}
+
+			img, err := save(input[2], func(w io.Writer) (*imgrepo.Image, error) {
+				return irc.Download(input[1], size, w)
+			})
+			if err != nil {
+				fmt.Printf("%v\n\n", err)
+				continue
+			}
 			fmt.Printf("downloaded file: %s\n", img.Name)
+		} else if cmd == "convert" && len(input) >= 4 && len(input) <= 7 {
+			t, err := parseTransform(input[3:])
+			if err != nil {
+				fmt.Printf("%v\n\n", err)
+				continue
+			}
+
+			img, err := save(input[2], func(w io.Writer) (*imgrepo.Image, error) {
+				return irc.Transform(input[1], t, w)
+			})
+			if err != nil {
+				fmt.Printf("%v\n\n", err)
+				continue
+			}
+			fmt.Printf("converted file: %s\n", img.Name)
 		} else if cmd == "ls" {
-			if len(input) < 2 || input[1] != "-n" {
+			// The next page is listed with the filter of the first.
+			if len(input) != 2 || input[1] != "-n" {
 				lastId = ""
+				filter = imgrepo.ListFilter{}
+			}
+			if len(input) > 1 && input[1] != "-n" {
+				filter, err = parseFilter(input[1:])
+				if err != nil {
+					fmt.Printf("%v\n\n", err)
+					continue
+				}
 			}
 
-			imgs, err := irc.List(lastId)
+			imgs, err := irc.List(lastId, filter)
 			if err != nil {
 				fmt.Printf("unable to list images: %v\n\n", err)
 				continue
@@ -174,8 +522,391 @@ func main() {
 			fmt.Printf("found %d image(s)\n", len(imgs))
 			for _, img := range imgs {
 				t, _ := mongo.GetTime(img)
-				fmt.Println(img.Name, img.Owner, perm(img.Access), t.Local().Format("2006-01-02T15:04:05"), img.Id)
+				fmt.Println(img.Name, img.Owner, perm(img.Access), t.Local().Format("2006-01-02T15:04:05"), img.Id, describe(img.Metadata), labels(img.Tags), sharing(img.Grant))
+			}
+		} else if cmd == "similar" && (len(input) == 2 || len(input) == 3) {
+			dist := _DefaultDistance
+			if len(input) == 3 {
+				dist, err = strconv.Atoi(input[2])
+				if err != nil {
+					fmt.Printf("invalid distance: %v\n\n", err)
+					continue
+				}
+			}
+
+			// The query is a local file if one exists, and an image id otherwise.
+			var matches []imgrepo.Match
+			if f, ferr := os.Open(input[1]); ferr == nil {
+				matches, err = irc.Similar("", f, dist)
+				f.Close()
+			} else {
+				matches, err = irc.Similar(input[1], nil, dist)
+			}
+			if err != nil {
+				fmt.Printf("unable to search images: %v\n\n", err)
+				continue
+			}
+
+			fmt.Printf("found %d similar image(s)\n", len(matches))
+			for _, m := range matches {
+				img := m.Image
+				fmt.Println(m.Distance, img.Name, img.Owner, perm(img.Access), img.Id)
+			}
+		} else if cmd == "mv" && len(input) == 3 {
+			img, err := irc.Update(&imgrepo.Image{Id: input[1], Name: input[2]}, imgrepo.NameField)
+			if err != nil {
+				fmt.Printf("unable to rename image %s: %v\n\n", input[1], err)
+				continue
+			}
+
+			fmt.Printf("renamed image %s to %s\n", img.Id, img.Name)
+		} else if cmd == "chmod" && len(input) == 3 {
+			val, err := strconv.Atoi(input[2])
+			if err != nil {
+				fmt.Printf("invalid permission: %v\n\n", err)
+				continue
 			}
+
+			img, err := irc.Update(&imgrepo.Image{Id: input[1], Access: imgrepo.Permission(val)}, imgrepo.AccessField)
+			if err != nil {
+				fmt.Printf("unable to change access of image %s: %v\n\n", input[1], err)
+				continue
+			}
+
+			fmt.Printf("changed access of image %s to %s\n", img.Id, perm(img.Access))
+		} else if cmd == "tag" && (len(input) == 2 || len(input) == 3) {
+			// Without tags, the tags of the image are cleared.
+			var tags []string
+			if len(input) == 3 {
+				tags = strings.Split(input[2], ",")
+			}
+
+			img, err := irc.Update(&imgrepo.Image{Id: input[1], Tags: tags}, imgrepo.TagsField)
+			if err != nil {
+				fmt.Printf("unable to tag image %s: %v\n\n", input[1], err)
+				continue
+			}
+
+			fmt.Printf("tagged image %s with [%s]\n", img.Id, strings.Join(img.Tags, ","))
+		} else if (cmd == "share" || cmd == "unshare") && (len(input) == 3 || (len(input) == 4 && input[2] == "-group")) {
+			// The names are users, or groups if preceded by -group.
+			var g imgrepo.Grant
+			if len(input) == 4 {
+				g.Groups = strings.Split(input[3], ",")
+			} else {
+				g.Readers = strings.Split(input[2], ",")
+			}
+
+			var img *imgrepo.Image
+			if cmd == "share" {
+				img, err = irc.Share(input[1], g)
+			} else {
+				img, err = irc.Unshare(input[1], g)
+			}
+			if err != nil {
+				fmt.Printf("unable to %s image %s: %v\n\n", cmd, input[1], err)
+				continue
+			}
+
+			fmt.Printf("image %s is shared with [%s]\n", img.Id, grantees(img.Grant))
+		} else if cmd == "groups" && len(input) == 1 {
+			names, err := irc.Groups()
+			if err != nil {
+				fmt.Printf("unable to list groups: %v\n\n", err)
+				continue
+			}
+
+			fmt.Printf("found %d group(s)\n", len(names))
+			for _, name := range names {
+				fmt.Println(name)
+			}
+		} else if cmd == "group" && len(input) >= 3 && len(input) <= 4 {
+			name := input[2]
+			var users []string
+			if len(input) == 4 {
+				users = strings.Split(input[3], ",")
+			}
+
+			// Leaving and deleting a group return no group to print.
+			var g *imgrepo.Group
+			var done string
+			switch sub := input[1]; {
+			case sub == "new" && users == nil:
+				g, err = irc.CreateGroup(name)
+			case sub == "show" && users == nil:
+				g, err = irc.Group(name)
+			case sub == "add" && users != nil:
+				g, err = irc.AddMembers(name, users...)
+			case sub == "kick" && users != nil:
+				g, err = irc.RemoveMembers(name, users...)
+			case sub == "admin" && users != nil:
+				g, err = irc.AddAdmins(name, users...)
+			case sub == "unadmin" && users != nil:
+				g, err = irc.RemoveAdmins(name, users...)
+			case sub == "leave" && users == nil:
+				_, err = irc.RemoveMembers(name, irc.Owner)
+				done = "left"
+			case sub == "del" && users == nil:
+				err = irc.DeleteGroup(name)
+				done = "deleted"
+			default:
+				fmt.Printf("invalid command: %s\n\n", input)
+				continue
+			}
+			if err != nil {
+				fmt.Printf("unable to %s group %s: %v\n\n", _GroupActions[input[1]], name, err)
+				continue
+			}
+
+			if g != nil {
+				printGroup(g)
+			} else {
+				fmt.Printf("%s group %s\n", done, name)
+			}
+		} else if cmd == "albums" && (len(input) == 1 || (len(input) == 2 && input[1] == "-n")) {
+			if len(input) == 1 {
+				lastAlbumId = ""
+			}
+
+			albums, err := irc.Albums(lastAlbumId)
+			if err != nil {
+				fmt.Printf("unable to list albums: %v\n\n", err)
+				continue
+			}
+
+			if len(albums) > 0 {
+				lastAlbumId = albums[len(albums)-1].Id
+			}
+
+			fmt.Printf("found %d album(s)\n", len(albums))
+			for _, album := range albums {
+				printAlbum(album)
+			}
+		} else if cmd == "album" && len(input) == 3 && input[1] == "ls" ||
+			cmd == "album" && len(input) == 4 && input[1] == "ls" && input[3] == "-n" {
+			// The next page is of the same album.
+			if len(input) == 3 {
+				lastAlbumImageId = ""
+			}
+
+			imgs, err := irc.AlbumImages(input[2], lastAlbumImageId)
+			if err != nil {
+				fmt.Printf("unable to list images of album %s: %v\n\n", input[2], err)
+				continue
+			}
+
+			if len(imgs) > 0 {
+				lastAlbumImageId = imgs[len(imgs)-1].Id
+			}
+
+			fmt.Printf("found %d image(s)\n", len(imgs))
+			for _, img := range imgs {
+				t, _ := mongo.GetTime(img)
+				fmt.Println(img.Name, img.Owner, perm(img.Access), t.Local().Format("2006-01-02T15:04:05"), img.Id, describe(img.Metadata), labels(img.Tags), sharing(img.Grant))
+			}
+		} else if cmd == "album" && len(input) >= 5 && len(input) <= 6 && input[1] == "down" {
+			size := 0
+			if len(input) == 6 {
+				size, err = strconv.Atoi(input[5])
+				if err != nil || size <= 0 {
+					fmt.Printf("invalid size: %s\n\n", input[5])
+					continue
+				}
+			}
+
+			img, err := save(input[4], func(w io.Writer) (*imgrepo.Image, error) {
+				return irc.DownloadFromAlbum(input[2], input[3], size, w)
+			})
+			if err != nil {
+				fmt.Printf("%v\n\n", err)
+				continue
+			}
+			fmt.Printf("downloaded file: %s\n", img.Name)
+		} else if cmd == "album" && len(input) >= 3 && len(input) <= 5 {
+			id := input[2]
+			var names []string
+			if len(input) >= 4 {
+				names = strings.Split(input[len(input)-1], ",")
+			}
+
+			// Deleting an album returns no album to print.
+			var album *imgrepo.Album
+			switch sub := input[1]; {
+			case sub == "new" && len(input) >= 4:
+				// The album is named by id, and may be created with images.
+				var val int
+				val, err = strconv.Atoi(input[3])
+				if err != nil {
+					fmt.Printf("invalid permission: %v\n\n", err)
+					continue
+				}
+				var ids []string
+				if len(input) == 5 {
+					ids = names
+				}
+				album, err = irc.CreateAlbum(id, imgrepo.Permission(val), ids...)
+			case sub == "add" && len(input) == 4:
+				album, err = irc.AddToAlbum(id, names...)
+			case sub == "rm" && len(input) == 4:
+				album, err = irc.RemoveFromAlbum(id, names...)
+			case (sub == "share" || sub == "unshare") && (len(input) == 4 || (len(input) == 5 && input[3] == "-group")):
+				// The names are users, or groups if preceded by -group.
+				var g imgrepo.Grant
+				if len(input) == 5 {
+					g.Groups = names
+				} else {
+					g.Readers = names
+				}
+				if sub == "share" {
+					album, err = irc.ShareAlbum(id, g)
+				} else {
+					album, err = irc.UnshareAlbum(id, g)
+				}
+			case sub == "del" && len(input) == 3:
+				err = irc.DeleteAlbum(id)
+			default:
+				fmt.Printf("invalid command: %s\n\n", input)
+				continue
+			}
+			if err != nil {
+				fmt.Printf("unable to %s album %s: %v\n\n", _AlbumActions[input[1]], id, err)
+				continue
+			}
+
+			if album != nil {
+				printAlbum(album)
+			} else {
+				fmt.Printf("deleted album %s\n", id)
+			}
+		} else if cmd == "link" && len(input) >= 2 {
+			id, expires, maxDownloads, err := parseLink(input[1:])
+			if err != nil {
+				fmt.Printf("%v\n\n", err)
+				continue
+			}
+
+			var at time.Time
+			if expires > 0 {
+				at = time.Now().Add(expires)
+			}
+
+			link, err := irc.CreateShareLink(id, at, maxDownloads)
+			if err != nil {
+				fmt.Printf("unable to link image %s: %v\n\n", id, err)
+				continue
+			}
+
+			fmt.Printf("created link %s to image %s, %s\n", link.Id, link.ImageId, usage(link))
+			fmt.Printf("token: %s\n", link.Token)
+		} else if cmd == "links" && len(input) == 1 {
+			links, err := irc.ShareLinks()
+			if err != nil {
+				fmt.Printf("unable to list links: %v\n\n", err)
+				continue
+			}
+
+			fmt.Printf("found %d link(s)\n", len(links))
+			for _, link := range links {
+				fmt.Println(link.Id, link.ImageId, link.Created.Local().Format("2006-01-02T15:04:05"), usage(link), link.Token)
+			}
+		} else if cmd == "revoke" && len(input) >= 2 {
+			for _, id := range input[1:] {
+				if err := irc.RevokeShareLink(id); err != nil {
+					fmt.Printf("unable to revoke link %s: %v\n", id, err)
+					continue
+				}
+				fmt.Printf("revoked link: %s\n", id)
+			}
+		} else if cmd == "get" && (len(input) == 3 || len(input) == 4) {
+			size := 0
+			if len(input) == 4 {
+				size, err = strconv.Atoi(input[3])
+				if err != nil || size <= 0 {
+					fmt.Printf("invalid size: %s\n\n", input[3])
+					continue
+				}
+			}
+
+			img, err := save(input[2], func(w io.Writer) (*imgrepo.Image, error) {
+				return irc.DownloadShared(input[1], size, w)
+			})
+			if err != nil {
+				fmt.Printf("%v\n\n", err)
+				continue
+			}
+			fmt.Printf("downloaded file: %s\n", img.Name)
+		} else if cmd == "users" && len(input) == 1 {
+			accounts, err := irc.Users()
+			if err != nil {
+				fmt.Printf("unable to list users: %v\n\n", err)
+				continue
+			}
+
+			fmt.Printf("found %d user(s)\n", len(accounts))
+			for _, acc := range accounts {
+				if acc.Disabled {
+					fmt.Println(acc.Username, roleName(acc.Role), "disabled")
+				} else {
+					fmt.Println(acc.Username, roleName(acc.Role))
+				}
+			}
+		} else if cmd == "role" && len(input) == 3 {
+			role, ok := _Roles[input[2]]
+			if !ok {
+				fmt.Printf("invalid role: %s\n\n", input[2])
+				continue
+			}
+
+			if err := irc.SetRole(input[1], role); err != nil {
+				fmt.Printf("unable to set role of %s: %v\n\n", input[1], err)
+				continue
+			}
+			fmt.Printf("%s is now %s\n", input[1], input[2])
+		} else if (cmd == "disable" || cmd == "enable") && len(input) >= 2 {
+			for _, user := range input[1:] {
+				if err := irc.SetDisabled(user, cmd == "disable"); err != nil {
+					fmt.Printf("unable to %s user %s: %v\n", cmd, user, err)
+					continue
+				}
+				fmt.Printf("%sd user: %s\n", cmd, user)
+			}
+		} else if cmd == "purge" && len(input) >= 2 {
+			var deleted int
+			for _, id := range input[1:] {
+				if err := irc.ForceDelete(id); err != nil {
+					fmt.Printf("unable to delete image %s: %v\n", id, err)
+					continue
+				}
+
+				deleted++
+				fmt.Printf("deleted image: %s\n", id)
+			}
+
+			fmt.Printf("deleted %d image(s)\n", deleted)
+		} else if cmd == "usage" && len(input) == 1 {
+			usage, err := irc.StorageUsage()
+			if err != nil {
+				fmt.Printf("unable to view storage usage: %v\n\n", err)
+				continue
+			}
+
+			for _, u := range usage {
+				fmt.Printf("%s %d image(s) %d bytes\n", u.Username, u.Images, u.Bytes)
+			}
+		} else if cmd == "rm" && len(input) >= 2 {
+			var deleted int
+			for _, id := range input[1:] {
+				err := irc.Delete(id)
+				if err != nil {
+					fmt.Printf("unable to delete image %s: %v\n", id, err)
+					continue
+				}
+
+				deleted++
+				fmt.Printf("deleted image: %s\n", id)
+			}
+
+			fmt.Printf("deleted %d image(s)\n", deleted)
 		} else {
 			fmt.Printf("invalid command: %s\n", input)
 		}
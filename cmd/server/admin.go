@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/algao1/imgrepo"
+	pb "github.com/algao1/imgrepo/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// parseAdmins parses the comma separated usernames of the admins flag.
+func parseAdmins(names string) map[string]bool {
+	admins := make(map[string]bool)
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			admins[name] = true
+		}
+	}
+	return admins
+}
+
+// promoteAdmins gives the admin role to the registered users named by the
+// admins flag, so that a new deployment can be administered at all. Names
+// without an account are skipped, rather than promoted when they register,
+// since anyone could claim them first.
+func (s *repoServer) promoteAdmins() error {
+	for name := range s.admins {
+		ok, err := s.us.Exists(name)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to find admin", err)
+		} else if !ok {
+			log.Printf("skipped admin %s without an account", name)
+			continue
+		}
+
+		if err := s.us.SetRole(name, imgrepo.AdminRole); err != nil {
+			return fmt.Errorf("%q: %w", "unable to promote admin", err)
+		}
+		log.Printf("promoted %s to admin", name)
+	}
+
+	return nil
+}
+
+// checkNotSelf keeps admins from changing their own account, so that the
+// last admin cannot lock everyone out.
+func checkNotSelf(requester, username string) error {
+	if requester == username {
+		return fmt.Errorf("unable to change own account %s: %w", username, imgrepo.ErrPermissionDenied)
+	}
+	return nil
+}
+
+// storageUsage adds up the size of the images of every account, largest
+// first. Accounts without images are listed too.
+func storageUsage(ir imgrepo.ImageRegistry, accounts []*imgrepo.Account) ([]imgrepo.Usage, error) {
+	usage := make(map[string]*imgrepo.Usage, len(accounts))
+	for _, acc := range accounts {
+		usage[acc.Username] = &imgrepo.Usage{Username: acc.Username}
+	}
+
+	err := ir.Walk(func(img *imgrepo.Image) error {
+		u, ok := usage[img.Owner]
+		if !ok {
+			u = &imgrepo.Usage{Username: img.Owner}
+			usage[img.Owner] = u
+		}
+		u.Images++
+		u.Bytes += img.Size
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]imgrepo.Usage, 0, len(usage))
+	for _, u := range usage {
+		res = append(res, *u)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Bytes != res[j].Bytes {
+			return res[i].Bytes > res[j].Bytes
+		}
+		return res[i].Username < res[j].Username
+	})
+
+	return res, nil
+}
+
+// ListUsers lists every account, with its role.
+func (s *repoServer) ListUsers(ctx context.Context, req *emptypb.Empty) (*pb.ListUsersResponse, error) {
+	accounts, err := s.us.Accounts()
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to list users", err)
+	}
+
+	users := make([]*pb.User, len(accounts))
+	for i, acc := range accounts {
+		users[i] = &pb.User{Username: acc.Username, Role: int32(acc.Role), Disabled: acc.Disabled}
+	}
+
+	return &pb.ListUsersResponse{Users: users}, nil
+}
+
+// SetRole sets the role of an account other than the requester's.
+func (s *repoServer) SetRole(ctx context.Context, req *pb.SetRoleRequest) (*emptypb.Empty, error) {
+	requester := userFromContext(ctx)
+	if err := checkNotSelf(requester, req.Username); err != nil {
+		return nil, err
+	}
+
+	if err := s.us.SetRole(req.Username, imgrepo.Role(req.Role)); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to set role", err)
+	}
+	log.Printf("%s set the role of %s to %d", requester, req.Username, req.Role)
+
+	return new(emptypb.Empty), nil
+}
+
+// SetDisabled disables an account other than the requester's, or enables it
+// again. The sessions of a disabled account are refused by the auth
+// interceptors.
+func (s *repoServer) SetDisabled(ctx context.Context, req *pb.SetDisabledRequest) (*emptypb.Empty, error) {
+	requester := userFromContext(ctx)
+	if err := checkNotSelf(requester, req.Username); err != nil {
+		return nil, err
+	}
+
+	if err := s.us.SetDisabled(req.Username, req.Disabled); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to disable user", err)
+	}
+	log.Printf("%s set %s disabled to %v", requester, req.Username, req.Disabled)
+
+	return new(emptypb.Empty), nil
+}
+
+// ForceDeleteImage deletes any image, on behalf of its owner.
+func (s *repoServer) ForceDeleteImage(ctx context.Context, req *pb.DeleteRequest) (*emptypb.Empty, error) {
+	img, err := s.ir.Stat(req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find image", err)
+	}
+
+	if err := s.deleteImage(img.Owner, req.Id); err != nil {
+		return nil, err
+	}
+	log.Printf("%s deleted image %s of %s", userFromContext(ctx), req.Id, img.Owner)
+
+	return new(emptypb.Empty), nil
+}
+
+// StorageUsage responds with the storage used by every account.
+func (s *repoServer) StorageUsage(ctx context.Context, req *emptypb.Empty) (*pb.StorageUsageResponse, error) {
+	accounts, err := s.us.Accounts()
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to list users", err)
+	}
+
+	usage, err := storageUsage(s.ir, accounts)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to add up storage", err)
+	}
+
+	res := make([]*pb.Usage, len(usage))
+	for i, u := range usage {
+		res[i] = &pb.Usage{Username: u.Username, Images: int32(u.Images), Bytes: u.Bytes}
+	}
+
+	return &pb.StorageUsageResponse{Usage: res}, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/image"
+	"github.com/algao1/imgrepo/memory"
+	pb "github.com/algao1/imgrepo/proto"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseAdmins(t *testing.T) {
+	tests := map[string]struct {
+		names string
+		want  map[string]bool
+	}{
+		"none":   {names: "", want: map[string]bool{}},
+		"one":    {names: "root", want: map[string]bool{"root": true}},
+		"spaced": {names: " root, ops ,,", want: map[string]bool{"root": true, "ops": true}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, parseAdmins(tc.names)); diff != "" {
+				t.Errorf("parseAdmins() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPromoteAdmins(t *testing.T) {
+	s := &repoServer{
+		us:     memory.NewUserService(),
+		admins: parseAdmins("root,ops"),
+	}
+	if err := s.us.Register("root", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.promoteAdmins(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Admin names without an account are not given to whoever registers
+	// them.
+	if _, err := s.Register(context.Background(), &pb.RegisterRequest{Username: "ops", Password: "password"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]imgrepo.Role{"root": imgrepo.AdminRole, "ops": imgrepo.UserRole} {
+		acc, err := s.us.Account(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acc.Role != want {
+			t.Errorf("Account(%q).Role = %v, want %v", name, acc.Role, want)
+		}
+	}
+}
+
+func TestStorageUsage(t *testing.T) {
+	ir := memory.NewImageRegistry(memory.NewImageStorage(), memory.NewGroupService())
+
+	images := []*imgrepo.Image{
+		{Owner: "test", Access: imgrepo.Private, Digest: "a", Size: 100},
+		{Owner: "test", Access: imgrepo.Public, Digest: "b", Size: 50},
+		{Owner: "test2", Access: imgrepo.Public, Digest: "a", Size: 100},
+		{Owner: "gone", Access: imgrepo.Public, Digest: "c", Size: 500},
+	}
+	for _, img := range images {
+		if err := ir.Upload(img, bytes.NewReader(nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	accounts := []*imgrepo.Account{{Username: "test"}, {Username: "test2"}, {Username: "idle"}}
+	got, err := storageUsage(ir, accounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Shared blobs are counted for each image, and owners without accounts
+	// are listed all the same.
+	want := []imgrepo.Usage{
+		{Username: "gone", Images: 1, Bytes: 500},
+		{Username: "test", Images: 2, Bytes: 150},
+		{Username: "test2", Images: 1, Bytes: 100},
+		{Username: "idle"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("storageUsage() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestForceDeleteImage(t *testing.T) {
+	tests := map[string]struct {
+		missing bool
+		wantErr error
+	}{
+		"other owner":   {},
+		"missing image": {missing: true, wantErr: imgrepo.ErrNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &repoServer{
+				ir:  memory.NewImageRegistry(memory.NewImageStorage(), memory.NewGroupService()),
+				idx: image.NewIndex(0),
+			}
+
+			img := &imgrepo.Image{Owner: "test", Access: imgrepo.Private, Digest: "digest"}
+			if err := s.ir.Upload(img, bytes.NewReader(nil)); err != nil {
+				t.Fatal(err)
+			}
+
+			id := img.Id
+			if tc.missing {
+				id = "missing"
+			}
+
+			ctx := context.WithValue(context.Background(), userKey{}, "mod")
+			_, err := s.ForceDeleteImage(ctx, &pb.DeleteRequest{Id: id})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("ForceDeleteImage() = %v, want %v", err, tc.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			if _, err := s.ir.Stat(id); !errors.Is(err, imgrepo.ErrNotFound) {
+				t.Errorf("Stat() after ForceDeleteImage() = _, %v, want %v", err, imgrepo.ErrNotFound)
+			}
+		})
+	}
+}
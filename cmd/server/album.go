@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/algao1/imgrepo"
+	pb "github.com/algao1/imgrepo/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// toAlbum converts an album to the message sent to the requester. Like
+// images, only the owner is told who the album is shared with.
+func toAlbum(requester string, album *imgrepo.Album) *pb.Album {
+	res := &pb.Album{
+		Id:       album.Id,
+		Name:     album.Name,
+		Owner:    album.Owner,
+		Access:   int32(album.Access),
+		ImageIds: album.Images,
+	}
+	if album.Owner == requester {
+		res.Readers = album.Readers
+		res.ReaderGroups = album.Groups
+	}
+
+	return res
+}
+
+// CreateAlbum creates an album owned by the requester, holding the images
+// of the request.
+func (s *repoServer) CreateAlbum(ctx context.Context, req *pb.CreateAlbumRequest) (*pb.Album, error) {
+	access := imgrepo.Permission(req.Access)
+	if err := checkAccess(access); err != nil {
+		return nil, err
+	}
+
+	requester := userFromContext(ctx)
+	album := &imgrepo.Album{Name: req.Name, Owner: requester, Access: access, Images: req.ImageIds}
+	if err := s.ar.Create(album); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create album", err)
+	}
+	log.Printf("created album %s", album.Id)
+
+	return toAlbum(requester, album), nil
+}
+
+// ListAlbums lists the albums viewable by the requester.
+func (s *repoServer) ListAlbums(ctx context.Context, req *pb.ListAlbumsRequest) (*pb.ListAlbumsResponse, error) {
+	requester := userFromContext(ctx)
+	albums, err := s.ar.List(int(req.Size), requester, req.LastId)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to list albums", err)
+	}
+
+	res := make([]*pb.Album, len(albums))
+	for i, album := range albums {
+		res[i] = toAlbum(requester, album)
+	}
+
+	return &pb.ListAlbumsResponse{Albums: res}, nil
+}
+
+// ListAlbumImages lists the images of an album viewable by the requester,
+// whatever their own access. Their file info is redacted like that of any
+// image the requester does not own.
+func (s *repoServer) ListAlbumImages(ctx context.Context, req *pb.ListAlbumImagesRequest) (*pb.ListResponse, error) {
+	requester := userFromContext(ctx)
+	imgs, err := s.ar.Images(int(req.Size), requester, req.Id, req.LastId)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to list album images", err)
+	}
+
+	finfos := make([]*pb.FileInfo, len(imgs))
+	for i, img := range imgs {
+		finfos[i] = fileInfo(requester, img)
+	}
+
+	return &pb.ListResponse{Files: finfos}, nil
+}
+
+// AddAlbumImages adds images owned by the requester to one of their albums.
+func (s *repoServer) AddAlbumImages(ctx context.Context, req *pb.AlbumImagesRequest) (*pb.Album, error) {
+	return s.changeAlbum(ctx, req.Id, func(requester string) error {
+		return s.ar.Add(requester, req.Id, req.ImageIds...)
+	})
+}
+
+// RemoveAlbumImages removes images from an album owned by the requester.
+func (s *repoServer) RemoveAlbumImages(ctx context.Context, req *pb.AlbumImagesRequest) (*pb.Album, error) {
+	return s.changeAlbum(ctx, req.Id, func(requester string) error {
+		return s.ar.Remove(requester, req.Id, req.ImageIds...)
+	})
+}
+
+// ShareAlbum grants the users and groups read access to an album owned by
+// the requester, and so to all of its images. Only registered users and
+// existing groups may be granted access, like for images.
+func (s *repoServer) ShareAlbum(ctx context.Context, req *pb.ShareRequest) (*pb.Album, error) {
+	g, err := toGrant(userFromContext(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUsers(g.Readers); err != nil {
+		return nil, err
+	}
+	if err := s.checkGroups(g.Groups); err != nil {
+		return nil, err
+	}
+
+	return s.changeAlbum(ctx, req.Id, func(requester string) error {
+		return s.ar.Share(requester, req.Id, g)
+	})
+}
+
+// UnshareAlbum revokes the read access of the users and groups to an album
+// owned by the requester.
+func (s *repoServer) UnshareAlbum(ctx context.Context, req *pb.ShareRequest) (*pb.Album, error) {
+	g, err := toGrant(userFromContext(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.changeAlbum(ctx, req.Id, func(requester string) error {
+		return s.ar.Unshare(requester, req.Id, g)
+	})
+}
+
+// changeAlbum applies change to the album with the given id as the
+// requester, and responds with the updated album.
+func (s *repoServer) changeAlbum(ctx context.Context, id string, change func(requester string) error) (*pb.Album, error) {
+	requester := userFromContext(ctx)
+	if err := change(requester); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to change album", err)
+	}
+
+	album, err := s.ar.Find(requester, id)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find album", err)
+	}
+	log.Printf("changed album %s: %d image(s), shared with %v", album.Id, len(album.Images), album.Grant)
+
+	return toAlbum(requester, album), nil
+}
+
+// DeleteAlbum deletes an album owned by the requester, but not its images.
+func (s *repoServer) DeleteAlbum(ctx context.Context, req *pb.AlbumRequest) (*emptypb.Empty, error) {
+	if err := s.ar.Delete(userFromContext(ctx), req.Id); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to delete album", err)
+	}
+	log.Printf("deleted album %s", req.Id)
+
+	return new(emptypb.Empty), nil
+}
+
+// reader returns the user the image of a download request is read as. The
+// images of an album are read as its owner, for the requester may view them
+// through the album whatever their own access, like links are read as their
+// owner. Other images are read as the requester.
+func (s *repoServer) reader(requester string, req *pb.DownloadRequest) (string, error) {
+	if req.AlbumId == "" {
+		return requester, nil
+	}
+
+	album, err := s.ar.Find(requester, req.AlbumId)
+	if err != nil {
+		return "", fmt.Errorf("%q: %w", "unable to find album", err)
+	}
+
+	if !contains(album.Images, req.Id) {
+		return "", fmt.Errorf("file %s in album %s: %w", req.Id, album.Id, imgrepo.ErrNotFound)
+	}
+
+	// Albums only list the images of their owner, and so only grant access
+	// to those.
+	img, err := s.ir.Stat(req.Id)
+	if err != nil {
+		return "", fmt.Errorf("%q: %w", "unable to find image", err)
+	}
+	if img.Owner != album.Owner {
+		return "", fmt.Errorf("file %s in album %s: %w", req.Id, album.Id, imgrepo.ErrNotFound)
+	}
+
+	return album.Owner, nil
+}
+
+// contains reports whether the ids hold id.
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/image"
+	"github.com/algao1/imgrepo/memory"
+	pb "github.com/algao1/imgrepo/proto"
+)
+
+// tmpAlbumServer returns a server holding a private image of "test", taken
+// at a location and shared with "test3", in a public and a private album,
+// and another image of "test" in neither.
+func tmpAlbumServer(t *testing.T) (s *repoServer, public, private *pb.Album, inAlbum, outside *imgrepo.Image, raw []byte) {
+	raw, err := os.ReadFile("../../_data/apple1.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ir := memory.NewImageRegistry(memory.NewImageStorage(), memory.NewGroupService())
+	s = &repoServer{
+		ir:    ir,
+		ar:    memory.NewAlbumRegistry(ir),
+		tf:    image.NewTransformer(),
+		cache: memory.NewImageCache(1 << 20),
+	}
+
+	loc := &imgrepo.Location{Latitude: 48.858333, Longitude: -122.42}
+	inAlbum = &imgrepo.Image{Name: "a.jpg", Owner: "test", Access: imgrepo.Private, Digest: "a", Metadata: imgrepo.Metadata{Location: loc}}
+	outside = &imgrepo.Image{Name: "b.jpg", Owner: "test", Access: imgrepo.Private, Digest: "b"}
+	for _, img := range []*imgrepo.Image{inAlbum, outside} {
+		if err := s.ir.Upload(img, bytes.NewReader(raw)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inAlbum, err = s.ir.Share("test", inAlbum.Id, imgrepo.Grant{Readers: []string{"test3"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), userKey{}, "test")
+	for _, access := range []imgrepo.Permission{imgrepo.Public, imgrepo.Private} {
+		album, err := s.CreateAlbum(ctx, &pb.CreateAlbumRequest{Name: "album", Access: int32(access), ImageIds: []string{inAlbum.Id}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if access == imgrepo.Public {
+			public = album
+		} else {
+			private = album
+		}
+	}
+
+	return s, public, private, inAlbum, outside, raw
+}
+
+func TestListAlbumImages(t *testing.T) {
+	s, public, _, img, _, _ := tmpAlbumServer(t)
+
+	tests := map[string]struct {
+		requester string
+		redacted  bool
+	}{
+		"owner":  {requester: "test"},
+		"viewer": {requester: "test2", redacted: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), userKey{}, tc.requester)
+			got, err := s.ListAlbumImages(ctx, &pb.ListAlbumImagesRequest{Id: public.Id})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Files) != 1 || got.Files[0].Id != img.Id {
+				t.Fatalf("ListAlbumImages() = %v, want %s", got.Files, img.Id)
+			}
+
+			// The album shows the image, but not who it is shared with, or
+			// where it was taken.
+			finfo := got.Files[0]
+			if redacted := finfo.Metadata.Location == nil && finfo.Readers == nil; redacted != tc.redacted {
+				t.Errorf("ListAlbumImages() location = %v, readers = %v, want redacted %v", finfo.Metadata.Location, finfo.Readers, tc.redacted)
+			}
+		})
+	}
+}
+
+func TestDownloadAlbumImage(t *testing.T) {
+	s, public, private, inAlbum, outside, raw := tmpAlbumServer(t)
+
+	tests := map[string]struct {
+		requester string
+		req       *pb.DownloadRequest
+		wantErr   error
+	}{
+		"owner":            {requester: "test", req: &pb.DownloadRequest{Id: inAlbum.Id, AlbumId: private.Id}},
+		"viewer":           {requester: "test2", req: &pb.DownloadRequest{Id: inAlbum.Id, AlbumId: public.Id}},
+		"viewer transform": {requester: "test2", req: &pb.DownloadRequest{Id: inAlbum.Id, AlbumId: public.Id, Transform: &pb.Transform{Width: 32}}},
+		"without album":    {requester: "test2", req: &pb.DownloadRequest{Id: inAlbum.Id}, wantErr: imgrepo.ErrPermissionDenied},
+		"private album":    {requester: "test2", req: &pb.DownloadRequest{Id: inAlbum.Id, AlbumId: private.Id}, wantErr: imgrepo.ErrPermissionDenied},
+		"not in album":     {requester: "test2", req: &pb.DownloadRequest{Id: outside.Id, AlbumId: public.Id}, wantErr: imgrepo.ErrNotFound},
+		"missing album":    {requester: "test2", req: &pb.DownloadRequest{Id: inAlbum.Id, AlbumId: "missing"}, wantErr: imgrepo.ErrNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			stream := &downloadStream{ctx: context.WithValue(context.Background(), userKey{}, tc.requester)}
+			err := s.DownloadImage(tc.req, stream)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("DownloadImage() = %v, want %v", err, tc.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			if tc.req.Transform == nil && !bytes.Equal(stream.data, raw) {
+				t.Error("DownloadImage() differs from the uploaded image")
+			}
+
+			// Only the owner is sent the location, and readers.
+			owner := tc.requester == inAlbum.Owner
+			if got := stream.info.Metadata.Location != nil; got != owner {
+				t.Errorf("DownloadImage() sent location = %v, want %v", got, owner)
+			}
+			if got := stream.info.Readers != nil; got != owner {
+				t.Errorf("DownloadImage() sent readers = %v, want %v", got, owner)
+			}
+		})
+	}
+}
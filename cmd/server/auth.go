@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/algao1/imgrepo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// _PublicMethods lists the RPCs that can be called without a session.
+var _PublicMethods = map[string]bool{
+	"/proto.Repo/Register":       true,
+	"/proto.Repo/Login":          true,
+	"/proto.Repo/DownloadShared": true,
+}
+
+// _MethodRoles maps the other RPCs to the least role allowed to call them.
+// RPCs missing from both are refused, so that new RPCs must be given a role.
+var _MethodRoles = map[string]imgrepo.Role{
+	"/proto.Repo/UploadImage":        imgrepo.UserRole,
+	"/proto.Repo/BeginUpload":        imgrepo.UserRole,
+	"/proto.Repo/WriteUpload":        imgrepo.UserRole,
+	"/proto.Repo/QueryUpload":        imgrepo.UserRole,
+	"/proto.Repo/CommitUpload":       imgrepo.UserRole,
+	"/proto.Repo/DownloadImage":      imgrepo.UserRole,
+	"/proto.Repo/ListImages":         imgrepo.UserRole,
+	"/proto.Repo/UpdateImage":        imgrepo.UserRole,
+	"/proto.Repo/ShareImage":         imgrepo.UserRole,
+	"/proto.Repo/UnshareImage":       imgrepo.UserRole,
+	"/proto.Repo/CreateGroup":        imgrepo.UserRole,
+	"/proto.Repo/GetGroup":           imgrepo.UserRole,
+	"/proto.Repo/ListGroups":         imgrepo.UserRole,
+	"/proto.Repo/AddGroupMembers":    imgrepo.UserRole,
+	"/proto.Repo/RemoveGroupMembers": imgrepo.UserRole,
+	"/proto.Repo/AddGroupAdmins":     imgrepo.UserRole,
+	"/proto.Repo/RemoveGroupAdmins":  imgrepo.UserRole,
+	"/proto.Repo/DeleteGroup":        imgrepo.UserRole,
+	"/proto.Repo/CreateAlbum":        imgrepo.UserRole,
+	"/proto.Repo/ListAlbums":         imgrepo.UserRole,
+	"/proto.Repo/ListAlbumImages":    imgrepo.UserRole,
+	"/proto.Repo/AddAlbumImages":     imgrepo.UserRole,
+	"/proto.Repo/RemoveAlbumImages":  imgrepo.UserRole,
+	"/proto.Repo/ShareAlbum":         imgrepo.UserRole,
+	"/proto.Repo/UnshareAlbum":       imgrepo.UserRole,
+	"/proto.Repo/DeleteAlbum":        imgrepo.UserRole,
+	"/proto.Repo/CreateShareLink":    imgrepo.UserRole,
+	"/proto.Repo/ListShareLinks":     imgrepo.UserRole,
+	"/proto.Repo/RevokeShareLink":    imgrepo.UserRole,
+	"/proto.Repo/DeleteImage":        imgrepo.UserRole,
+	"/proto.Repo/SearchSimilar":      imgrepo.UserRole,
+
+	"/proto.Admin/ListUsers":        imgrepo.ModeratorRole,
+	"/proto.Admin/ForceDeleteImage": imgrepo.ModeratorRole,
+	"/proto.Admin/SetRole":          imgrepo.AdminRole,
+	"/proto.Admin/SetDisabled":      imgrepo.AdminRole,
+	"/proto.Admin/StorageUsage":     imgrepo.AdminRole,
+}
+
+type userKey struct{}
+
+// userFromContext returns the user resolved by the auth interceptors.
+func userFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(userKey{}).(string)
+	return user
+}
+
+// authenticate reads the bearer token from the incoming metadata, and
+// returns a context carrying the user the session belongs to.
+func (s *repoServer) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	auth := md.Get("authorization")
+	if len(auth) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+
+	token := strings.TrimPrefix(auth[0], "Bearer ")
+	if token == auth[0] {
+		return nil, status.Error(codes.Unauthenticated, "authorization is not a bearer token")
+	}
+
+	user, err := s.ss.IsSession(token)
+	if errors.Is(err, imgrepo.ErrUnauthenticated) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid session: %v", err)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to check session", err)
+	}
+
+	return context.WithValue(ctx, userKey{}, user), nil
+}
+
+// authorize checks that the account of the user is enabled, and has the role
+// the method requires. The account is looked up on every call, so that
+// disabling it, or changing its role, applies to its open sessions.
+func (s *repoServer) authorize(user, method string) error {
+	acc, err := s.us.Account(user)
+	if errors.Is(err, imgrepo.ErrNotFound) {
+		return status.Errorf(codes.Unauthenticated, "invalid session: %v", err)
+	} else if err != nil {
+		return fmt.Errorf("%q: %w", "unable to find account", err)
+	}
+
+	if acc.Disabled {
+		return status.Errorf(codes.PermissionDenied, "account %s is disabled", user)
+	}
+	if role, ok := _MethodRoles[method]; !ok || acc.Role < role {
+		return status.Errorf(codes.PermissionDenied, "account %s may not call %s", user, method)
+	}
+
+	return nil
+}
+
+// unaryAuth authenticates and authorizes unary RPCs, except those in
+// _PublicMethods.
+func (s *repoServer) unaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if _PublicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	ctx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(userFromContext(ctx), info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// authStream overrides the context of a grpc.ServerStream.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (as *authStream) Context() context.Context {
+	return as.ctx
+}
+
+// streamAuth authenticates and authorizes streaming RPCs, except those in
+// _PublicMethods.
+func (s *repoServer) streamAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if _PublicMethods[info.FullMethod] {
+		return handler(srv, ss)
+	}
+
+	ctx, err := s.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	if err := s.authorize(userFromContext(ctx), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/memory"
+	pb "github.com/algao1/imgrepo/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAuth(t *testing.T) {
+	ss := memory.NewSessionService()
+	us := memory.NewUserService()
+	s := &repoServer{ss: ss, us: us}
+
+	tokens := make(map[string]string)
+	for _, user := range []string{"test", "mod", "off"} {
+		if err := us.Register(user, "password"); err != nil {
+			t.Fatal(err)
+		}
+
+		token, err := ss.NewSession(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens[user] = token
+	}
+	if err := us.SetRole("mod", imgrepo.ModeratorRole); err != nil {
+		t.Fatal(err)
+	}
+	if err := us.SetDisabled("off", true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		method   string
+		md       metadata.MD
+		want     string
+		wantCode codes.Code
+	}{
+		"valid token": {
+			method: "/proto.Repo/ListImages",
+			md:     metadata.Pairs("authorization", "Bearer "+tokens["test"]),
+			want:   "test",
+		},
+		"missing token": {
+			method:   "/proto.Repo/ListImages",
+			md:       metadata.MD{},
+			wantCode: codes.Unauthenticated,
+		},
+		"not a bearer token": {
+			method:   "/proto.Repo/ListImages",
+			md:       metadata.Pairs("authorization", tokens["test"]),
+			wantCode: codes.Unauthenticated,
+		},
+		"unknown session": {
+			method:   "/proto.Repo/ListImages",
+			md:       metadata.Pairs("authorization", "Bearer unknown"),
+			wantCode: codes.Unauthenticated,
+		},
+		"public method": {
+			method: "/proto.Repo/Login",
+			md:     metadata.MD{},
+			want:   "",
+		},
+		"disabled account": {
+			method:   "/proto.Repo/ListImages",
+			md:       metadata.Pairs("authorization", "Bearer "+tokens["off"]),
+			wantCode: codes.PermissionDenied,
+		},
+		"moderator method": {
+			method: "/proto.Admin/ListUsers",
+			md:     metadata.Pairs("authorization", "Bearer "+tokens["mod"]),
+			want:   "mod",
+		},
+		"moderator method as user": {
+			method:   "/proto.Admin/ListUsers",
+			md:       metadata.Pairs("authorization", "Bearer "+tokens["test"]),
+			wantCode: codes.PermissionDenied,
+		},
+		"admin method as moderator": {
+			method:   "/proto.Admin/SetRole",
+			md:       metadata.Pairs("authorization", "Bearer "+tokens["mod"]),
+			wantCode: codes.PermissionDenied,
+		},
+		"method without role": {
+			method:   "/proto.Repo/Unknown",
+			md:       metadata.Pairs("authorization", "Bearer "+tokens["mod"]),
+			wantCode: codes.PermissionDenied,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+			info := &grpc.UnaryServerInfo{FullMethod: tc.method}
+
+			var got string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				got = userFromContext(ctx)
+				return nil, nil
+			}
+
+			_, err := s.unaryAuth(ctx, nil, info, handler)
+			if status.Code(err) != tc.wantCode {
+				t.Fatalf("unaryAuth() = %v, want code %v", err, tc.wantCode)
+			} else if err != nil {
+				return
+			}
+			if got != tc.want {
+				t.Fatalf("userFromContext() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMethodRoles(t *testing.T) {
+	for _, sd := range []grpc.ServiceDesc{pb.Repo_ServiceDesc, pb.Admin_ServiceDesc} {
+		var methods []string
+		for _, m := range sd.Methods {
+			methods = append(methods, m.MethodName)
+		}
+		for _, m := range sd.Streams {
+			methods = append(methods, m.StreamName)
+		}
+
+		for _, m := range methods {
+			method := "/" + sd.ServiceName + "/" + m
+			if _, ok := _MethodRoles[method]; ok == _PublicMethods[method] {
+				t.Errorf("%s must be either public or given a role", method)
+			}
+		}
+	}
+}
+
+// brokenSessions fails every session lookup, like an unreachable store.
+type brokenSessions struct {
+	imgrepo.SessionService
+}
+
+func (brokenSessions) IsSession(uuid string) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func TestAuthenticateSessionError(t *testing.T) {
+	s := &repoServer{ss: brokenSessions{}}
+
+	md := metadata.Pairs("authorization", "Bearer token")
+	_, err := s.authenticate(metadata.NewIncomingContext(context.Background(), md))
+	if status.Code(toStatus(err)) != codes.Internal {
+		t.Fatalf("authenticate() = %v, want code %v", err, codes.Internal)
+	}
+}
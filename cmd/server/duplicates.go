@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/algao1/imgrepo"
+)
+
+// duplicates returns the ids of the images owned by the owner of img whose
+// hash is within the duplicate distance of its hash, closest first.
+func (s *repoServer) duplicates(img *imgrepo.Image) ([]string, error) {
+	matches, err := s.idx.Search(img, s.dupDist)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to search images", err)
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.Image.Id
+	}
+
+	imgs, err := s.ir.Find(img.Owner, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find images", err)
+	}
+
+	var dups []string
+	for _, dup := range imgs {
+		if dup.Owner == img.Owner {
+			dups = append(dups, dup.Id)
+		}
+	}
+
+	return dups, nil
+}
+
+// checkDuplicates applies the policy to the upload of img. It returns the
+// likely duplicates of img, and whether the upload should be skipped.
+func (s *repoServer) checkDuplicates(img *imgrepo.Image, policy imgrepo.DuplicatePolicy) ([]string, bool, error) {
+	switch policy {
+	case imgrepo.AllowDuplicates, imgrepo.SkipDuplicates, imgrepo.RejectDuplicates:
+	default:
+		return nil, false, fmt.Errorf("unknown duplicate policy %d: %w", policy, imgrepo.ErrInvalidArgument)
+	}
+
+	dups, err := s.duplicates(img)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(dups) == 0 {
+		return nil, false, nil
+	}
+
+	switch policy {
+	case imgrepo.SkipDuplicates:
+		return dups, true, nil
+	case imgrepo.RejectDuplicates:
+		return nil, false, fmt.Errorf("duplicate of %s: %w", strings.Join(dups, ", "), imgrepo.ErrAlreadyExists)
+	default:
+		return dups, false, nil
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/image"
+	"github.com/algao1/imgrepo/memory"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckDuplicates(t *testing.T) {
+	s := &repoServer{
+		ir:      memory.NewImageRegistry(memory.NewImageStorage(), memory.NewGroupService()),
+		idx:     image.NewIndex(image.PerceptualHash),
+		dupDist: 2,
+	}
+
+	// Only the image owned by the uploader, within the distance, counts.
+	images := []*imgrepo.Image{
+		{Owner: "test", Hash: 0x0},
+		{Owner: "test", Hash: 0xFF},
+		{Owner: "test2", Hash: 0x1},
+	}
+	for i, img := range images {
+		img.Kind = image.PerceptualHash
+		img.Digest = string(rune('a' + i))
+		if err := s.ir.Upload(img, bytes.NewReader(nil)); err != nil {
+			t.Fatal(err)
+		}
+		s.indexImage(img)
+	}
+
+	tests := map[string]struct {
+		hash     uint64
+		policy   imgrepo.DuplicatePolicy
+		wantDups []string
+		wantSkip bool
+		wantErr  error
+	}{
+		"allow":          {hash: 0x3, policy: imgrepo.AllowDuplicates, wantDups: []string{images[0].Id}},
+		"skip":           {hash: 0x3, policy: imgrepo.SkipDuplicates, wantDups: []string{images[0].Id}, wantSkip: true},
+		"reject":         {hash: 0x3, policy: imgrepo.RejectDuplicates, wantErr: imgrepo.ErrAlreadyExists},
+		"skip unique":    {hash: 0xF000, policy: imgrepo.SkipDuplicates},
+		"reject unique":  {hash: 0xF000, policy: imgrepo.RejectDuplicates},
+		"unknown policy": {hash: 0x3, policy: 42, wantErr: imgrepo.ErrInvalidArgument},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := &imgrepo.Image{Owner: "test", Hash: tc.hash, Kind: image.PerceptualHash}
+
+			dups, skip, err := s.checkDuplicates(img, tc.policy)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("checkDuplicates() = _, _, %v, want %v", err, tc.wantErr)
+			}
+			if skip != tc.wantSkip {
+				t.Fatalf("checkDuplicates() skip = %v, want %v", skip, tc.wantSkip)
+			}
+			if diff := cmp.Diff(tc.wantDups, dups); diff != "" {
+				t.Fatalf("checkDuplicates() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/algao1/imgrepo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// _ErrorCodes maps the imgrepo sentinel errors to gRPC status codes.
+var _ErrorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{imgrepo.ErrNotFound, codes.NotFound},
+	{imgrepo.ErrPermissionDenied, codes.PermissionDenied},
+	{imgrepo.ErrAlreadyExists, codes.AlreadyExists},
+	{imgrepo.ErrUnauthenticated, codes.Unauthenticated},
+	{imgrepo.ErrInvalidArgument, codes.InvalidArgument},
+	{imgrepo.ErrDigestMismatch, codes.DataLoss},
+	{imgrepo.ErrTooLarge, codes.OutOfRange},
+	{imgrepo.ErrExpired, codes.FailedPrecondition},
+	{imgrepo.ErrConflict, codes.Aborted},
+	{context.DeadlineExceeded, codes.DeadlineExceeded},
+	{context.Canceled, codes.Canceled},
+}
+
+// toStatus converts an error returned by a handler into a gRPC status error.
+// Errors that carry a status, even wrapped, are reported with that status,
+// and errors that match no sentinel are reported as codes.Internal.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// status.FromError does not unwrap, so the status of errors received
+	// from a stream would be lost behind the context added to them.
+	var se interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &se) {
+		return se.GRPCStatus().Err()
+	}
+
+	for _, ec := range _ErrorCodes {
+		if errors.Is(err, ec.err) {
+			return status.Error(ec.code, err.Error())
+		}
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// unaryStatus translates the errors returned by unary handlers.
+func unaryStatus(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, toStatus(err)
+}
+
+// streamStatus translates the errors returned by streaming handlers.
+func streamStatus(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return toStatus(handler(srv, ss))
+}
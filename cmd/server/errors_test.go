@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want codes.Code
+	}{
+		"nil":               {err: nil, want: codes.OK},
+		"not found":         {err: fmt.Errorf("file x: %w", imgrepo.ErrNotFound), want: codes.NotFound},
+		"permission denied": {err: fmt.Errorf("file x: %w", imgrepo.ErrPermissionDenied), want: codes.PermissionDenied},
+		"already exists":    {err: fmt.Errorf("user x: %w", imgrepo.ErrAlreadyExists), want: codes.AlreadyExists},
+		"unauthenticated":   {err: imgrepo.ErrUnauthenticated, want: codes.Unauthenticated},
+		"invalid argument":  {err: fmt.Errorf("a: %w", fmt.Errorf("b: %w", imgrepo.ErrInvalidArgument)), want: codes.InvalidArgument},
+		"digest mismatch":   {err: fmt.Errorf("image x: %w", imgrepo.ErrDigestMismatch), want: codes.DataLoss},
+		"too large":         {err: fmt.Errorf("image x: %w", imgrepo.ErrTooLarge), want: codes.OutOfRange},
+		"expired":           {err: fmt.Errorf("link x: %w", imgrepo.ErrExpired), want: codes.FailedPrecondition},
+		"conflict":          {err: fmt.Errorf("file x: %w", imgrepo.ErrConflict), want: codes.Aborted},
+		"status":            {err: status.Error(codes.Unavailable, "down"), want: codes.Unavailable},
+		"wrapped status":    {err: fmt.Errorf("upload x committed up to 0: %w", status.Error(codes.Canceled, "canceled")), want: codes.Canceled},
+		"wrapped deadline":  {err: fmt.Errorf("a: %w", fmt.Errorf("b: %w", status.Error(codes.DeadlineExceeded, "late"))), want: codes.DeadlineExceeded},
+		"unknown":           {err: errors.New("boom"), want: codes.Internal},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := status.Code(toStatus(tc.err)); got != tc.want {
+				t.Fatalf("toStatus() code = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
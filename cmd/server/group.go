@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/algao1/imgrepo"
+	pb "github.com/algao1/imgrepo/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// _MaxGroupLen is the longest a group name may be, in bytes.
+const _MaxGroupLen = 64
+
+// toGroup converts a group to its message.
+func toGroup(g *imgrepo.Group) *pb.Group {
+	return &pb.Group{Name: g.Name, Admins: g.Admins, Members: g.Members}
+}
+
+// checkGroupName checks the name of a new group, which may not hold spaces
+// or commas, which separate names in the client.
+func checkGroupName(name string) error {
+	if name == "" || len(name) > _MaxGroupLen || strings.ContainsRune(name, ',') || strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("invalid group name %q: %w", name, imgrepo.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// CreateGroup creates a group administered by the requester.
+func (s *repoServer) CreateGroup(ctx context.Context, req *pb.GroupRequest) (*pb.Group, error) {
+	if err := checkGroupName(req.Name); err != nil {
+		return nil, err
+	}
+
+	g, err := s.gs.Create(userFromContext(ctx), req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create group", err)
+	}
+	log.Printf("created group %s", g.Name)
+
+	return toGroup(g), nil
+}
+
+// GetGroup responds with a group the requester is a member of.
+func (s *repoServer) GetGroup(ctx context.Context, req *pb.GroupRequest) (*pb.Group, error) {
+	g, err := s.gs.Find(userFromContext(ctx), req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find group", err)
+	}
+
+	return toGroup(g), nil
+}
+
+// ListGroups responds with the names of the groups of the requester.
+func (s *repoServer) ListGroups(ctx context.Context, req *emptypb.Empty) (*pb.ListGroupsResponse, error) {
+	names, err := s.gs.Groups(userFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to list groups", err)
+	}
+
+	return &pb.ListGroupsResponse{Names: names}, nil
+}
+
+// AddGroupMembers adds registered users to a group.
+func (s *repoServer) AddGroupMembers(ctx context.Context, req *pb.GroupMembersRequest) (*pb.Group, error) {
+	return s.changeGroup(ctx, req, true, s.gs.AddMembers)
+}
+
+// RemoveGroupMembers removes users from a group.
+func (s *repoServer) RemoveGroupMembers(ctx context.Context, req *pb.GroupMembersRequest) (*pb.Group, error) {
+	return s.changeGroup(ctx, req, false, s.gs.RemoveMembers)
+}
+
+// AddGroupAdmins makes registered users admins of a group.
+func (s *repoServer) AddGroupAdmins(ctx context.Context, req *pb.GroupMembersRequest) (*pb.Group, error) {
+	return s.changeGroup(ctx, req, true, s.gs.AddAdmins)
+}
+
+// RemoveGroupAdmins makes admins of a group members only.
+func (s *repoServer) RemoveGroupAdmins(ctx context.Context, req *pb.GroupMembersRequest) (*pb.Group, error) {
+	return s.changeGroup(ctx, req, false, s.gs.RemoveAdmins)
+}
+
+// changeGroup applies change to the group with the users of the request,
+// which must be registered if added.
+func (s *repoServer) changeGroup(ctx context.Context, req *pb.GroupMembersRequest, adding bool,
+	change func(requester, name string, users ...string) (*imgrepo.Group, error)) (*pb.Group, error) {
+	users, err := toNames(req.Users)
+	if err != nil {
+		return nil, err
+	}
+	if adding {
+		if err := s.checkUsers(users); err != nil {
+			return nil, err
+		}
+	}
+
+	g, err := change(userFromContext(ctx), req.Name, users...)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to change group", err)
+	}
+	log.Printf("changed group %s: admins %v, members %v", g.Name, g.Admins, g.Members)
+
+	return toGroup(g), nil
+}
+
+// DeleteGroup deletes a group administered by the requester. Images shared
+// with the group are no longer viewable by its members.
+func (s *repoServer) DeleteGroup(ctx context.Context, req *pb.GroupRequest) (*emptypb.Empty, error) {
+	err := s.gs.Delete(userFromContext(ctx), req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to delete group", err)
+	}
+	log.Printf("deleted group %s", req.Name)
+
+	return new(emptypb.Empty), nil
+}
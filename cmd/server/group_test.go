@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+)
+
+func TestCheckGroupName(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		wantErr error
+	}{
+		"valid":    {name: "team-1"},
+		"empty":    {name: "", wantErr: imgrepo.ErrInvalidArgument},
+		"space":    {name: "my team", wantErr: imgrepo.ErrInvalidArgument},
+		"tab":      {name: "my\tteam", wantErr: imgrepo.ErrInvalidArgument},
+		"comma":    {name: "a,b", wantErr: imgrepo.ErrInvalidArgument},
+		"longest":  {name: strings.Repeat("a", _MaxGroupLen)},
+		"too long": {name: strings.Repeat("a", _MaxGroupLen+1), wantErr: imgrepo.ErrInvalidArgument},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := checkGroupName(tc.name); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("checkGroupName() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
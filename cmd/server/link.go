@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	pb "github.com/algao1/imgrepo/proto"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// _MinLinkKeyLen is the shortest key share link tokens may be signed with.
+const _MinLinkKeyLen = 32
+
+// newLinkKey returns the key share link tokens are signed with, read from
+// LINK_KEY, or random if the links are kept in memory, since they do not
+// outlive the server then.
+func newLinkKey(inMemory bool, env string) ([]byte, error) {
+	if inMemory && env == "" {
+		key := make([]byte, _MinLinkKeyLen)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to generate link key", err)
+		}
+		return key, nil
+	}
+
+	if len(env) < _MinLinkKeyLen {
+		return nil, fmt.Errorf("LINK_KEY must be at least %d bytes", _MinLinkKeyLen)
+	}
+	return []byte(env), nil
+}
+
+// signLink returns the token of the link with the given id, which is the id
+// followed by its HMAC-SHA256 under the key.
+func signLink(key []byte, id string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+	return id + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyLink returns the id of the link the token was signed for. Tokens
+// that were not signed with the key are reported as missing links.
+func verifyLink(key []byte, token string) (string, error) {
+	idx := strings.LastIndexByte(token, '.')
+	if idx < 0 || !hmac.Equal([]byte(signLink(key, token[:idx])), []byte(token)) {
+		return "", fmt.Errorf("%q: %w", "invalid share link", imgrepo.ErrNotFound)
+	}
+	return token[:idx], nil
+}
+
+// toShareLink converts a link to its message, with its token.
+func toShareLink(key []byte, link *imgrepo.ShareLink) *pb.ShareLink {
+	res := &pb.ShareLink{
+		Id:           link.Id,
+		Token:        signLink(key, link.Id),
+		ImageId:      link.ImageId,
+		Created:      link.Created.Unix(),
+		MaxDownloads: int32(link.MaxDownloads),
+		Downloads:    int32(link.Downloads),
+	}
+	if !link.Expires.IsZero() {
+		res.Expires = link.Expires.Unix()
+	}
+
+	return res
+}
+
+// CreateShareLink creates a link to an image owned by the requester.
+func (s *repoServer) CreateShareLink(ctx context.Context, req *pb.CreateShareLinkRequest) (*pb.ShareLink, error) {
+	requester := userFromContext(ctx)
+
+	link := &imgrepo.ShareLink{
+		Id:           uuid.NewString(),
+		ImageId:      req.Id,
+		Owner:        requester,
+		MaxDownloads: int(req.MaxDownloads),
+	}
+	if req.Expires != 0 {
+		link.Expires = time.Unix(req.Expires, 0).UTC()
+		if !link.Expires.After(time.Now()) {
+			return nil, fmt.Errorf("link expires in the past: %w", imgrepo.ErrInvalidArgument)
+		}
+	}
+	if link.MaxDownloads < 0 {
+		return nil, fmt.Errorf("negative maximum downloads %d: %w", link.MaxDownloads, imgrepo.ErrInvalidArgument)
+	}
+
+	imgs, err := s.ir.Find(requester, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find image", err)
+	} else if len(imgs) == 0 {
+		return nil, fmt.Errorf("file %s: %w", req.Id, imgrepo.ErrNotFound)
+	} else if imgs[0].Owner != requester {
+		return nil, fmt.Errorf("unable to link file %s: %w", req.Id, imgrepo.ErrPermissionDenied)
+	}
+
+	if err := s.lr.Create(link); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create link", err)
+	}
+	log.Printf("created link %s to image %s", link.Id, link.ImageId)
+
+	return toShareLink(s.linkKey, link), nil
+}
+
+// ListShareLinks lists the links created by the requester, newest first.
+func (s *repoServer) ListShareLinks(ctx context.Context, req *emptypb.Empty) (*pb.ListShareLinksResponse, error) {
+	links, err := s.lr.List(userFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to list links", err)
+	}
+
+	res := make([]*pb.ShareLink, len(links))
+	for i, link := range links {
+		res[i] = toShareLink(s.linkKey, link)
+	}
+
+	return &pb.ListShareLinksResponse{Links: res}, nil
+}
+
+// RevokeShareLink deletes a link created by the requester.
+func (s *repoServer) RevokeShareLink(ctx context.Context, req *pb.RevokeShareLinkRequest) (*emptypb.Empty, error) {
+	if err := s.lr.Revoke(userFromContext(ctx), req.Id); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to revoke link", err)
+	}
+	log.Printf("revoked link %s", req.Id)
+
+	return new(emptypb.Empty), nil
+}
+
+// DownloadShared streams the image of a link to anyone holding its token,
+// without a session. The image is opened before the download is counted,
+// so that a missing rendition does not use the link up.
+func (s *repoServer) DownloadShared(req *pb.DownloadSharedRequest, stream pb.Repo_DownloadSharedServer) error {
+	id, err := verifyLink(s.linkKey, req.Token)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.lr.Find(id)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to find link", err)
+	}
+
+	// Links act for their owner, so they stop working with their account.
+	acc, err := s.us.Account(link.Owner)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to find link owner", err)
+	}
+	if acc.Disabled {
+		return fmt.Errorf("account %s is disabled: %w", link.Owner, imgrepo.ErrPermissionDenied)
+	}
+
+	// The image is read as its owner, who may view it whatever its access.
+	var image *imgrepo.Image
+	var rc io.ReadCloser
+	if req.Rendition != 0 {
+		image, rc, err = s.ir.Rendition(link.Owner, link.ImageId, int(req.Rendition))
+	} else {
+		image, rc, err = s.ir.Download(link.Owner, link.ImageId)
+	}
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to download raw image", err)
+	}
+	defer rc.Close()
+
+	if link, err = s.lr.Redeem(id); err != nil {
+		return fmt.Errorf("%q: %w", "unable to redeem link", err)
+	}
+	log.Printf("redeemed link %s to image %s, %d download(s)", link.Id, link.ImageId, link.Downloads)
+
+	// Link holders have no account, so they are never the owner.
+	info := fileInfo("", image)
+	if req.Rendition != 0 {
+		info.FileName = renditionName(image.Name, int(req.Rendition))
+	}
+
+	return sendFile(stream, info, rc)
+}
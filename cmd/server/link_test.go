@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/memory"
+	pb "github.com/algao1/imgrepo/proto"
+	"google.golang.org/grpc"
+)
+
+func TestVerifyLink(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	token := signLink(key, "link")
+
+	tests := map[string]struct {
+		token   string
+		wantErr error
+	}{
+		"valid":        {token: token},
+		"other key":    {token: signLink([]byte("fedcba9876543210fedcba9876543210"), "link"), wantErr: imgrepo.ErrNotFound},
+		"other id":     {token: "link2" + token[len("link"):], wantErr: imgrepo.ErrNotFound},
+		"truncated":    {token: token[:len(token)-1], wantErr: imgrepo.ErrNotFound},
+		"no signature": {token: "link", wantErr: imgrepo.ErrNotFound},
+		"empty":        {token: "", wantErr: imgrepo.ErrNotFound},
+		"id with dot":  {token: signLink(key, "link.x")},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			id, err := verifyLink(key, tc.token)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("verifyLink() = _, %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && signLink(key, id) != tc.token {
+				t.Errorf("verifyLink() = %s, not the id signed", id)
+			}
+		})
+	}
+}
+
+func TestNewLinkKey(t *testing.T) {
+	tests := map[string]struct {
+		inMemory  bool
+		env       string
+		expectErr bool
+	}{
+		"memory":       {inMemory: true},
+		"memory set":   {inMemory: true, env: "0123456789abcdef0123456789abcdef"},
+		"memory short": {inMemory: true, env: "short", expectErr: true},
+		"set":          {env: "0123456789abcdef0123456789abcdef"},
+		"unset":        {expectErr: true},
+		"short":        {env: "short", expectErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, err := newLinkKey(tc.inMemory, tc.env)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("newLinkKey() = _, %v, want error %v", err, tc.expectErr)
+			}
+			if err == nil && len(key) < _MinLinkKeyLen {
+				t.Errorf("newLinkKey() = %d bytes, want at least %d", len(key), _MinLinkKeyLen)
+			}
+		})
+	}
+}
+
+// downloadStream records the messages sent by DownloadShared.
+type downloadStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	info *pb.FileInfo
+	data []byte
+}
+
+func (ds *downloadStream) Context() context.Context {
+	return ds.ctx
+}
+
+func (ds *downloadStream) Send(dl *pb.Download) error {
+	if info := dl.GetFileInfo(); info != nil {
+		ds.info = info
+	}
+	ds.data = append(ds.data, dl.GetChunk()...)
+	return nil
+}
+
+func TestDownloadShared(t *testing.T) {
+	tests := map[string]struct {
+		link      imgrepo.ShareLink
+		token     string
+		revoke    bool
+		disabled  bool
+		downloads int
+		wantErr   error
+	}{
+		"unlimited":  {downloads: 3},
+		"within max": {link: imgrepo.ShareLink{MaxDownloads: 2}, downloads: 2},
+		"over max":   {link: imgrepo.ShareLink{MaxDownloads: 2}, downloads: 3, wantErr: imgrepo.ErrExpired},
+		"expired":    {link: imgrepo.ShareLink{Expires: time.Now().Add(-time.Minute)}, downloads: 1, wantErr: imgrepo.ErrExpired},
+		"revoked":    {revoke: true, downloads: 1, wantErr: imgrepo.ErrNotFound},
+		"forged":     {token: "link.forged", downloads: 1, wantErr: imgrepo.ErrNotFound},
+		"disabled":   {disabled: true, downloads: 1, wantErr: imgrepo.ErrPermissionDenied},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &repoServer{
+				ir:      memory.NewImageRegistry(memory.NewImageStorage(), memory.NewGroupService()),
+				lr:      memory.NewLinkRegistry(),
+				us:      memory.NewUserService(),
+				linkKey: []byte("0123456789abcdef0123456789abcdef"),
+			}
+
+			if err := s.us.Register("test", "password"); err != nil {
+				t.Fatal(err)
+			}
+			if tc.disabled {
+				if err := s.us.SetDisabled("test", true); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			raw := []byte("image")
+			img := &imgrepo.Image{Owner: "test", Access: imgrepo.Private, Digest: "digest"}
+			if err := s.ir.Upload(img, bytes.NewReader(raw)); err != nil {
+				t.Fatal(err)
+			}
+
+			link := tc.link
+			link.Id = "link"
+			link.ImageId = img.Id
+			link.Owner = "test"
+			if err := s.lr.Create(&link); err != nil {
+				t.Fatal(err)
+			}
+			if tc.revoke {
+				if err := s.lr.Revoke("test", link.Id); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			token := signLink(s.linkKey, link.Id)
+			if tc.token != "" {
+				token = tc.token
+			}
+
+			// Only the last download may fail.
+			for i := 1; i <= tc.downloads; i++ {
+				stream := &downloadStream{}
+				err := s.DownloadShared(&pb.DownloadSharedRequest{Token: token}, stream)
+				if i < tc.downloads && err != nil {
+					t.Fatalf("DownloadShared() #%d = %v", i, err)
+				} else if i == tc.downloads && !errors.Is(err, tc.wantErr) {
+					t.Fatalf("DownloadShared() #%d = %v, want %v", i, err, tc.wantErr)
+				}
+				if err == nil && !bytes.Equal(stream.data, raw) {
+					t.Errorf("DownloadShared() #%d = %q, want %q", i, stream.data, raw)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/algao1/imgrepo"
+)
+
+// _StripNames maps the values of -strip_public to privacy policies.
+var _StripNames = map[string]imgrepo.Privacy{
+	"none":     imgrepo.KeepMetadata,
+	"location": imgrepo.StripLocation,
+	"all":      imgrepo.StripMetadata,
+}
+
+// privacy returns the privacy policy applying to the upload of img, where p
+// is the policy requested by the uploader.
+func (s *repoServer) privacy(img *imgrepo.Image, p imgrepo.Privacy) (imgrepo.Privacy, error) {
+	switch {
+	case p < imgrepo.DefaultPrivacy || p > imgrepo.StripMetadata:
+		return 0, fmt.Errorf("unknown privacy policy %d: %w", p, imgrepo.ErrInvalidArgument)
+	case p != imgrepo.DefaultPrivacy:
+		return p, nil
+	case img.Access == imgrepo.Public:
+		return s.publicPrivacy, nil
+	default:
+		return imgrepo.KeepMetadata, nil
+	}
+}
+
+// redact returns md without the fields the privacy policy p does not keep.
+func redact(md imgrepo.Metadata, p imgrepo.Privacy) imgrepo.Metadata {
+	switch p {
+	case imgrepo.StripLocation:
+		md.Location = nil
+	case imgrepo.StripMetadata:
+		md = imgrepo.Metadata{
+			MimeType:    md.MimeType,
+			Width:       md.Width,
+			Height:      md.Height,
+			Orientation: md.Orientation,
+		}
+	}
+	return md
+}
+
+// upload adds the inspected image read from r to the registry, stripped
+// according to the privacy policy p. Stripping changes the data of the
+// image, so img.Digest is replaced by the digest of the stripped image.
+func (s *repoServer) upload(img *imgrepo.Image, r io.Reader, p imgrepo.Privacy, rends []imgrepo.Rendition) error {
+	img.Metadata = redact(img.Metadata, p)
+	if p == imgrepo.KeepMetadata {
+		return s.ir.Upload(img, r, rends...)
+	}
+
+	f, err := s.strip(img, r)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to strip image", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	return s.ir.Upload(img, f, rends...)
+}
+
+// strip copies the image from r without its metadata blocks to a spool
+// file, and sets img.Digest. The caller must close and remove the file.
+func (s *repoServer) strip(img *imgrepo.Image, r io.Reader) (*os.File, error) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(s.st.Strip(pw, r))
+	}()
+
+	img.Digest = ""
+	return spool(newDigestReader(pr, img))
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/image"
+	"github.com/algao1/imgrepo/memory"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrivacy(t *testing.T) {
+	s := &repoServer{publicPrivacy: imgrepo.StripLocation}
+
+	tests := map[string]struct {
+		access  imgrepo.Permission
+		privacy imgrepo.Privacy
+		want    imgrepo.Privacy
+		wantErr error
+	}{
+		"public default":  {access: imgrepo.Public, want: imgrepo.StripLocation},
+		"private default": {access: imgrepo.Private, want: imgrepo.KeepMetadata},
+		"public keep":     {access: imgrepo.Public, privacy: imgrepo.KeepMetadata, want: imgrepo.KeepMetadata},
+		"private strip":   {access: imgrepo.Private, privacy: imgrepo.StripMetadata, want: imgrepo.StripMetadata},
+		"unknown policy":  {access: imgrepo.Public, privacy: 42, wantErr: imgrepo.ErrInvalidArgument},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := s.privacy(&imgrepo.Image{Access: tc.access}, tc.privacy)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("privacy() = _, %v, want %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("privacy() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+// withSecret returns a JPEG with an EXIF segment holding a secret.
+func withSecret(t *testing.T, secret string) []byte {
+	data, err := os.ReadFile("../../_data/apple1.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app1 := []byte("Exif\x00\x00MM\x00\x2a" + secret)
+	seg := append([]byte{0xff, 0xe1, byte((len(app1) + 2) >> 8), byte(len(app1) + 2)}, app1...)
+
+	return append(append(append([]byte{}, data[:2]...), seg...), data[2:]...)
+}
+
+func TestUpload(t *testing.T) {
+	const secret = "48.858333N 122.42W"
+	data := withSecret(t, secret)
+
+	md := imgrepo.Metadata{
+		MimeType: "image/jpeg",
+		Width:    6000,
+		Height:   4000,
+		Taken:    time.Date(2021, 5, 17, 10, 30, 0, 0, time.UTC),
+		Make:     "Imgrepo",
+		Location: &imgrepo.Location{Latitude: 48.858333, Longitude: -122.42},
+	}
+
+	tests := map[string]struct {
+		privacy   imgrepo.Privacy
+		want      imgrepo.Metadata
+		wantStrip bool
+	}{
+		"keep": {
+			privacy: imgrepo.KeepMetadata,
+			want:    md,
+		},
+		"strip location": {
+			privacy: imgrepo.StripLocation,
+			want: imgrepo.Metadata{
+				MimeType: "image/jpeg",
+				Width:    6000,
+				Height:   4000,
+				Taken:    time.Date(2021, 5, 17, 10, 30, 0, 0, time.UTC),
+				Make:     "Imgrepo",
+			},
+			wantStrip: true,
+		},
+		"strip metadata": {
+			privacy:   imgrepo.StripMetadata,
+			want:      imgrepo.Metadata{MimeType: "image/jpeg", Width: 6000, Height: 4000},
+			wantStrip: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &repoServer{
+				ir: memory.NewImageRegistry(memory.NewImageStorage(), memory.NewGroupService()),
+				st: image.NewStripper(),
+			}
+
+			img := &imgrepo.Image{Owner: "test", Digest: "original", Metadata: md}
+			if err := s.upload(img, bytes.NewReader(data), tc.privacy, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			got, rc, err := s.ir.Download("test", img.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rc.Close()
+			stored, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.want, got.Metadata); diff != "" {
+				t.Errorf("upload() metadata mismatch (-want +got):\n%s", diff)
+			}
+			if leaked := bytes.Contains(stored, []byte(secret)); leaked == tc.wantStrip {
+				t.Errorf("upload() stored the secret = %v, want %v", leaked, !tc.wantStrip)
+			}
+
+			// Stripped images are stored under their own digest.
+			if tc.wantStrip {
+				sum := sha256.Sum256(stored)
+				if want := hex.EncodeToString(sum[:]); got.Digest != want {
+					t.Errorf("upload() digest = %s, want %s", got.Digest, want)
+				}
+			}
+		})
+	}
+}
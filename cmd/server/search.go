@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/image"
+	pb "github.com/algao1/imgrepo/proto"
+)
+
+// SearchSimilar finds the images viewable by the requester whose hash is
+// within the distance of the query, closest first.
+//
+// The query either names an existing image, or is followed by the chunks
+// of an image, which is hashed but not stored.
+func (s *repoServer) SearchSimilar(stream pb.Repo_SearchSimilarServer) error {
+	in, err := stream.Recv()
+	if err == io.EOF {
+		return fmt.Errorf("no query received: %w", imgrepo.ErrInvalidArgument)
+	} else if err != nil {
+		return err
+	}
+
+	q := in.GetQuery()
+	if q == nil {
+		return fmt.Errorf("received chunk before query: %w", imgrepo.ErrInvalidArgument)
+	}
+	if q.Size < 0 {
+		return fmt.Errorf("negative size %d: %w", q.Size, imgrepo.ErrInvalidArgument)
+	}
+
+	requester := userFromContext(stream.Context())
+
+	var query imgrepo.Image
+	if q.Id != "" {
+		imgs, err := s.ir.Find(requester, q.Id)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to find image", err)
+		}
+		if len(imgs) == 0 {
+			return fmt.Errorf("file %s: %w", q.Id, imgrepo.ErrNotFound)
+		}
+		query = *imgs[0]
+	} else {
+		r, err := s.validated(newLimitReader(searchChunks(stream), s.maxSize))
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to receive image", err)
+		}
+		if err := s.ic.SetHash(&query, r); err != nil {
+			return fmt.Errorf("%q: %w", "unable to hash image", err)
+		}
+	}
+
+	matches, err := s.idx.Search(&query, int(q.MaxDistance))
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to search images", err)
+	}
+
+	// The index holds every image, so the matches are narrowed down to the
+	// ones viewable by the requester.
+	var ids []string
+	dists := make(map[string]int)
+	for _, m := range matches {
+		if m.Image.Id == q.Id {
+			continue
+		}
+		ids = append(ids, m.Image.Id)
+		dists[m.Image.Id] = m.Distance
+	}
+
+	imgs, err := s.ir.Find(requester, ids...)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to find images", err)
+	}
+	if q.Size > 0 && len(imgs) > int(q.Size) {
+		imgs = imgs[:q.Size]
+	}
+
+	resp := &pb.SearchResponse{Images: make([]*pb.SimilarImage, len(imgs))}
+	for i, img := range imgs {
+		resp.Images[i] = &pb.SimilarImage{FileInfo: fileInfo(requester, img), Distance: int32(dists[img.Id])}
+	}
+
+	return stream.SendAndClose(resp)
+}
+
+// newIndex returns an index of the images in the registry hashed with the
+// given kind of hash.
+func newIndex(ir imgrepo.ImageRegistry, kind int) (*image.Index, error) {
+	idx := image.NewIndex(kind)
+
+	var indexed, skipped int
+	err := ir.Walk(func(img *imgrepo.Image) error {
+		// Images hashed with another kind of hash cannot be compared.
+		if img.Kind != kind {
+			skipped++
+			return nil
+		}
+
+		indexed++
+		return idx.Add(img)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("indexed %d image(s), skipped %d with another kind of hash", indexed, skipped)
+
+	return idx, nil
+}
@@ -1,18 +1,25 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net"
 	"os"
+	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/algao1/imgrepo"
 	"github.com/algao1/imgrepo/digitalocean"
+	"github.com/algao1/imgrepo/filesystem"
+	"github.com/algao1/imgrepo/image"
+	"github.com/algao1/imgrepo/memory"
 	"github.com/algao1/imgrepo/mongo"
 	"github.com/algao1/imgrepo/redis"
 
@@ -24,22 +31,144 @@ import (
 
 var (
 	serverAddr = flag.String("server_addr", "localhost:10000", "The server address in the format of host:port")
+	inMemory   = flag.Bool("memory", false, "Keep accounts, sessions and the image registry in memory instead of MongoDB and Redis")
+	storage    = flag.String("storage", "spaces", "The image storage to use, one of spaces, filesystem or memory")
+	storageDir = flag.String("storage_dir", "store", "The directory used by the filesystem image storage")
+	uploadDir  = flag.String("upload_dir", "uploads", "The directory holding resumable uploads until they are committed")
+	uploadTTL  = flag.Duration("upload_ttl", 24*time.Hour, "How long a resumable upload is kept after it was last written, before it is discarded as abandoned (at least a minute)")
+	hashKind   = flag.String("hash", "perceptual", "The hash used to compare images, one of average, perceptual or difference")
+	dupDist    = flag.Int("duplicate_distance", 2, "The maximum distance between the hashes of images considered duplicates")
+	renditions = flag.String("renditions", "128,512", "The comma separated sizes of the renditions generated on upload, in pixels")
+	cacheSize  = flag.Int("transform_cache", 64, "The size of the cache of transformed images, in megabytes")
+	types      = flag.String("allowed_types", strings.Join(image.DecodableTypes, ","), "The comma separated MIME types of the images accepted, detected from their content")
+	maxSize    = flag.Int64("max_size", 32, "The maximum size of an image, in megabytes")
+	maxPixels  = flag.Int64("max_pixels", 50000000, "The maximum number of pixels of an image")
+	stripPub   = flag.String("strip_public", "location", "The metadata stripped from public images unless the uploader chooses otherwise, one of none, location or all")
+	adminNames = flag.String("admins", "", "The comma separated usernames of the registered accounts given the admin role when the server starts")
 )
 
-// _ChunkSize determines the size of each chunk.
-const _ChunkSize = 128 * 1024
-
 type repoServer struct {
 	pb.UnimplementedRepoServer
+	pb.UnimplementedAdminServer
+
+	us  imgrepo.UserService
+	ss  imgrepo.SessionService
+	ir  imgrepo.ImageRegistry
+	gs  imgrepo.GroupService
+	ar  imgrepo.AlbumRegistry
+	lr  imgrepo.LinkRegistry
+	ups imgrepo.UploadStore
+	iv  imgrepo.ImageValidator
+	ic  imgrepo.ImageComparator
+	mi  imgrepo.ImageInspector
+	idx imgrepo.ImageIndex
+	rd  imgrepo.ImageRenderer
+	tf  imgrepo.ImageTransformer
+	st  imgrepo.ImageStripper
+
+	cache         imgrepo.ImageCache
+	admins        map[string]bool
+	linkKey       []byte
+	dupDist       int
+	maxSize       int64
+	publicPrivacy imgrepo.Privacy
+}
+
+// fileInfo converts an image to the file info sent to the requester. Only
+// the owner is told who the image is shared with, and where it was taken,
+// like in the images listed by albums.
+func fileInfo(requester string, img *imgrepo.Image) *pb.FileInfo {
+	finfo := &pb.FileInfo{
+		Id:       img.Id,
+		FileName: img.Name,
+		Owner:    img.Owner,
+		Access:   int32(img.Access),
+		Digest:   img.Digest,
+
+		Renditions: toSizes(img.Renditions),
+		Metadata:   toMetadata(img.Metadata),
+		Tags:       img.Tags,
+	}
+	if img.Owner == requester {
+		finfo.Readers = img.Readers
+		finfo.ReaderGroups = img.Groups
+	} else {
+		finfo.Metadata.Location = nil
+	}
+
+	return finfo
+}
+
+// checkAccess rejects the access of a request if it is neither public nor
+// private, so that no other value is ever stored.
+func checkAccess(access imgrepo.Permission) error {
+	if access != imgrepo.Public && access != imgrepo.Private {
+		return fmt.Errorf("unknown access %d: %w", access, imgrepo.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// toMetadata converts the metadata of an image, where a zero capture time is
+// sent as 0.
+func toMetadata(md imgrepo.Metadata) *pb.Metadata {
+	res := &pb.Metadata{
+		MimeType:    md.MimeType,
+		Width:       int32(md.Width),
+		Height:      int32(md.Height),
+		Make:        md.Make,
+		Model:       md.Model,
+		Orientation: int32(md.Orientation),
+	}
+	if !md.Taken.IsZero() {
+		res.Taken = md.Taken.Unix()
+	}
+	if md.Location != nil {
+		res.Location = &pb.Location{Latitude: md.Location.Latitude, Longitude: md.Location.Longitude}
+	}
+
+	return res
+}
+
+func toSizes(sizes []int) []int32 {
+	var res []int32
+	for _, size := range sizes {
+		res = append(res, int32(size))
+	}
+	return res
+}
 
-	us imgrepo.UserService
-	ss imgrepo.SessionService
-	ir imgrepo.ImageRegistry
+// renditionName returns the file name of the rendition of the given size of
+// an image, which is always a JPEG.
+func renditionName(name string, size int) string {
+	return fmt.Sprintf("%s_%d.jpg", strings.TrimSuffix(name, path.Ext(name)), size)
 }
 
-// Register registers a user account.
+// indexImage adds an uploaded image to the similarity index. The upload has
+// already succeeded, so a failure is only logged.
+func (s *repoServer) indexImage(img *imgrepo.Image) {
+	if err := s.idx.Add(img); err != nil {
+		log.Printf("unable to index image %s: %v", img.Id, err)
+	}
+}
+
+// validated checks the type and dimensions of the image read from r, and
+// returns a reader over the whole image. Only the headers are read, so the
+// image is rejected before it is decoded.
+func (s *repoServer) validated(r io.Reader) (io.Reader, error) {
+	var head bytes.Buffer
+	if err := s.iv.Validate(io.TeeReader(r, &head)); err != nil {
+		return nil, err
+	}
+	return io.MultiReader(&head, r), nil
+}
+
+// Register registers a user account, which always has the user role.
 func (s *repoServer) Register(ctx context.Context, req *pb.RegisterRequest) (*emptypb.Empty, error) {
-	return new(emptypb.Empty), s.us.Register(req.Username, req.Password)
+	if err := s.us.Register(req.Username, req.Password); err != nil {
+		return nil, err
+	}
+
+	return new(emptypb.Empty), nil
 }
 
 // Login logs in a user account.
@@ -50,7 +179,7 @@ func (s *repoServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Login
 	}
 
 	// Generates a new session.
-	uuid, err := s.ss.NewSession()
+	uuid, err := s.ss.NewSession(req.Username)
 	if err != nil {
 		return nil, err
 	}
@@ -61,115 +190,187 @@ func (s *repoServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Login
 // UploadImage uploads an image to the image repository.
 //
 // It gets a stream of events (fileinfo & chunks), and responds with either
-// a completion message, or an error.
+// the id of the image and its likely duplicates, or an error. The chunks are
+// spooled to a temporary file as they arrive, rather than being buffered in
+// memory, and the upload is rejected as soon as the image turns out to be too
+// large or of a type not allowed, or once the stream ends if their digest
+// differs from the one in the file info. The spooled image is then read again
+// to hash, inspect and render it, and to store it. The metadata blocks of the
+// image are stripped before it is stored, unless the privacy policy keeps
+// them.
 func (s *repoServer) UploadImage(stream pb.Repo_UploadImageServer) error {
-	img := imgrepo.Image{}
 	startTime := time.Now()
 
-	for {
-		in, err := stream.Recv()
-		if err == io.EOF {
-			endTime := time.Now()
-			log.Printf("finished receiving file in: %.4fs\n", endTime.Sub(startTime).Seconds())
-
-			uerr := s.ir.Upload(&img)
-			if uerr != nil {
-				return err
-			}
-
-			return stream.SendAndClose(new(emptypb.Empty))
-		}
-		if err != nil {
-			return err
-		}
-
-		// Handles the 2 types of events (UploadInfo & Chunk).
-		switch in.GetEvent().(type) {
-		case *pb.Upload_Info:
-			// Verify that the user is logged in using token.
-			err := s.ss.IsSession(in.GetInfo().Token)
-			if err != nil {
-				return fmt.Errorf("%q: %w", "unable to authenticate UploadImage()", err)
-			}
-
-			finfo := in.GetInfo().GetFileInfo()
-
-			img.Name = finfo.FileName
-			img.Owner = finfo.Owner
-			img.Access = imgrepo.Permission(finfo.Access)
-
-			log.Println("received file info")
-		case *pb.Upload_Chunk_:
-			img.Raw = append(img.Raw, in.GetChunk().Chunk...)
-		}
+	// The first event must be the file info.
+	in, err := stream.Recv()
+	if err == io.EOF {
+		return fmt.Errorf("no file info received: %w", imgrepo.ErrInvalidArgument)
+	} else if err != nil {
+		return err
 	}
+
+	finfo := in.GetInfo().GetFileInfo()
+	if finfo == nil {
+		return fmt.Errorf("received chunk before file info: %w", imgrepo.ErrInvalidArgument)
+	}
+	log.Println("received file info")
+
+	tags, err := toTags(finfo.Tags)
+	if err != nil {
+		return err
+	}
+
+	// The owner is always the logged in user, so finfo.Owner is ignored.
+	img := imgrepo.Image{
+		Name:   finfo.FileName,
+		Owner:  userFromContext(stream.Context()),
+		Access: imgrepo.Permission(finfo.Access),
+		Digest: finfo.Digest,
+		Tags:   tags,
+	}
+	if err := checkAccess(img.Access); err != nil {
+		return err
+	}
+
+	privacy, err := s.privacy(&img, imgrepo.Privacy(in.GetInfo().Privacy))
+	if err != nil {
+		return err
+	}
+
+	r, err := s.validated(newLimitReader(uploadChunks(stream), s.maxSize))
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to receive image", err)
+	}
+
+	// The image is spooled to disk first, since the registry stores blobs
+	// under their digest, which is only known once the stream ends.
+	f, err := spool(newDigestReader(r, &img))
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to receive image", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	err = s.ic.SetHash(&img, f)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to hash image", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("%q: %w", "unable to rewind image", err)
+	}
+
+	dups, skip, err := s.checkDuplicates(&img, imgrepo.DuplicatePolicy(in.GetInfo().OnDuplicate))
+	if err != nil {
+		return err
+	}
+	if skip {
+		log.Printf("skipped duplicate of %v", dups)
+		return stream.SendAndClose(&pb.UploadResponse{Duplicates: dups, Skipped: true})
+	}
+
+	err = s.mi.Inspect(&img, f)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to inspect image", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("%q: %w", "unable to rewind image", err)
+	}
+
+	rends, err := s.rd.Render(f)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to render image", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("%q: %w", "unable to rewind image", err)
+	}
+
+	err = s.upload(&img, f, privacy, rends)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to upload image", err)
+	}
+	s.indexImage(&img)
+
+	endTime := time.Now()
+	log.Printf("finished receiving file in: %.4fs\n", endTime.Sub(startTime).Seconds())
+
+	return stream.SendAndClose(&pb.UploadResponse{Id: img.Id, Duplicates: dups})
 }
 
-// DownloadImage downloads an image with id specified by the request.
+// DownloadImage downloads an image with id specified by the request, its
+// rendition of the requested size, or the image converted by the requested
+// transform. Images downloaded through an album are redacted like any other
+// image the requester does not own.
 //
-// The id is first looked up in the image registry, then downloaded from the image
-// storage. Once obtained, the file is streamed back to the client.
+// The id is first looked up in the image registry, then streamed from the
+// image storage back to the client in chunks.
 func (s *repoServer) DownloadImage(req *pb.DownloadRequest, stream pb.Repo_DownloadImageServer) error {
-	// Verify that the user is logged in using token.
-	err := s.ss.IsSession(req.Token)
+	if req.Transform != nil {
+		return s.downloadTransformed(req, stream)
+	}
+
+	requester := userFromContext(stream.Context())
+	reader, err := s.reader(requester, req)
 	if err != nil {
-		return fmt.Errorf("%q: %w", "unable to authenticate DownloadImage()", err)
+		return err
 	}
 
-	image, err := s.ir.Download(req.Sender, req.Id)
+	var image *imgrepo.Image
+	var rc io.ReadCloser
+	if req.Rendition != 0 {
+		image, rc, err = s.ir.Rendition(reader, req.Id, int(req.Rendition))
+	} else {
+		image, rc, err = s.ir.Download(reader, req.Id)
+	}
 	if err != nil {
-		return fmt.Errorf("%q: %w", "unable to download raw iamge", err)
+		return fmt.Errorf("%q: %w", "unable to download raw image", err)
+	}
+	defer rc.Close()
+
+	info := fileInfo(requester, image)
+	if req.Rendition != 0 {
+		info.FileName = renditionName(image.Name, int(req.Rendition))
 	}
 
+	return sendFile(stream, info, rc)
+}
+
+// sendFile sends the file info, then the file from r in chunks.
+func sendFile(stream pb.Repo_DownloadImageServer, info *pb.FileInfo, r io.Reader) error {
 	finfo := &pb.Download{
 		Event: &pb.Download_FileInfo{
-			FileInfo: &pb.FileInfo{
-				Id:       image.Id,
-				FileName: image.Name,
-				Owner:    image.Owner,
-				Access:   int32(image.Access),
-			},
+			FileInfo: info,
 		},
 	}
 
 	// Send back file info first.
 	if err := stream.Send(finfo); err != nil {
-		log.Fatalf("%v.Send(%v) = %v", stream, image.Name, err)
+		return fmt.Errorf("%q: %w", "unable to send file info", err)
 	}
 
-	for cByte := 0; cByte < len(image.Raw); cByte += _ChunkSize {
-		var chunk []byte
-		if cByte+_ChunkSize > len(image.Raw) {
-			chunk = image.Raw[cByte:]
-		} else {
-			chunk = image.Raw[cByte : cByte+_ChunkSize]
-		}
-
-		dchunk := &pb.Download{
-			Event: &pb.Download_Chunk{
-				Chunk: chunk,
-			},
-		}
-
-		// Send the file in chunks.
-		if err := stream.Send(dchunk); err != nil {
-			log.Fatalf("%v.Send(%v) = %v", stream, image.Name, err)
-		}
+	// Send the file in chunks.
+	_, err := io.CopyBuffer(&chunkWriter{stream: stream}, r, make([]byte, _ChunkSize))
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to send file", err)
 	}
 
 	return nil
 }
 
-// ListImages lists the images viewable by the requester.
+// ListImages lists the images viewable by the requester, with any and all
+// of the tags of the request.
 func (s *repoServer) ListImages(ctx context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
-	// Verify that the user is logged in using token.
-	err := s.ss.IsSession(req.Token)
-	if err != nil {
-		return nil, fmt.Errorf("%q: %w", "unable to authenticate ListImages()", err)
+	filter := imgrepo.ListFilter{Shared: req.Shared}
+	var err error
+	if filter.Any, err = toTags(req.AnyTags); err != nil {
+		return nil, err
+	}
+	if filter.All, err = toTags(req.AllTags); err != nil {
+		return nil, err
 	}
 
 	// Get list of images viewable by requester.
-	imgs, err := s.ir.List(int(req.Size), req.Sender, req.LastId)
+	requester := userFromContext(ctx)
+	imgs, err := s.ir.List(int(req.Size), requester, req.LastId, filter)
 	if err != nil {
 		return nil, fmt.Errorf("%q: %w", "unable to list images", err)
 	}
@@ -177,24 +378,155 @@ func (s *repoServer) ListImages(ctx context.Context, req *pb.ListRequest) (*pb.L
 	// Sender list of images viewable back.
 	finfos := make([]*pb.FileInfo, len(imgs))
 	for i, img := range imgs {
-		finfos[i] = &pb.FileInfo{
-			Id:       img.Id,
-			FileName: img.Name,
-			Owner:    img.Owner,
-			Access:   int32(img.Access),
-		}
+		finfos[i] = fileInfo(requester, img)
 	}
 
 	return &pb.ListResponse{Files: finfos}, nil
 }
 
+// DeleteImage deletes an image owned by the requester, removing both the
+// registry entry and the stored file.
+func (s *repoServer) DeleteImage(ctx context.Context, req *pb.DeleteRequest) (*emptypb.Empty, error) {
+	if err := s.deleteImage(userFromContext(ctx), req.Id); err != nil {
+		return nil, err
+	}
+
+	return new(emptypb.Empty), nil
+}
+
+// deleteImage deletes an image owned by the requester, and removes it from
+// the similarity index.
+func (s *repoServer) deleteImage(requester, id string) error {
+	err := s.ir.Delete(requester, id)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to delete image", err)
+	}
+
+	// Images hashed with another kind of hash were never indexed.
+	if err := s.idx.Remove(id); err != nil && !errors.Is(err, imgrepo.ErrNotFound) {
+		log.Printf("unable to remove image %s from index: %v", id, err)
+	}
+
+	return nil
+}
+
+// newImageStorage creates the ImageStorage selected by the storage flag.
+func newImageStorage() (imgrepo.ImageStorage, error) {
+	switch *storage {
+	case "spaces":
+		return digitalocean.NewImageStorage(
+			os.Getenv("SPACES_KEY"),
+			os.Getenv("SPACES_SECRET"),
+			os.Getenv("SPACES_ENDPOINT"),
+			os.Getenv("SPACES_REGION"),
+			os.Getenv("SPACES_BUCKET"),
+		)
+	case "filesystem":
+		return filesystem.NewImageStorage(*storageDir)
+	case "memory":
+		return memory.NewImageStorage(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage: %s", *storage)
+	}
+}
+
 func newServer() (*repoServer, error) {
-	// Load configurations from .env.
+	// Load configurations from .env, which is optional when every service
+	// is kept in memory.
 	err := godotenv.Load()
-	if err != nil {
+	if err != nil && !(*inMemory && *storage != "spaces") {
 		return nil, fmt.Errorf("unable to load .env file: %v", err)
 	}
 
+	// Create a ImageStorage
+	is, err := newImageStorage()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create image storage: %v", err)
+	}
+	log.Printf("new ImageStorage created: %s", *storage)
+
+	// Create a ImageComparator
+	kind, err := image.ParseKind(*hashKind)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create image comparator: %v", err)
+	}
+	ic, err := image.NewComparator(kind)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create image comparator: %v", err)
+	}
+	log.Printf("new ImageComparator created: %s", *hashKind)
+
+	if *uploadTTL < _MinUploadTTL {
+		return nil, fmt.Errorf("upload ttl %v is shorter than %v", *uploadTTL, _MinUploadTTL)
+	}
+
+	// Create a ImageValidator
+	if *maxSize <= 0 {
+		return nil, fmt.Errorf("maximum image size %d is not positive", *maxSize)
+	}
+	iv, err := image.NewValidator(strings.Split(*types, ","), *maxPixels)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create image validator: %v", err)
+	}
+	log.Printf("new ImageValidator created: %s", *types)
+
+	// Create a ImageRenderer
+	sizes, err := image.ParseSizes(*renditions)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create image renderer: %v", err)
+	}
+	rd, err := image.NewRenderer(sizes...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create image renderer: %v", err)
+	}
+	log.Printf("new ImageRenderer created: %v", sizes)
+
+	// Create a ImageCache
+	if *cacheSize < 0 {
+		return nil, fmt.Errorf("negative transform cache size: %d", *cacheSize)
+	}
+	cache := memory.NewImageCache(*cacheSize << 20)
+	log.Printf("new ImageCache created: %d MB", *cacheSize)
+
+	publicPrivacy, ok := _StripNames[*stripPub]
+	if !ok {
+		return nil, fmt.Errorf("unknown metadata to strip from public images: %q", *stripPub)
+	}
+
+	linkKey, err := newLinkKey(*inMemory, os.Getenv("LINK_KEY"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create link key: %v", err)
+	}
+
+	if *inMemory {
+		log.Printf("using in-memory services")
+		gs := memory.NewGroupService()
+		ir := memory.NewImageRegistry(is, gs)
+		return &repoServer{
+			us:  memory.NewUserService(),
+			ss:  memory.NewSessionService(),
+			ir:  ir,
+			gs:  gs,
+			ar:  memory.NewAlbumRegistry(ir),
+			lr:  memory.NewLinkRegistry(),
+			ups: memory.NewUploadStore(),
+			iv:  iv,
+			ic:  ic,
+			mi:  image.NewInspector(),
+			idx: image.NewIndex(kind),
+			rd:  rd,
+			tf:  image.NewTransformer(),
+			st:  image.NewStripper(),
+
+			cache:         cache,
+			admins:        parseAdmins(*adminNames),
+			linkKey:       linkKey,
+			dupDist:       *dupDist,
+			maxSize:       *maxSize << 20,
+			publicPrivacy: publicPrivacy,
+		}, nil
+	}
+
 	// Create a UserService
 	us, err := mongo.NewUserService(
 		os.Getenv("MONGO_URI"),
@@ -223,22 +555,21 @@ func newServer() (*repoServer, error) {
 	}
 	log.Printf("new SessionService created")
 
-	// Create a ImageStorage
-	is, err := digitalocean.NewImageStorage(
-		os.Getenv("SPACES_KEY"),
-		os.Getenv("SPACES_SECRET"),
-		os.Getenv("SPACES_ENDPOINT"),
-		os.Getenv("SPACES_REGION"),
-		os.Getenv("SPACES_BUCKET"),
+	// Create a GroupService
+	gs, err := mongo.NewGroupService(
+		os.Getenv("MONGO_URI"),
+		os.Getenv("MONGO_DB"),
+		os.Getenv("MONGO_GROUPS"),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create image storage: %v", err)
+		return nil, fmt.Errorf("unable to create group service: %v", err)
 	}
-	log.Printf("new ImageStorage created")
+	log.Printf("new GroupService created")
 
 	// Create a ImageRegistry
 	ir, err := mongo.NewImageRegistry(
 		is,
+		gs,
 		os.Getenv("MONGO_URI"),
 		os.Getenv("MONGO_DB"),
 		os.Getenv("MONGO_IMGS"),
@@ -248,7 +579,58 @@ func newServer() (*repoServer, error) {
 	}
 	log.Printf("new ImageRegistry created")
 
-	return &repoServer{us: us, ss: ss, ir: ir}, nil
+	// Create an AlbumRegistry, next to the images it holds
+	ar := mongo.NewAlbumRegistry(ir, os.Getenv("MONGO_ALBUMS"))
+	log.Printf("new AlbumRegistry created")
+
+	// Create a LinkRegistry
+	lr, err := mongo.NewLinkRegistry(
+		os.Getenv("MONGO_URI"),
+		os.Getenv("MONGO_DB"),
+		os.Getenv("MONGO_LINKS"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create link registry: %v", err)
+	}
+	log.Printf("new LinkRegistry created")
+
+	// Create a UploadStore
+	ups, err := filesystem.NewUploadStore(*uploadDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create upload store: %v", err)
+	}
+	log.Printf("new UploadStore created")
+
+	// Create a ImageIndex
+	idx, err := newIndex(ir, kind)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create image index: %v", err)
+	}
+	log.Printf("new ImageIndex created")
+
+	return &repoServer{
+		us:  us,
+		ss:  ss,
+		ir:  ir,
+		gs:  gs,
+		ar:  ar,
+		lr:  lr,
+		ups: ups,
+		iv:  iv,
+		ic:  ic,
+		mi:  image.NewInspector(),
+		idx: idx,
+		rd:  rd,
+		tf:  image.NewTransformer(),
+		st:  image.NewStripper(),
+
+		cache:         cache,
+		admins:        parseAdmins(*adminNames),
+		linkKey:       linkKey,
+		dupDist:       *dupDist,
+		maxSize:       *maxSize << 20,
+		publicPrivacy: publicPrivacy,
+	}, nil
 }
 
 func main() {
@@ -260,14 +642,21 @@ func main() {
 	}
 	log.Printf("listening on: %s\n", *serverAddr)
 
-	var opts []grpc.ServerOption
-	grpcServer := grpc.NewServer(opts...)
-
 	server, err := newServer()
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err := server.promoteAdmins(); err != nil {
+		log.Fatal(err)
+	}
+	go server.sweepUploads(*uploadTTL)
+
+	var opts []grpc.ServerOption
+	opts = append(opts, grpc.ChainUnaryInterceptor(unaryStatus, server.unaryAuth))
+	opts = append(opts, grpc.ChainStreamInterceptor(streamStatus, server.streamAuth))
+	grpcServer := grpc.NewServer(opts...)
 
 	pb.RegisterRepoServer(grpcServer, server)
+	pb.RegisterAdminServer(grpcServer, server)
 	grpcServer.Serve(lis)
 }
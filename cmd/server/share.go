@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/algao1/imgrepo"
+	pb "github.com/algao1/imgrepo/proto"
+)
+
+// ShareImage grants the users and groups read access to an image owned by
+// the requester. Only registered users and existing groups may be granted
+// access, so that a grant never passes to whoever registers a mistyped name
+// later.
+func (s *repoServer) ShareImage(ctx context.Context, req *pb.ShareRequest) (*pb.FileInfo, error) {
+	requester := userFromContext(ctx)
+	g, err := toGrant(requester, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUsers(g.Readers); err != nil {
+		return nil, err
+	}
+	if err := s.checkGroups(g.Groups); err != nil {
+		return nil, err
+	}
+
+	img, err := s.ir.Share(requester, req.Id, g)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to share image", err)
+	}
+	log.Printf("shared image %s with %v", img.Id, g)
+
+	return fileInfo(requester, img), nil
+}
+
+// UnshareImage revokes the read access of the users and groups to an image
+// owned by the requester.
+func (s *repoServer) UnshareImage(ctx context.Context, req *pb.ShareRequest) (*pb.FileInfo, error) {
+	requester := userFromContext(ctx)
+	g, err := toGrant(requester, req)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := s.ir.Unshare(requester, req.Id, g)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to unshare image", err)
+	}
+	log.Printf("unshared image %s with %v", img.Id, g)
+
+	return fileInfo(requester, img), nil
+}
+
+// checkUsers checks that the users are registered.
+func (s *repoServer) checkUsers(users []string) error {
+	for _, user := range users {
+		ok, err := s.us.Exists(user)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to find user", err)
+		} else if !ok {
+			return fmt.Errorf("user %s: %w", user, imgrepo.ErrNotFound)
+		}
+	}
+	return nil
+}
+
+// checkGroups checks that the groups exist.
+func (s *repoServer) checkGroups(groups []string) error {
+	for _, group := range groups {
+		ok, err := s.gs.Exists(group)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to find group", err)
+		} else if !ok {
+			return fmt.Errorf("group %s: %w", group, imgrepo.ErrNotFound)
+		}
+	}
+	return nil
+}
+
+// toGrant converts a share request, and checks that it names someone other
+// than the requester.
+func toGrant(requester string, req *pb.ShareRequest) (imgrepo.Grant, error) {
+	var g imgrepo.Grant
+	var err error
+	if g.Readers, err = toNames(req.Users); err != nil {
+		return g, err
+	}
+	if g.Groups, err = toNames(req.Groups); err != nil {
+		return g, err
+	}
+
+	for _, user := range g.Readers {
+		if user == requester {
+			return g, fmt.Errorf("%q: %w", "unable to share with the owner", imgrepo.ErrInvalidArgument)
+		}
+	}
+	if g.Empty() {
+		return g, fmt.Errorf("%q: %w", "no users or groups to share with", imgrepo.ErrInvalidArgument)
+	}
+
+	return g, nil
+}
+
+// toNames returns the names of users or groups without duplicates, and
+// checks that none are empty.
+func toNames(names []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var res []string
+	for _, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("%q: %w", "empty name", imgrepo.ErrInvalidArgument)
+		}
+		if !seen[name] {
+			seen[name] = true
+			res = append(res, name)
+		}
+	}
+
+	return res, nil
+}
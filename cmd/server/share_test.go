@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	pb "github.com/algao1/imgrepo/proto"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestToGrant(t *testing.T) {
+	tests := map[string]struct {
+		users   []string
+		groups  []string
+		want    imgrepo.Grant
+		wantErr error
+	}{
+		"users":       {users: []string{"test2", "test3", "test2"}, want: imgrepo.Grant{Readers: []string{"test2", "test3"}}},
+		"groups":      {groups: []string{"team", "team"}, want: imgrepo.Grant{Groups: []string{"team"}}},
+		"both":        {users: []string{"test2"}, groups: []string{"team"}, want: imgrepo.Grant{Readers: []string{"test2"}, Groups: []string{"team"}}},
+		"none":        {wantErr: imgrepo.ErrInvalidArgument},
+		"empty user":  {users: []string{"test2", ""}, wantErr: imgrepo.ErrInvalidArgument},
+		"empty group": {groups: []string{""}, wantErr: imgrepo.ErrInvalidArgument},
+		"owner":       {users: []string{"test2", "test"}, wantErr: imgrepo.ErrInvalidArgument},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := toGrant("test", &pb.ShareRequest{Users: tc.users, Groups: tc.groups})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("toGrant() = _, %v, want %v", err, tc.wantErr)
+			} else if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("toGrant() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFileInfo(t *testing.T) {
+	img := &imgrepo.Image{
+		Id:       "x",
+		Owner:    "test",
+		Access:   imgrepo.Private,
+		Grant:    imgrepo.Grant{Readers: []string{"test2"}, Groups: []string{"team"}},
+		Metadata: imgrepo.Metadata{Make: "Camera", Location: &imgrepo.Location{Latitude: 48.858333, Longitude: -122.42}},
+	}
+
+	// Readers, and link holders without an account, see neither the grant
+	// nor the location.
+	tests := map[string]struct {
+		requester string
+		want      bool
+	}{
+		"owner":       {requester: "test", want: true},
+		"reader":      {requester: "test2"},
+		"link holder": {requester: ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := fileInfo(tc.requester, img)
+			if shown := got.Metadata.Location != nil; shown != tc.want {
+				t.Errorf("fileInfo() location shown = %v, want %v", shown, tc.want)
+			}
+			if shown := len(got.Readers) > 0 || len(got.ReaderGroups) > 0; shown != tc.want {
+				t.Errorf("fileInfo() grant shown = %v, want %v", shown, tc.want)
+			}
+			if got.Metadata.Make != "Camera" {
+				t.Errorf("fileInfo() make = %q, want %q", got.Metadata.Make, "Camera")
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/algao1/imgrepo"
+	pb "github.com/algao1/imgrepo/proto"
+)
+
+// _ChunkSize determines the size of each chunk.
+const _ChunkSize = 128 * 1024
+
+// chunkReader reads the chunks of a client stream, following its first
+// message, as a contiguous io.Reader. recv returns the next chunk.
+type chunkReader struct {
+	recv func() ([]byte, error)
+	buf  []byte
+}
+
+// uploadChunks returns a chunkReader over an UploadImage stream.
+func uploadChunks(stream pb.Repo_UploadImageServer) *chunkReader {
+	return &chunkReader{recv: func() ([]byte, error) {
+		in, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		chunk := in.GetChunk()
+		if chunk == nil {
+			return nil, fmt.Errorf("received file info twice: %w", imgrepo.ErrInvalidArgument)
+		}
+		return chunk.Chunk, nil
+	}}
+}
+
+// searchChunks returns a chunkReader over a SearchSimilar stream.
+func searchChunks(stream pb.Repo_SearchSimilarServer) *chunkReader {
+	return &chunkReader{recv: func() ([]byte, error) {
+		in, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := in.GetEvent().(*pb.SearchRequest_Chunk); !ok {
+			return nil, fmt.Errorf("received query twice: %w", imgrepo.ErrInvalidArgument)
+		}
+		return in.GetChunk(), nil
+	}}
+}
+
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for len(cr.buf) == 0 {
+		chunk, err := cr.recv()
+		if err != nil {
+			return 0, err
+		}
+		cr.buf = chunk
+	}
+
+	n := copy(p, cr.buf)
+	cr.buf = cr.buf[n:]
+
+	return n, nil
+}
+
+// digestReader computes the SHA-256 and the size of the data read from r,
+// and sets img.Digest and img.Size once r is exhausted. If img.Digest is
+// already set, it is the digest declared by the client, and reading fails on
+// a mismatch instead.
+type digestReader struct {
+	r    io.Reader
+	img  *imgrepo.Image
+	want string
+	h    hash.Hash
+	n    int64
+}
+
+func newDigestReader(r io.Reader, img *imgrepo.Image) *digestReader {
+	dr := &digestReader{r: r, img: img, want: img.Digest, h: sha256.New()}
+	img.Digest = ""
+	return dr
+}
+
+func (dr *digestReader) Read(p []byte) (int, error) {
+	n, err := dr.r.Read(p)
+	dr.h.Write(p[:n])
+	dr.n += int64(n)
+
+	if err == io.EOF {
+		sum := hex.EncodeToString(dr.h.Sum(nil))
+		if dr.want != "" && dr.want != sum {
+			return n, fmt.Errorf("digest %s does not match declared digest %s: %w", sum, dr.want, imgrepo.ErrDigestMismatch)
+		}
+		dr.img.Digest = sum
+		dr.img.Size = dr.n
+	}
+
+	return n, err
+}
+
+// limitReader reads from r, and fails once more than max bytes are read,
+// so that oversized images are rejected while they are streamed.
+type limitReader struct {
+	r    io.Reader
+	max  int64
+	left int64
+}
+
+func newLimitReader(r io.Reader, max int64) *limitReader {
+	return &limitReader{r: r, max: max, left: max}
+}
+
+func (lr *limitReader) Read(p []byte) (int, error) {
+	if lr.left < 0 {
+		return 0, fmt.Errorf("image larger than %d bytes: %w", lr.max, imgrepo.ErrTooLarge)
+	}
+
+	// One byte past the limit is enough to tell that it is exceeded.
+	if int64(len(p)) > lr.left+1 {
+		p = p[:lr.left+1]
+	}
+
+	n, err := lr.r.Read(p)
+	lr.left -= int64(n)
+	if lr.left < 0 {
+		return 0, fmt.Errorf("image larger than %d bytes: %w", lr.max, imgrepo.ErrTooLarge)
+	}
+
+	return n, err
+}
+
+// spool copies r to a temporary file, and returns it rewound. The caller
+// must close and remove the file.
+func spool(r io.Reader) (*os.File, error) {
+	f, err := os.CreateTemp("", "imgrepo-spool-*")
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create spool file", err)
+	}
+
+	_, err = io.Copy(f, r)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// chunkWriter writes to a DownloadImage stream, splitting the data into
+// chunks of at most _ChunkSize bytes.
+type chunkWriter struct {
+	stream pb.Repo_DownloadImageServer
+}
+
+func (cw *chunkWriter) Write(p []byte) (int, error) {
+	var n int
+	for n < len(p) {
+		end := n + _ChunkSize
+		if end > len(p) {
+			end = len(p)
+		}
+
+		dchunk := &pb.Download{
+			Event: &pb.Download_Chunk{
+				Chunk: p[n:end],
+			},
+		}
+
+		if err := cw.stream.Send(dchunk); err != nil {
+			return n, err
+		}
+		n = end
+	}
+
+	return n, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/algao1/imgrepo"
+)
+
+func TestDigestReader(t *testing.T) {
+	data := []byte("hello world")
+	const sum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+	tests := map[string]struct {
+		declared string
+		want     string
+		wantSize int64
+		wantErr  error
+	}{
+		"undeclared": {declared: "", want: sum, wantSize: int64(len(data))},
+		"matching":   {declared: sum, want: sum, wantSize: int64(len(data))},
+		"mismatch":   {declared: "00", want: "", wantErr: imgrepo.ErrDigestMismatch},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := imgrepo.Image{Digest: tc.declared}
+
+			got, err := io.ReadAll(newDigestReader(bytes.NewReader(data), &img))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatal("read bytes differ from source bytes")
+			}
+			if img.Digest != tc.want {
+				t.Fatalf("got digest %q, want %q", img.Digest, tc.want)
+			}
+			if img.Size != tc.wantSize {
+				t.Fatalf("got size %d, want %d", img.Size, tc.wantSize)
+			}
+		})
+	}
+}
+
+func TestLimitReader(t *testing.T) {
+	data := []byte("hello world")
+
+	tests := map[string]struct {
+		max     int64
+		wantErr error
+	}{
+		"under":    {max: 100},
+		"exact":    {max: int64(len(data))},
+		"over":     {max: int64(len(data)) - 1, wantErr: imgrepo.ErrTooLarge},
+		"zero":     {max: 0, wantErr: imgrepo.ErrTooLarge},
+		"negative": {max: -1, wantErr: imgrepo.ErrTooLarge},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// One byte at a time, so that the limit is crossed mid stream.
+			got, err := io.ReadAll(newLimitReader(iotest.OneByteReader(bytes.NewReader(data)), tc.max))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && !bytes.Equal(got, data) {
+				t.Fatal("read bytes differ from source bytes")
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/algao1/imgrepo"
+)
+
+// _MaxTags is the largest number of tags an image, or a filter, may have.
+const _MaxTags = 32
+
+// _MaxTagLen is the longest a tag may be, in bytes.
+const _MaxTagLen = 64
+
+// toTags returns the tags in lower case, sorted and without duplicates, so
+// that they compare equal however they were typed. Tags may not be empty,
+// or hold spaces or commas, which separate them in the client.
+func toTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var res []string
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || len(t) > _MaxTagLen || strings.ContainsRune(t, ',') || strings.IndexFunc(t, unicode.IsSpace) >= 0 {
+			return nil, fmt.Errorf("invalid tag %q: %w", t, imgrepo.ErrInvalidArgument)
+		}
+		if !seen[t] {
+			seen[t] = true
+			res = append(res, t)
+		}
+	}
+
+	if len(res) > _MaxTags {
+		return nil, fmt.Errorf("%d tags, more than %d: %w", len(res), _MaxTags, imgrepo.ErrInvalidArgument)
+	}
+	sort.Strings(res)
+
+	return res, nil
+}
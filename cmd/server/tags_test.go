@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestToTags(t *testing.T) {
+	many := make([]string, _MaxTags+1)
+	for i := range many {
+		many[i] = strings.Repeat("a", i+1)
+	}
+
+	tests := map[string]struct {
+		tags    []string
+		want    []string
+		wantErr error
+	}{
+		"none":       {},
+		"normalized": {tags: []string{" Red", "fruit", "RED"}, want: []string{"fruit", "red"}},
+		"empty":      {tags: []string{"red", " "}, wantErr: imgrepo.ErrInvalidArgument},
+		"space":      {tags: []string{"red fruit"}, wantErr: imgrepo.ErrInvalidArgument},
+		"comma":      {tags: []string{"red,fruit"}, wantErr: imgrepo.ErrInvalidArgument},
+		"too long":   {tags: []string{strings.Repeat("a", _MaxTagLen+1)}, wantErr: imgrepo.ErrInvalidArgument},
+		"too many":   {tags: many, wantErr: imgrepo.ErrInvalidArgument},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := toTags(tc.tags)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("toTags() = _, %v, want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("toTags() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/image"
+	pb "github.com/algao1/imgrepo/proto"
+)
+
+// _TransformFormats maps the formats of transforms to their names.
+var _TransformFormats = map[pb.Transform_Format]string{
+	pb.Transform_ORIGINAL: "",
+	pb.Transform_JPEG:     "jpeg",
+	pb.Transform_PNG:      "png",
+	pb.Transform_WEBP:     "webp",
+	pb.Transform_GIF:      "gif",
+}
+
+// _Extensions maps the formats of images to their usual file extensions.
+var _Extensions = map[string]string{
+	"jpeg": ".jpg",
+	"png":  ".png",
+	"webp": ".webp",
+	"gif":  ".gif",
+}
+
+// toTransform converts a transform request, and checks that it is valid.
+func toTransform(t *pb.Transform) (imgrepo.Transform, error) {
+	format, ok := _TransformFormats[t.Format]
+	if !ok {
+		return imgrepo.Transform{}, fmt.Errorf("unknown format %d: %w", t.Format, imgrepo.ErrInvalidArgument)
+	}
+
+	res := imgrepo.Transform{
+		Width:   int(t.Width),
+		Height:  int(t.Height),
+		Fit:     imgrepo.Fit(t.Fit),
+		Format:  format,
+		Quality: int(t.Quality),
+	}
+	if err := image.ValidateTransform(res); err != nil {
+		return imgrepo.Transform{}, err
+	}
+
+	return res, nil
+}
+
+// transformedName returns the file name of an image converted to the given
+// format.
+func transformedName(name, format string) string {
+	return strings.TrimSuffix(name, path.Ext(name)) + _Extensions[format]
+}
+
+// downloadTransformed streams the image of the request converted by its
+// transform. Converted images are cached by digest and transform, since
+// Replace changes the contents of an image but keeps its id.
+func (s *repoServer) downloadTransformed(req *pb.DownloadRequest, stream pb.Repo_DownloadImageServer) error {
+	if req.Rendition != 0 {
+		return fmt.Errorf("%q: %w", "rendition and transform are exclusive", imgrepo.ErrInvalidArgument)
+	}
+
+	t, err := toTransform(req.Transform)
+	if err != nil {
+		return err
+	}
+
+	requester := userFromContext(stream.Context())
+	reader, err := s.reader(requester, req)
+	if err != nil {
+		return err
+	}
+
+	imgs, err := s.ir.Find(reader, req.Id)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to find image", err)
+	}
+	if len(imgs) == 0 {
+		return fmt.Errorf("file %s: %w", req.Id, imgrepo.ErrNotFound)
+	}
+	img := imgs[0]
+
+	out, ok := s.cache.Get(t.Key(img.Digest))
+	if !ok {
+		// The image may have been replaced since it was found, so the
+		// downloaded one gives the digest of what is transformed.
+		var rc io.ReadCloser
+		img, rc, err = s.ir.Download(reader, img.Id)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to download raw image", err)
+		}
+		defer rc.Close()
+
+		out, err = s.tf.Transform(rc, t)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to transform image", err)
+		}
+		s.cache.Put(t.Key(img.Digest), out)
+	}
+
+	info := fileInfo(requester, img)
+	info.FileName = transformedName(img.Name, out.Format)
+
+	return sendFile(stream, info, bytes.NewReader(out.Data))
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/image"
+	"github.com/algao1/imgrepo/memory"
+	pb "github.com/algao1/imgrepo/proto"
+)
+
+func TestToTransform(t *testing.T) {
+	tests := map[string]struct {
+		req     *pb.Transform
+		want    imgrepo.Transform
+		wantErr error
+	}{
+		"original": {
+			req:  &pb.Transform{},
+			want: imgrepo.Transform{},
+		},
+		"cover webp": {
+			req:  &pb.Transform{Width: 64, Height: 32, Fit: pb.Transform_COVER, Format: pb.Transform_WEBP, Quality: 70},
+			want: imgrepo.Transform{Width: 64, Height: 32, Fit: imgrepo.Cover, Format: "webp", Quality: 70},
+		},
+		"unknown format": {
+			req:     &pb.Transform{Format: pb.Transform_Format(42)},
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+		"unknown fit": {
+			req:     &pb.Transform{Fit: pb.Transform_Fit(42)},
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+		"negative width": {
+			req:     &pb.Transform{Width: -1},
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := toTransform(tc.req)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got %v, want %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTransformedName(t *testing.T) {
+	tests := map[string]struct {
+		name   string
+		format string
+		want   string
+	}{
+		"jpeg":         {name: "apple.png", format: "jpeg", want: "apple.jpg"},
+		"same":         {name: "apple.png", format: "png", want: "apple.png"},
+		"no extension": {name: "apple", format: "webp", want: "apple.webp"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := transformedName(tc.name, tc.format); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDownloadTransformedReplaced(t *testing.T) {
+	s := &repoServer{
+		ir:    memory.NewImageRegistry(memory.NewImageStorage(), memory.NewGroupService()),
+		tf:    image.NewTransformer(),
+		cache: memory.NewImageCache(1 << 20),
+	}
+	ctx := context.WithValue(context.Background(), userKey{}, "test")
+
+	load := func(name string) (*imgrepo.Image, []byte) {
+		data, err := os.ReadFile("../../_data/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(data)
+		return &imgrepo.Image{Name: name, Owner: "test", Digest: hex.EncodeToString(sum[:]), Size: int64(len(data))}, data
+	}
+	download := func(id string) []byte {
+		req := &pb.DownloadRequest{Id: id, Transform: &pb.Transform{Width: 32, Format: pb.Transform_PNG}}
+		stream := &downloadStream{ctx: ctx}
+		if err := s.downloadTransformed(req, stream); err != nil {
+			t.Fatal(err)
+		}
+		return stream.data
+	}
+
+	img, data := load("apple1.jpg")
+	if err := s.ir.Upload(img, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	before := download(img.Id)
+
+	// The cached transform of the old contents must not be served.
+	repl, data := load("banana1.jpg")
+	repl.Id = img.Id
+	if _, err := s.ir.Replace("test", img.Digest, repl, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if after := download(img.Id); bytes.Equal(after, before) {
+		t.Fatal("downloadTransformed() after Replace returned the old image")
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	pb "github.com/algao1/imgrepo/proto"
+)
+
+// _UpdatePaths maps the paths of update masks to the fields of images.
+var _UpdatePaths = map[string]imgrepo.Field{
+	"file_name":         imgrepo.NameField,
+	"access":            imgrepo.AccessField,
+	"tags":              imgrepo.TagsField,
+	"metadata.taken":    imgrepo.TakenField,
+	"metadata.location": imgrepo.LocationField,
+}
+
+// UpdateImage sets the fields of an image owned by the requester named by
+// the update mask, and responds with the updated file info. A private image
+// made public is stripped according to the privacy policy of public images,
+// like on upload, and keeps its id.
+func (s *repoServer) UpdateImage(ctx context.Context, req *pb.UpdateRequest) (*pb.FileInfo, error) {
+	img, fields, err := toUpdate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	requester := userFromContext(ctx)
+	publish, fields := s.publishes(img, fields)
+
+	var res *imgrepo.Image
+	if len(fields) > 0 {
+		res, err = s.ir.Update(requester, img, fields...)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to update image", err)
+		}
+		log.Printf("updated %v of image %s", fields, res.Id)
+	}
+
+	if publish {
+		res, err = s.publish(requester, img.Id)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to make image public", err)
+		}
+	}
+
+	return fileInfo(requester, res), nil
+}
+
+// publishes reports whether the update makes the image public, with a privacy
+// policy that strips public images, in which case the access is left to
+// publish, and returns the other fields to update.
+func (s *repoServer) publishes(img *imgrepo.Image, fields []imgrepo.Field) (bool, []imgrepo.Field) {
+	if img.Access != imgrepo.Public || s.publicPrivacy == imgrepo.KeepMetadata {
+		return false, fields
+	}
+
+	var rest []imgrepo.Field
+	for _, f := range fields {
+		if f != imgrepo.AccessField {
+			rest = append(rest, f)
+		}
+	}
+
+	return len(rest) < len(fields), rest
+}
+
+// publish makes the image with the given id public, stripped according to
+// the privacy policy of public images. The stripped image replaces the stored
+// one under the same id, and only if the stored one is still the one read, so
+// that concurrent updates can neither skip the stripping nor repeat it.
+// Images already public are left as they are.
+func (s *repoServer) publish(requester, id string) (*imgrepo.Image, error) {
+	img, rc, err := s.ir.Download(requester, id)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to download image", err)
+	}
+	defer rc.Close()
+
+	if img.Owner != requester {
+		return nil, fmt.Errorf("unable to update file %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+	if img.Access == imgrepo.Public {
+		return img, nil
+	}
+
+	// The image is read once, so that the renditions and the stripped image
+	// come from the same blob.
+	f, err := spool(rc)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to read image", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	rends, err := s.rd.Render(f)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to render image", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to rewind image", err)
+	}
+
+	pub := *img
+	pub.Access = imgrepo.Public
+	pub.Metadata = redact(img.Metadata, s.publicPrivacy)
+	sf, err := s.strip(&pub, f)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to strip image", err)
+	}
+	defer os.Remove(sf.Name())
+	defer sf.Close()
+
+	res, err := s.ir.Replace(requester, img.Digest, &pub, sf, rends...)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to replace image", err)
+	}
+	log.Printf("made image %s public", id)
+
+	return res, nil
+}
+
+// toUpdate converts an update request, and checks the values of the fields
+// in its mask.
+func toUpdate(req *pb.UpdateRequest) (*imgrepo.Image, []imgrepo.Field, error) {
+	finfo := req.GetFileInfo()
+	img := &imgrepo.Image{
+		Id:     req.Id,
+		Name:   finfo.GetFileName(),
+		Access: imgrepo.Permission(finfo.GetAccess()),
+	}
+
+	md := finfo.GetMetadata()
+	if md.GetTaken() != 0 {
+		img.Metadata.Taken = time.Unix(md.GetTaken(), 0).UTC()
+	}
+	if loc := md.GetLocation(); loc != nil {
+		img.Metadata.Location = &imgrepo.Location{Latitude: loc.GetLatitude(), Longitude: loc.GetLongitude()}
+	}
+
+	var fields []imgrepo.Field
+	for _, path := range req.GetUpdateMask().GetPaths() {
+		f, ok := _UpdatePaths[path]
+		if !ok {
+			return nil, nil, fmt.Errorf("field %q cannot be updated: %w", path, imgrepo.ErrInvalidArgument)
+		}
+
+		loc := img.Metadata.Location
+		switch {
+		case f == imgrepo.NameField && (img.Name == "" || strings.ContainsAny(img.Name, `/\`)):
+			return nil, nil, fmt.Errorf("invalid file name %q: %w", img.Name, imgrepo.ErrInvalidArgument)
+		case f == imgrepo.AccessField:
+			if err := checkAccess(img.Access); err != nil {
+				return nil, nil, err
+			}
+		case f == imgrepo.LocationField && loc != nil && (loc.Latitude < -90 || loc.Latitude > 90 || loc.Longitude < -180 || loc.Longitude > 180):
+			return nil, nil, fmt.Errorf("location %v out of range: %w", *loc, imgrepo.ErrInvalidArgument)
+		case f == imgrepo.TagsField:
+			tags, err := toTags(finfo.GetTags())
+			if err != nil {
+				return nil, nil, err
+			}
+			img.Tags = tags
+		}
+
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, nil, fmt.Errorf("%q: %w", "no fields to update", imgrepo.ErrInvalidArgument)
+	}
+
+	return img, fields, nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/image"
+	"github.com/algao1/imgrepo/memory"
+	pb "github.com/algao1/imgrepo/proto"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestToUpdate(t *testing.T) {
+	update := func(finfo *pb.FileInfo, paths ...string) *pb.UpdateRequest {
+		return &pb.UpdateRequest{Id: "x", FileInfo: finfo, UpdateMask: &fieldmaskpb.FieldMask{Paths: paths}}
+	}
+
+	tests := map[string]struct {
+		req        *pb.UpdateRequest
+		want       *imgrepo.Image
+		wantFields []imgrepo.Field
+		wantErr    error
+	}{
+		"rename": {
+			req:        update(&pb.FileInfo{FileName: "renamed.jpg"}, "file_name"),
+			want:       &imgrepo.Image{Id: "x", Name: "renamed.jpg"},
+			wantFields: []imgrepo.Field{imgrepo.NameField},
+		},
+		"make private": {
+			req:        update(&pb.FileInfo{Access: 1}, "access"),
+			want:       &imgrepo.Image{Id: "x", Access: imgrepo.Private},
+			wantFields: []imgrepo.Field{imgrepo.AccessField},
+		},
+		"edit metadata": {
+			req: update(&pb.FileInfo{Metadata: &pb.Metadata{
+				Taken:    1621247400,
+				Location: &pb.Location{Latitude: 48.858333, Longitude: -122.42},
+			}}, "metadata.taken", "metadata.location"),
+			want: &imgrepo.Image{Id: "x", Metadata: imgrepo.Metadata{
+				Taken:    time.Date(2021, 5, 17, 10, 30, 0, 0, time.UTC),
+				Location: &imgrepo.Location{Latitude: 48.858333, Longitude: -122.42},
+			}},
+			wantFields: []imgrepo.Field{imgrepo.TakenField, imgrepo.LocationField},
+		},
+		"retag": {
+			req:        update(&pb.FileInfo{Tags: []string{"Red", "fruit"}}, "tags"),
+			want:       &imgrepo.Image{Id: "x", Tags: []string{"fruit", "red"}},
+			wantFields: []imgrepo.Field{imgrepo.TagsField},
+		},
+		"clear tags": {
+			req:        update(nil, "tags"),
+			want:       &imgrepo.Image{Id: "x"},
+			wantFields: []imgrepo.Field{imgrepo.TagsField},
+		},
+		"clear location": {
+			req:        update(nil, "metadata.location"),
+			want:       &imgrepo.Image{Id: "x"},
+			wantFields: []imgrepo.Field{imgrepo.LocationField},
+		},
+		"empty mask": {
+			req:     update(&pb.FileInfo{FileName: "renamed.jpg"}),
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+		"immutable field": {
+			req:     update(&pb.FileInfo{Owner: "test2"}, "owner"),
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+		"empty name": {
+			req:     update(&pb.FileInfo{}, "file_name"),
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+		"path in name": {
+			req:     update(&pb.FileInfo{FileName: "../renamed.jpg"}, "file_name"),
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+		"unknown access": {
+			req:     update(&pb.FileInfo{Access: 42}, "access"),
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+		"invalid tag": {
+			req:     update(&pb.FileInfo{Tags: []string{"red fruit"}}, "tags"),
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+		"location out of range": {
+			req:     update(&pb.FileInfo{Metadata: &pb.Metadata{Location: &pb.Location{Latitude: 91}}}, "metadata.location"),
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, fields, err := toUpdate(tc.req)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("toUpdate() = _, _, %v, want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("toUpdate() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantFields, fields); diff != "" {
+				t.Errorf("toUpdate() fields mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateAccess(t *testing.T) {
+	const secret = "48.858333N 122.42W"
+	data := withSecret(t, secret)
+	loc := &imgrepo.Location{Latitude: 48.858333, Longitude: -122.42}
+
+	tests := map[string]struct {
+		access        imgrepo.Permission
+		update        imgrepo.Permission
+		publicPrivacy imgrepo.Privacy
+		requester     string
+		wantErr       error
+		wantStrip     bool
+	}{
+		"make public":         {access: imgrepo.Private, update: imgrepo.Public, publicPrivacy: imgrepo.StripLocation, wantStrip: true},
+		"make public keep":    {access: imgrepo.Private, update: imgrepo.Public, publicPrivacy: imgrepo.KeepMetadata},
+		"already public":      {access: imgrepo.Public, update: imgrepo.Public, publicPrivacy: imgrepo.StripLocation},
+		"make private":        {access: imgrepo.Public, update: imgrepo.Private, publicPrivacy: imgrepo.StripLocation},
+		"make public not own": {access: imgrepo.Private, update: imgrepo.Public, publicPrivacy: imgrepo.StripLocation, requester: "test2", wantErr: imgrepo.ErrPermissionDenied},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rd, err := image.NewRenderer(128)
+			if err != nil {
+				t.Fatal(err)
+			}
+			is := memory.NewImageStorage()
+			s := &repoServer{
+				ir:  memory.NewImageRegistry(is, memory.NewGroupService()),
+				idx: image.NewIndex(0),
+				rd:  rd,
+				st:  image.NewStripper(),
+
+				publicPrivacy: tc.publicPrivacy,
+			}
+
+			img := &imgrepo.Image{Owner: "test", Access: tc.access, Digest: "original", Metadata: imgrepo.Metadata{Location: loc}}
+			if err := s.ir.Upload(img, bytes.NewReader(data)); err != nil {
+				t.Fatal(err)
+			}
+
+			requester := "test"
+			if tc.requester != "" {
+				requester = tc.requester
+			}
+			ctx := context.WithValue(context.Background(), userKey{}, requester)
+			req := &pb.UpdateRequest{
+				Id:         img.Id,
+				FileInfo:   &pb.FileInfo{Access: int32(tc.update)},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"access"}},
+			}
+
+			finfo, err := s.UpdateImage(ctx, req)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("UpdateImage() = _, %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				// A refused update leaves the image as it was.
+				if got, err := s.ir.Stat(img.Id); err != nil || got.Access != tc.access {
+					t.Errorf("Stat() = %v, %v, want access %d", got, err, tc.access)
+				}
+				return
+			}
+
+			got, rc, err := s.ir.Download("test", finfo.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rc.Close()
+			stored, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Access != tc.update {
+				t.Errorf("UpdateImage() access = %d, want %d", got.Access, tc.update)
+			}
+			if leaked := bytes.Contains(stored, []byte(secret)); leaked == tc.wantStrip {
+				t.Errorf("UpdateImage() stored the secret = %v, want %v", leaked, !tc.wantStrip)
+			}
+			if redacted := got.Metadata.Location == nil; redacted != tc.wantStrip {
+				t.Errorf("UpdateImage() redacted location = %v, want %v", redacted, tc.wantStrip)
+			}
+
+			// Stripped images replace the original under the same id.
+			if finfo.Id != img.Id {
+				t.Errorf("UpdateImage() id = %s, want %s", finfo.Id, img.Id)
+			}
+			if _, err := is.Download("original"); tc.wantStrip && !errors.Is(err, imgrepo.ErrNotFound) {
+				t.Errorf("Download() of original blob = %v, want %v", err, imgrepo.ErrNotFound)
+			}
+			if tc.wantStrip && len(got.Renditions) != 1 {
+				t.Errorf("UpdateImage() renditions = %v, want 1", got.Renditions)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	pb "github.com/algao1/imgrepo/proto"
+)
+
+// _MinUploadTTL is the shortest time uploads may be kept, so that sweeping
+// them twice per ttl is neither constant nor disabled by a zero interval.
+const _MinUploadTTL = time.Minute
+
+// BeginUpload starts a resumable upload owned by the requester.
+func (s *repoServer) BeginUpload(ctx context.Context, req *pb.BeginUploadRequest) (*pb.UploadStatus, error) {
+	finfo := req.GetFileInfo()
+	if finfo == nil {
+		return nil, fmt.Errorf("no file info received: %w", imgrepo.ErrInvalidArgument)
+	}
+
+	tags, err := toTags(finfo.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// The owner is always the logged in user, so finfo.Owner is ignored.
+	img := imgrepo.Image{
+		Name:   finfo.FileName,
+		Owner:  userFromContext(ctx),
+		Access: imgrepo.Permission(finfo.Access),
+		Digest: finfo.Digest,
+		Tags:   tags,
+	}
+	if err := checkAccess(img.Access); err != nil {
+		return nil, err
+	}
+
+	id, err := s.ups.Begin(&img)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to begin upload", err)
+	}
+	log.Printf("began upload %s", id)
+
+	return &pb.UploadStatus{UploadId: id}, nil
+}
+
+// WriteUpload appends a stream of contiguous chunks to a resumable upload,
+// and responds with the committed offset. If the stream is interrupted,
+// the chunks received so far stay committed.
+func (s *repoServer) WriteUpload(stream pb.Repo_WriteUploadServer) error {
+	in, err := stream.Recv()
+	if err == io.EOF {
+		return fmt.Errorf("no chunks received: %w", imgrepo.ErrInvalidArgument)
+	} else if err != nil {
+		return err
+	}
+
+	id := in.UploadId
+	if _, _, err := s.queryUpload(userFromContext(stream.Context()), id); err != nil {
+		return err
+	}
+
+	// A negative offset would also raise the size limit below.
+	if in.Offset < 0 {
+		return fmt.Errorf("negative offset %d: %w", in.Offset, imgrepo.ErrInvalidArgument)
+	}
+
+	// An upload past the size limit can never be committed, so it is
+	// discarded.
+	offset, err := s.ups.Write(id, in.Offset, newLimitReader(newOffsetReader(stream, in), s.maxSize-in.Offset))
+	if errors.Is(err, imgrepo.ErrTooLarge) {
+		if rerr := s.ups.Remove(id); rerr != nil {
+			log.Printf("unable to remove oversized upload %s: %v", id, rerr)
+		}
+		return fmt.Errorf("upload %s: %w", id, err)
+	}
+	if err != nil {
+		return fmt.Errorf("upload %s committed up to %d: %w", id, offset, err)
+	}
+
+	return stream.SendAndClose(&pb.UploadStatus{UploadId: id, CommittedOffset: offset})
+}
+
+// QueryUpload reports the committed offset of a resumable upload.
+func (s *repoServer) QueryUpload(ctx context.Context, req *pb.QueryUploadRequest) (*pb.UploadStatus, error) {
+	_, offset, err := s.queryUpload(userFromContext(ctx), req.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UploadStatus{UploadId: req.UploadId, CommittedOffset: offset}, nil
+}
+
+// CommitUpload adds the data of a resumable upload to the image repository,
+// and discards the upload. The upload is rejected if its digest differs from
+// the one declared by BeginUpload, and the duplicate and privacy policies of
+// the request apply to it like to UploadImage. The upload is claimed during
+// the commit, so writes and other commits of it are refused.
+func (s *repoServer) CommitUpload(ctx context.Context, req *pb.CommitUploadRequest) (*pb.UploadResponse, error) {
+	if _, _, err := s.queryUpload(userFromContext(ctx), req.UploadId); err != nil {
+		return nil, err
+	}
+
+	release, err := s.ups.Claim(req.UploadId)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to claim upload", err)
+	}
+	defer release()
+
+	// Every pass reads the same data, up to the length committed when the
+	// upload was claimed.
+	img, size, err := s.ups.Query(req.UploadId)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find upload", err)
+	}
+
+	privacy, err := s.privacy(img, imgrepo.Privacy(req.Privacy))
+	if err != nil {
+		return nil, err
+	}
+
+	// The upload is read first to validate it, and compute the digest the
+	// registry stores the blob under and the perceptual hash, then to inspect
+	// and render it, and last to store it.
+	err = s.readUpload(req.UploadId, size, func(r io.Reader) error {
+		dr := newDigestReader(r, img)
+		vr, err := s.validated(dr)
+		if err != nil {
+			return err
+		}
+		if err := s.ic.SetHash(img, vr); err != nil {
+			return err
+		}
+
+		// The decoder may stop before the end of the data.
+		_, err = io.Copy(io.Discard, dr)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to upload image", err)
+	}
+
+	// Skipped and rejected duplicates will never be committed, so the upload
+	// is discarded.
+	dups, skip, err := s.checkDuplicates(img, imgrepo.DuplicatePolicy(req.OnDuplicate))
+	if skip || errors.Is(err, imgrepo.ErrAlreadyExists) {
+		if rerr := s.ups.Remove(req.UploadId); rerr != nil {
+			log.Printf("unable to remove duplicate upload %s: %v", req.UploadId, rerr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if skip {
+		log.Printf("skipped upload %s as duplicate of %v", req.UploadId, dups)
+		return &pb.UploadResponse{Duplicates: dups, Skipped: true}, nil
+	}
+
+	err = s.readUpload(req.UploadId, size, func(r io.Reader) error {
+		return s.mi.Inspect(img, r)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to inspect image", err)
+	}
+
+	var rends []imgrepo.Rendition
+	err = s.readUpload(req.UploadId, size, func(r io.Reader) error {
+		rends, err = s.rd.Render(r)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to render image", err)
+	}
+
+	img.Id = ""
+	err = s.readUpload(req.UploadId, size, func(r io.Reader) error {
+		return s.upload(img, r, privacy, rends)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to upload image", err)
+	}
+
+	s.indexImage(img)
+
+	if err := s.ups.Remove(req.UploadId); err != nil {
+		log.Printf("unable to remove committed upload %s: %v", req.UploadId, err)
+	}
+	log.Printf("committed upload %s as %s", req.UploadId, img.Id)
+
+	return &pb.UploadResponse{Id: img.Id, Duplicates: dups}, nil
+}
+
+// sweepUploads discards the uploads not written for longer than ttl, which
+// were abandoned by their clients. It checks twice per ttl, which is at least
+// _MinUploadTTL, and never returns.
+func (s *repoServer) sweepUploads(ttl time.Duration) {
+	for range time.Tick(ttl / 2) {
+		n, err := s.ups.Sweep(time.Now().Add(-ttl))
+		if err != nil {
+			log.Printf("unable to sweep uploads: %v", err)
+		}
+		if n > 0 {
+			log.Printf("discarded %d abandoned uploads", n)
+		}
+	}
+}
+
+// readUpload calls fn with the first size bytes of the data of the upload.
+func (s *repoServer) readUpload(id string, size int64, fn func(r io.Reader) error) error {
+	rc, err := s.ups.Open(id)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to open upload", err)
+	}
+	defer rc.Close()
+
+	return fn(io.LimitReader(rc, size))
+}
+
+// queryUpload returns the image and committed offset of the upload, which
+// must belong to the requester.
+func (s *repoServer) queryUpload(requester, id string) (*imgrepo.Image, int64, error) {
+	img, offset, err := s.ups.Query(id)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%q: %w", "unable to find upload", err)
+	}
+
+	if img.Owner != requester {
+		return nil, 0, fmt.Errorf("unable to access upload %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	return img, offset, nil
+}
+
+// offsetReader reads the data of a WriteUpload stream, checking that every
+// chunk continues where the previous one ended.
+type offsetReader struct {
+	stream pb.Repo_WriteUploadServer
+	chunk  *pb.UploadChunk
+	buf    []byte
+}
+
+func newOffsetReader(stream pb.Repo_WriteUploadServer, first *pb.UploadChunk) *offsetReader {
+	return &offsetReader{stream: stream, chunk: first, buf: first.Data}
+}
+
+func (or *offsetReader) Read(p []byte) (int, error) {
+	for len(or.buf) == 0 {
+		in, err := or.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+
+		end := or.chunk.Offset + int64(len(or.chunk.Data))
+		if in.UploadId != or.chunk.UploadId || in.Offset != end {
+			return 0, fmt.Errorf("chunk at offset %d does not follow offset %d: %w", in.Offset, end, imgrepo.ErrInvalidArgument)
+		}
+		or.chunk, or.buf = in, in.Data
+	}
+
+	n := copy(p, or.buf)
+	or.buf = or.buf[n:]
+
+	return n, nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/image"
+	"github.com/algao1/imgrepo/memory"
+	pb "github.com/algao1/imgrepo/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBeginUpload(t *testing.T) {
+	tests := map[string]struct {
+		finfo   *pb.FileInfo
+		wantErr error
+	}{
+		"public":         {finfo: &pb.FileInfo{FileName: "a.jpg", Access: 0}},
+		"private":        {finfo: &pb.FileInfo{FileName: "a.jpg", Access: 1}},
+		"unknown access": {finfo: &pb.FileInfo{FileName: "a.jpg", Access: 42}, wantErr: imgrepo.ErrInvalidArgument},
+		"negative":       {finfo: &pb.FileInfo{FileName: "a.jpg", Access: -1}, wantErr: imgrepo.ErrInvalidArgument},
+		"no file info":   {wantErr: imgrepo.ErrInvalidArgument},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &repoServer{ups: memory.NewUploadStore()}
+
+			ctx := context.WithValue(context.Background(), userKey{}, "test")
+			got, err := s.BeginUpload(ctx, &pb.BeginUploadRequest{FileInfo: tc.finfo})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("BeginUpload() = _, %v, want %v", err, tc.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			img, _, err := s.ups.Query(got.UploadId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if img.Access != imgrepo.Permission(tc.finfo.Access) {
+				t.Errorf("Query() access = %d, want %d", img.Access, tc.finfo.Access)
+			}
+		})
+	}
+}
+
+// writeStream feeds chunks to WriteUpload, then fails with err, or ends the
+// stream if err is nil.
+type writeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks []*pb.UploadChunk
+	err    error
+	status *pb.UploadStatus
+}
+
+func (ws *writeStream) Context() context.Context {
+	return ws.ctx
+}
+
+func (ws *writeStream) Recv() (*pb.UploadChunk, error) {
+	if len(ws.chunks) == 0 {
+		if ws.err != nil {
+			return nil, ws.err
+		}
+		return nil, io.EOF
+	}
+
+	chunk := ws.chunks[0]
+	ws.chunks = ws.chunks[1:]
+	return chunk, nil
+}
+
+func (ws *writeStream) SendAndClose(st *pb.UploadStatus) error {
+	ws.status = st
+	return nil
+}
+
+func TestResumableUpload(t *testing.T) {
+	data, err := os.ReadFile("../../_data/apple1.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+
+	iv, err := image.NewValidator(image.DecodableTypes, 50000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ic, err := image.NewComparator(image.PerceptualHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rd, err := image.NewRenderer(128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &repoServer{
+		ir:  memory.NewImageRegistry(memory.NewImageStorage(), memory.NewGroupService()),
+		ups: memory.NewUploadStore(),
+		iv:  iv,
+		ic:  ic,
+		mi:  image.NewInspector(),
+		idx: image.NewIndex(image.PerceptualHash),
+		rd:  rd,
+		st:  image.NewStripper(),
+
+		maxSize:       32 << 20,
+		publicPrivacy: imgrepo.StripLocation,
+	}
+	ctx := context.WithValue(context.Background(), userKey{}, "test")
+
+	began, err := s.BeginUpload(ctx, &pb.BeginUploadRequest{FileInfo: &pb.FileInfo{
+		FileName: "apple1.jpg",
+		Access:   int32(imgrepo.Private),
+		Digest:   hex.EncodeToString(sum[:]),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := began.UploadId
+
+	chunk := func(from, to int) *pb.UploadChunk {
+		return &pb.UploadChunk{UploadId: id, Offset: int64(from), Data: data[from:to]}
+	}
+	half := len(data) / 2
+
+	// The chunks received before the stream breaks stay committed.
+	stream := &writeStream{ctx: ctx, chunks: []*pb.UploadChunk{chunk(0, 1000), chunk(1000, half)}, err: status.Error(codes.Unavailable, "broken")}
+	if err := s.WriteUpload(stream); status.Code(toStatus(err)) != codes.Unavailable {
+		t.Fatalf("WriteUpload() = %v, want %v", err, codes.Unavailable)
+	}
+
+	st, err := s.QueryUpload(ctx, &pb.QueryUploadRequest{UploadId: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.CommittedOffset != int64(half) {
+		t.Fatalf("QueryUpload() offset = %d, want %d", st.CommittedOffset, half)
+	}
+
+	// Negative offsets are refused, rather than raising the size limit.
+	stream = &writeStream{ctx: ctx, chunks: []*pb.UploadChunk{{UploadId: id, Offset: -1, Data: data}}}
+	if err := s.WriteUpload(stream); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+		t.Fatalf("WriteUpload() at negative offset = %v, want %v", err, imgrepo.ErrInvalidArgument)
+	}
+
+	// Resuming before the committed offset skips what was already written.
+	stream = &writeStream{ctx: ctx, chunks: []*pb.UploadChunk{chunk(half-100, len(data))}}
+	if err := s.WriteUpload(stream); err != nil {
+		t.Fatal(err)
+	}
+	if stream.status.CommittedOffset != int64(len(data)) {
+		t.Fatalf("WriteUpload() offset = %d, want %d", stream.status.CommittedOffset, len(data))
+	}
+
+	resp, err := s.CommitUpload(ctx, &pb.CommitUploadRequest{UploadId: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, rc, err := s.ir.Download("test", resp.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("CommitUpload() stored image differs from the uploaded one")
+	}
+
+	// Committed uploads are discarded.
+	if _, err := s.QueryUpload(ctx, &pb.QueryUploadRequest{UploadId: id}); !errors.Is(err, imgrepo.ErrNotFound) {
+		t.Fatalf("QueryUpload() after commit = %v, want %v", err, imgrepo.ErrNotFound)
+	}
+}
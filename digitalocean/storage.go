@@ -1,19 +1,22 @@
 package digitalocean
 
 import (
-	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/algao1/imgrepo"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
 type ImageStorage struct {
-	client *s3.S3
-	bucket string
+	client   *s3.S3
+	uploader *s3manager.Uploader
+	bucket   string
 }
 
 var _ imgrepo.ImageStorage = (*ImageStorage)(nil)
@@ -30,21 +33,29 @@ func NewImageStorage(key, secret, endpoint, region, bucket string) (*ImageStorag
 		return nil, fmt.Errorf("%q: %w", "unable to create spaces session", err)
 	}
 
-	return &ImageStorage{client: s3.New(newSession), bucket: bucket}, nil
+	client := s3.New(newSession)
+
+	return &ImageStorage{
+		client:   client,
+		uploader: s3manager.NewUploaderWithClient(client),
+		bucket:   bucket,
+	}, nil
 }
 
-func (is *ImageStorage) Upload(img *imgrepo.Image) error {
-	object := s3.PutObjectInput{
+// Upload streams the image to Spaces as a multipart upload, so at most
+// a few parts are buffered in memory regardless of the size of the image.
+func (is *ImageStorage) Upload(id string, r io.Reader) error {
+	object := s3manager.UploadInput{
 		Bucket: aws.String(is.bucket),
-		Key:    aws.String(img.Id),
-		Body:   bytes.NewReader(img.Raw),
+		Key:    aws.String(id),
+		Body:   r,
 		ACL:    aws.String("private"),
 		Metadata: map[string]*string{
 			"x-amz-meta-my-key": aws.String("your-value"), // required
 		},
 	}
 
-	_, err := is.client.PutObject(&object)
+	_, err := is.uploader.Upload(&object)
 	if err != nil {
 		return fmt.Errorf("%q: %w", "unable to upload file", err)
 	}
@@ -52,21 +63,22 @@ func (is *ImageStorage) Upload(img *imgrepo.Image) error {
 	return nil
 }
 
-func (is *ImageStorage) Download(id string) ([]byte, error) {
+// Download returns the body of the object, which is streamed from Spaces
+// as it is read.
+func (is *ImageStorage) Download(id string) (io.ReadCloser, error) {
 	input := &s3.GetObjectInput{
 		Bucket: aws.String(is.bucket),
 		Key:    aws.String(id),
 	}
 
 	result, err := is.client.GetObject(input)
-	if err != nil {
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	} else if err != nil {
 		return nil, fmt.Errorf("%q: %w", "unable to download file", err)
 	}
 
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(result.Body)
-
-	return buf.Bytes(), nil
+	return result.Body, nil
 }
 
 func (is *ImageStorage) Delete(id string) error {
@@ -4,10 +4,10 @@ import (
 	"bytes"
 	"crypto/rand"
 	"fmt"
+	"io"
 	"os"
 	"testing"
 
-	"github.com/algao1/imgrepo"
 	"github.com/joho/godotenv"
 )
 
@@ -45,23 +45,27 @@ func TestUploadDownload(t *testing.T) {
 	}
 
 	tests := map[string]struct {
-		image  *imgrepo.Image
+		id     string
+		data   []byte
 		search string
 		expect []byte
 		err    error
 	}{
 		"small": {
-			image:  &imgrepo.Image{Id: "__small", Raw: data[0]},
+			id:     "__small",
+			data:   data[0],
 			search: "__small",
 			expect: data[0],
 		},
 		"medium": {
-			image:  &imgrepo.Image{Id: "__medium", Raw: data[1]},
+			id:     "__medium",
+			data:   data[1],
 			search: "__medium",
 			expect: data[1],
 		},
 		"large": {
-			image:  &imgrepo.Image{Id: "__large", Raw: data[2]},
+			id:     "__large",
+			data:   data[2],
 			search: "__large",
 			expect: data[2],
 		},
@@ -74,11 +78,16 @@ func TestUploadDownload(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			if err = is.Upload(tc.image); err != nil {
+			if err = is.Upload(tc.id, bytes.NewReader(tc.data)); err != nil {
 				t.Fatal(err)
 			}
 
-			bt, err := is.Download(tc.search)
+			rc, err := is.Download(tc.search)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bt, err := io.ReadAll(rc)
+			rc.Close()
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -86,7 +95,7 @@ func TestUploadDownload(t *testing.T) {
 			if res := bytes.Compare(bt, tc.expect); res != 0 {
 				t.Fatalf("unable to delete %s", tc.search)
 			}
-			is.Delete(tc.image.Id)
+			is.Delete(tc.id)
 		})
 	}
 }
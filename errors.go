@@ -0,0 +1,17 @@
+package imgrepo
+
+import "errors"
+
+// Sentinel errors returned by the services. Implementations wrap them with
+// more context, so they should be checked with errors.Is.
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrUnauthenticated  = errors.New("unauthenticated")
+	ErrInvalidArgument  = errors.New("invalid argument")
+	ErrDigestMismatch   = errors.New("digest mismatch")
+	ErrTooLarge         = errors.New("too large")
+	ErrExpired          = errors.New("expired")
+	ErrConflict         = errors.New("conflict")
+)
@@ -0,0 +1,123 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/algao1/imgrepo"
+)
+
+// _ShardDepth and _ShardWidth determine how objects are spread across
+// directories, e.g. id 6098110218339517c1321fa7 is stored at 60/98/<id>.
+const (
+	_ShardDepth = 2
+	_ShardWidth = 2
+)
+
+// ImageStorage stores raw images as files in a directory tree.
+type ImageStorage struct {
+	root string
+}
+
+var _ imgrepo.ImageStorage = (*ImageStorage)(nil)
+
+// NewImageStorage returns an ImageStorage rooted at dir, creating the
+// directory if it does not exist.
+func NewImageStorage(dir string) (*ImageStorage, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create storage directory", err)
+	}
+
+	return &ImageStorage{root: dir}, nil
+}
+
+// path returns the location of the object with the given id, sharded by
+// the prefix of the id.
+func (is *ImageStorage) path(id string) (string, error) {
+	if id == "" || strings.HasPrefix(id, ".") || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("id %q: %w", id, imgrepo.ErrInvalidArgument)
+	}
+
+	elems := []string{is.root}
+	for i := 0; i < _ShardDepth && (i+1)*_ShardWidth < len(id); i++ {
+		elems = append(elems, id[i*_ShardWidth:(i+1)*_ShardWidth])
+	}
+
+	return filepath.Join(append(elems, id)...), nil
+}
+
+// Upload writes the image to a temporary file, and renames it into place
+// so that readers never observe a partially written object.
+func (is *ImageStorage) Upload(id string, r io.Reader) error {
+	path, err := is.path(id)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("%q: %w", "unable to create shard directory", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to create temporary file", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, r)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to write file", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to upload file", err)
+	}
+
+	return nil
+}
+
+func (is *ImageStorage) Download(id string) (io.ReadCloser, error) {
+	path, err := is.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to download file", err)
+	}
+
+	return file, nil
+}
+
+func (is *ImageStorage) Delete(id string) error {
+	path, err := is.path(id)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	} else if err != nil {
+		return fmt.Errorf("%q: %w", "unable to delete file", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package filesystem
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+)
+
+func randomBytes(len int) []byte {
+	token := make([]byte, len)
+	rand.Read(token)
+	return token
+}
+
+func TestUploadDownload(t *testing.T) {
+	data := [][]byte{
+		randomBytes(10),
+		randomBytes(1000),
+		randomBytes(100000),
+	}
+
+	tests := map[string]struct {
+		id     string
+		data   []byte
+		search string
+		expect []byte
+	}{
+		"small": {
+			id:     "__small",
+			data:   data[0],
+			search: "__small",
+			expect: data[0],
+		},
+		"medium": {
+			id:     "__medium",
+			data:   data[1],
+			search: "__medium",
+			expect: data[1],
+		},
+		"large": {
+			id:     "__large",
+			data:   data[2],
+			search: "__large",
+			expect: data[2],
+		},
+	}
+
+	is, err := NewImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err = is.Upload(tc.id, bytes.NewReader(tc.data)); err != nil {
+				t.Fatal(err)
+			}
+
+			rc, err := is.Download(tc.search)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bt, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res := bytes.Compare(bt, tc.expect); res != 0 {
+				t.Fatalf("downloaded bytes differ for %s", tc.search)
+			}
+
+			if err := is.Delete(tc.id); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := is.Download(tc.search); err == nil {
+				t.Fatalf("expected %s to be deleted", tc.search)
+			}
+		})
+	}
+}
+
+func TestShardedLayout(t *testing.T) {
+	root := t.TempDir()
+
+	is, err := NewImageStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := "6098110218339517c1321fa7"
+	if err := is.Upload(id, bytes.NewReader(randomBytes(100))); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "60", "98", id)); err != nil {
+		t.Fatalf("expected sharded object: %v", err)
+	}
+
+	// No temporary files should be left behind.
+	entries, err := os.ReadDir(filepath.Join(root, "60", "98"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries in shard, want 1", len(entries))
+	}
+}
+
+func TestInvalidIds(t *testing.T) {
+	is, err := NewImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"", ".", "..", "../escape", "a/b", `a\b`, ".upload-1"} {
+		if err := is.Upload(id, bytes.NewReader(nil)); err == nil {
+			t.Errorf("Upload(%q) succeeded, want error", id)
+		}
+		if _, err := is.Download(id); err == nil {
+			t.Errorf("Download(%q) succeeded, want error", id)
+		}
+	}
+}
+
+func TestInterruptedUpload(t *testing.T) {
+	root := t.TempDir()
+
+	is, err := NewImageStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := io.MultiReader(bytes.NewReader(randomBytes(100)), iotest.ErrReader(iotest.ErrTimeout))
+	if err := is.Upload("__interrupted", r); err == nil {
+		t.Fatal("expected error from interrupted upload")
+	}
+
+	if _, err := is.Download("__interrupted"); err == nil {
+		t.Fatal("interrupted upload is visible")
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "__", "in"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("got %d entries in shard, want 0", len(entries))
+	}
+}
@@ -0,0 +1,335 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"github.com/google/uuid"
+)
+
+// UploadStore keeps resumable uploads in a directory tree, with one
+// directory per upload holding the image info and the data received so far.
+// Since the state is kept on disk, uploads survive server restarts, but
+// claims do not.
+type UploadStore struct {
+	root string
+
+	mu      sync.Mutex
+	locks   map[string]*sync.Mutex
+	claimed map[string]bool
+}
+
+var _ imgrepo.UploadStore = (*UploadStore)(nil)
+
+// NewUploadStore returns an UploadStore rooted at dir, creating the
+// directory if it does not exist. Uploads already in dir can be resumed.
+func NewUploadStore(dir string) (*UploadStore, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create upload directory", err)
+	}
+
+	return &UploadStore{
+		root:    dir,
+		locks:   make(map[string]*sync.Mutex),
+		claimed: make(map[string]bool),
+	}, nil
+}
+
+// dir returns the directory holding the upload with the given id.
+func (us *UploadStore) dir(id string) (string, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return "", fmt.Errorf("upload id %q: %w", id, imgrepo.ErrInvalidArgument)
+	}
+
+	return filepath.Join(us.root, id), nil
+}
+
+// lock serializes access to the upload with the given id, and returns
+// the function releasing it.
+func (us *UploadStore) lock(id string) func() {
+	us.mu.Lock()
+	l, ok := us.locks[id]
+	if !ok {
+		l = new(sync.Mutex)
+		us.locks[id] = l
+	}
+	us.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+func (us *UploadStore) Begin(img *imgrepo.Image) (string, error) {
+	id := uuid.NewString()
+
+	dir, err := us.dir(id)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return "", fmt.Errorf("%q: %w", "unable to create upload", err)
+	}
+
+	data, err := os.Create(filepath.Join(dir, "data"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("%q: %w", "unable to create upload", err)
+	}
+	data.Close()
+
+	info, err := json.Marshal(img)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("%q: %w", "unable to encode image info", err)
+	}
+
+	// The info file is written last, and marks the upload as valid.
+	tmp := filepath.Join(dir, ".info")
+	err = os.WriteFile(tmp, info, 0644)
+	if err == nil {
+		err = os.Rename(tmp, filepath.Join(dir, "info.json"))
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("%q: %w", "unable to write image info", err)
+	}
+
+	return id, nil
+}
+
+func (us *UploadStore) Write(id string, offset int64, r io.Reader) (int64, error) {
+	dir, err := us.dir(id)
+	if err != nil {
+		return 0, err
+	}
+
+	unlock := us.lock(id)
+	defer unlock()
+
+	if _, _, err := us.query(id, dir); err != nil {
+		return 0, err
+	}
+	if us.isClaimed(id) {
+		return 0, fmt.Errorf("upload %s is being committed: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, "data"), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return 0, fmt.Errorf("%q: %w", "unable to open upload", err)
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("%q: %w", "unable to open upload", err)
+	}
+	size := st.Size()
+
+	if offset < 0 {
+		return size, fmt.Errorf("negative offset %d: %w", offset, imgrepo.ErrInvalidArgument)
+	} else if offset > size {
+		return size, fmt.Errorf("offset %d is past committed offset %d: %w", offset, size, imgrepo.ErrInvalidArgument)
+	}
+
+	// Skip the data that has already been committed.
+	_, err = io.CopyN(io.Discard, r, size-offset)
+	if err == io.EOF {
+		return size, nil
+	} else if err != nil {
+		return size, fmt.Errorf("%q: %w", "unable to read upload", err)
+	}
+
+	// Whatever was appended before an error is still valid, so it is synced
+	// and counted towards the committed offset.
+	n, err := io.Copy(f, r)
+	if serr := f.Sync(); err == nil {
+		err = serr
+	}
+	if err != nil {
+		return size + n, fmt.Errorf("%q: %w", "unable to write upload", err)
+	}
+
+	return size + n, nil
+}
+
+// isClaimed reports whether the upload with the given id is being committed.
+func (us *UploadStore) isClaimed(id string) bool {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	return us.claimed[id]
+}
+
+// query returns the image info and the committed offset of an upload.
+func (us *UploadStore) query(id, dir string) (*imgrepo.Image, int64, error) {
+	info, err := os.ReadFile(filepath.Join(dir, "info.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, fmt.Errorf("upload %s: %w", id, imgrepo.ErrNotFound)
+	} else if err != nil {
+		return nil, 0, fmt.Errorf("%q: %w", "unable to read image info", err)
+	}
+
+	var img imgrepo.Image
+	if err := json.Unmarshal(info, &img); err != nil {
+		return nil, 0, fmt.Errorf("%q: %w", "unable to decode image info", err)
+	}
+
+	st, err := os.Stat(filepath.Join(dir, "data"))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%q: %w", "unable to open upload", err)
+	}
+
+	return &img, st.Size(), nil
+}
+
+func (us *UploadStore) Query(id string) (*imgrepo.Image, int64, error) {
+	dir, err := us.dir(id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return us.query(id, dir)
+}
+
+func (us *UploadStore) Open(id string) (io.ReadCloser, error) {
+	dir, err := us.dir(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := us.query(id, dir); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(dir, "data"))
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to open upload", err)
+	}
+
+	return f, nil
+}
+
+func (us *UploadStore) Remove(id string) error {
+	dir, err := us.dir(id)
+	if err != nil {
+		return err
+	}
+
+	unlock := us.lock(id)
+	defer unlock()
+
+	if _, _, err := us.query(id, dir); err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(dir)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to remove upload", err)
+	}
+
+	us.mu.Lock()
+	delete(us.locks, id)
+	us.mu.Unlock()
+
+	return nil
+}
+
+func (us *UploadStore) Claim(id string) (func(), error) {
+	dir, err := us.dir(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Taking the upload lock waits for the writes in progress.
+	unlock := us.lock(id)
+	defer unlock()
+
+	if _, _, err := us.query(id, dir); err != nil {
+		return nil, err
+	}
+
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	if us.claimed[id] {
+		return nil, fmt.Errorf("upload %s is being committed: %w", id, imgrepo.ErrPermissionDenied)
+	}
+	us.claimed[id] = true
+
+	return func() {
+		us.mu.Lock()
+		delete(us.claimed, id)
+		us.mu.Unlock()
+	}, nil
+}
+
+func (us *UploadStore) Sweep(before time.Time) (int, error) {
+	entries, err := os.ReadDir(us.root)
+	if err != nil {
+		return 0, fmt.Errorf("%q: %w", "unable to list uploads", err)
+	}
+
+	n := 0
+	for _, e := range entries {
+		id := e.Name()
+		dir, err := us.dir(id)
+		if err != nil || !e.IsDir() {
+			continue
+		}
+
+		ok, err := us.sweep(id, dir, before)
+		if err != nil {
+			return n, err
+		}
+		if ok {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// sweep removes the upload with the given id if it was last written before
+// the given time, and is not claimed. Uploads interrupted during Begin have
+// no data, and are dated by their directory instead.
+func (us *UploadStore) sweep(id, dir string, before time.Time) (bool, error) {
+	unlock := us.lock(id)
+	defer unlock()
+
+	if us.isClaimed(id) {
+		return false, nil
+	}
+
+	st, err := os.Stat(filepath.Join(dir, "data"))
+	if errors.Is(err, fs.ErrNotExist) {
+		st, err = os.Stat(dir)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("%q: %w", "unable to stat upload", err)
+	}
+	if !st.ModTime().Before(before) {
+		return false, nil
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return false, fmt.Errorf("%q: %w", "unable to remove upload", err)
+	}
+
+	us.mu.Lock()
+	delete(us.locks, id)
+	us.mu.Unlock()
+
+	return true, nil
+}
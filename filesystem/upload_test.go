@@ -0,0 +1,183 @@
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/algao1/imgrepo"
+)
+
+func TestResumableUpload(t *testing.T) {
+	data := randomBytes(1000)
+	dir := t.TempDir()
+
+	us, err := NewUploadStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := us.Begin(&imgrepo.Image{Name: "test.png", Owner: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first write fails part way through.
+	r := io.MultiReader(bytes.NewReader(data[:400]), iotest.ErrReader(iotest.ErrTimeout))
+	off, err := us.Write(id, 0, r)
+	if err == nil {
+		t.Fatal("expected error from interrupted write")
+	}
+	if off != 400 {
+		t.Fatalf("expected committed offset 400, got %d", off)
+	}
+
+	// The upload survives a restart.
+	us, err = NewUploadStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, off, err := us.Query(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Name != "test.png" || img.Owner != "alice" || off != 400 {
+		t.Fatalf("unexpected upload state: %+v at %d", img, off)
+	}
+
+	// Writing past the committed offset leaves a gap.
+	if _, err := us.Write(id, 500, bytes.NewReader(data[500:])); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+
+	// A negative offset would skip data that was never committed.
+	if _, err := us.Write(id, -100, bytes.NewReader(data)); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+
+	// Overlapping data is skipped.
+	off, err = us.Write(id, 300, bytes.NewReader(data[300:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if off != int64(len(data)) {
+		t.Fatalf("expected committed offset %d, got %d", len(data), off)
+	}
+
+	rc, err := us.Open(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("uploaded bytes differ from written bytes")
+	}
+
+	if err := us.Remove(id); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := us.Query(id); !errors.Is(err, imgrepo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestInvalidUploadIds(t *testing.T) {
+	us, err := NewUploadStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"", "..", "../info.json", "not-a-uuid"} {
+		if _, _, err := us.Query(id); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+			t.Errorf("%q: expected ErrInvalidArgument, got %v", id, err)
+		}
+	}
+}
+
+func TestClaimUpload(t *testing.T) {
+	us, err := NewUploadStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := us.Begin(&imgrepo.Image{Name: "test.png", Owner: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := us.Claim(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A claimed upload can neither be written nor claimed again.
+	if _, err := us.Write(id, 0, bytes.NewReader(randomBytes(10))); !errors.Is(err, imgrepo.ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+	if _, err := us.Claim(id); !errors.Is(err, imgrepo.ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+
+	release()
+	if _, err := us.Write(id, 0, bytes.NewReader(randomBytes(10))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := us.Claim(id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSweepUploads(t *testing.T) {
+	dir := t.TempDir()
+	us, err := NewUploadStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make(map[string]string)
+	for _, name := range []string{"old", "claimed", "new"} {
+		id, err := us.Begin(&imgrepo.Image{Name: name + ".png"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = id
+	}
+	if _, err := us.Claim(ids["claimed"]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Uploads are dated by their last write.
+	past := time.Now().Add(-time.Hour)
+	for _, name := range []string{"old", "claimed"} {
+		if err := os.Chtimes(filepath.Join(dir, ids[name], "data"), past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := us.Sweep(time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 upload swept, got %d", n)
+	}
+
+	if _, _, err := us.Query(ids["old"]); !errors.Is(err, imgrepo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	for _, name := range []string{"claimed", "new"} {
+		if _, _, err := us.Query(ids[name]); err != nil {
+			t.Fatalf("upload %s: %v", name, err)
+		}
+	}
+}
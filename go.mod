@@ -4,13 +4,17 @@ go 1.16
 
 require (
 	github.com/aws/aws-sdk-go v1.38.35
-	github.com/go-redis/redis/v8 v8.8.2 // indirect
+	github.com/chai2010/webp v1.1.0
+	github.com/corona10/goimagehash v1.0.3
+	github.com/go-redis/redis/v8 v8.8.2
 	github.com/golang/protobuf v1.4.2
 	github.com/google/go-cmp v0.5.5
-	github.com/google/uuid v1.2.0 // indirect
+	github.com/google/uuid v1.2.0
 	github.com/joho/godotenv v1.3.0
+	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd
 	go.mongodb.org/mongo-driver v1.5.2
 	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
+	golang.org/x/image v0.0.0-20210220032944-ac19c3e999fb
 	golang.org/x/net v0.0.0-20210505214959-0714010a04ed // indirect
 	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
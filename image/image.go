@@ -1,83 +1,116 @@
+// Package image decodes images, and compares them by perceptual hashes.
 package image
 
-// import (
-// 	"fmt"
-// 	"image"
-// 	"image/jpeg"
-// 	"image/png"
-// 	"os"
-// 	"regexp"
-
-// 	"github.com/algao1/imgrepo"
-// 	"github.com/corona10/goimagehash"
-// )
-
-// type Image struct {
-// 	name    string
-// 	owner   string
-// 	access  imgrepo.Permission
-// 	data    *image.Image
-// 	rawHash uint64
-// 	kind    int
-// }
-
-// var _ imgrepo.Image = (*Image)(nil)
-
-// // Temporary global regex, remove later.
-// var _fmtReg *regexp.Regexp = regexp.MustCompile(`\..+$`)
-
-// func NewImage(name, owner string, access imgrepo.Permission) (*Image, error) {
-// 	file, err := os.Open(name)
-// 	if err != nil {
-// 		return nil, fmt.Errorf("%q: %w", "unable to open file", err)
-// 	}
-// 	defer file.Close()
-
-// 	format := _fmtReg.FindString(name)
-
-// 	var img image.Image
-
-// 	if format == ".jpg" {
-// 		img, err = jpeg.Decode(file)
-// 	} else if format == ".png" {
-// 		img, err = png.Decode(file)
-// 	} else {
-// 		return nil, fmt.Errorf("invalid image format: %s", format)
-// 	}
-
-// 	if err != nil {
-// 		return nil, fmt.Errorf("%q: %w", "unable to decode file", err)
-// 	}
-
-// 	hash, err := goimagehash.AverageHash(img)
-// 	if err != nil {
-// 		return nil, fmt.Errorf("%q: %w", "unable to hash image", err)
-// 	}
-
-// 	return &Image{
-// 		name:    name,
-// 		owner:   owner,
-// 		access:  access,
-// 		data:    &img,
-// 		rawHash: hash.GetHash(),
-// 		kind:    int(hash.GetKind()),
-// 	}, nil
-// }
-
-// func (img *Image) Name() string {
-// 	return img.name
-// }
-
-// func (img *Image) Hash() (uint64, int) {
-// 	return img.rawHash, img.kind
-// }
-
-// func (img *Image) Difference(target *imgrepo.Image) (int, error) {
-// 	rhash1, kind1 := img.Hash()
-// 	rhash2, kind2 := target.Hash()
-
-// 	hash1 := goimagehash.NewImageHash(rhash1, goimagehash.Kind(kind1))
-// 	hash2 := goimagehash.NewImageHash(rhash2, goimagehash.Kind(kind2))
-
-// 	return hash1.Distance(hash2)
-// }
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+
+	"github.com/algao1/imgrepo"
+	"github.com/corona10/goimagehash"
+	_ "golang.org/x/image/webp"
+)
+
+// Kinds of hashes computed by a Comparator, stored in imgrepo.Image.Kind.
+const (
+	AverageHash    = int(goimagehash.AHash)
+	PerceptualHash = int(goimagehash.PHash)
+	DifferenceHash = int(goimagehash.DHash)
+)
+
+// _KindNames maps the names of the kinds of hashes to their values.
+var _KindNames = map[string]int{
+	"average":    AverageHash,
+	"perceptual": PerceptualHash,
+	"difference": DifferenceHash,
+}
+
+// ParseKind returns the kind of hash with the given name, one of average,
+// perceptual or difference.
+func ParseKind(name string) (int, error) {
+	kind, ok := _KindNames[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown hash %q: %w", name, imgrepo.ErrInvalidArgument)
+	}
+	return kind, nil
+}
+
+// Decode decodes a JPEG, PNG, GIF or WebP image, and returns it along with
+// the name of its format.
+func Decode(r io.Reader) (image.Image, string, error) {
+	img, format, err := image.Decode(r)
+	if errors.Is(err, image.ErrFormat) {
+		return nil, "", fmt.Errorf("%q: %w", "unsupported image format", imgrepo.ErrInvalidArgument)
+	} else if errors.Is(err, imgrepo.ErrTooLarge) {
+		return nil, "", fmt.Errorf("%q: %w", "unable to read image", err)
+	} else if err != nil {
+		return nil, "", fmt.Errorf("unable to decode image: %v: %w", err, imgrepo.ErrInvalidArgument)
+	}
+
+	return img, format, nil
+}
+
+// Comparator implements imgrepo.ImageComparator, hashing images with a
+// single kind of hash.
+type Comparator struct {
+	kind int
+}
+
+var _ imgrepo.ImageComparator = (*Comparator)(nil)
+
+// NewComparator returns a Comparator computing hashes of the given kind.
+func NewComparator(kind int) (*Comparator, error) {
+	switch kind {
+	case AverageHash, PerceptualHash, DifferenceHash:
+		return &Comparator{kind: kind}, nil
+	default:
+		return nil, fmt.Errorf("unknown hash kind %d: %w", kind, imgrepo.ErrInvalidArgument)
+	}
+}
+
+func (c *Comparator) SetHash(img *imgrepo.Image, r io.Reader) error {
+	data, _, err := Decode(r)
+	if err != nil {
+		return err
+	}
+
+	var hash *goimagehash.ImageHash
+	switch c.kind {
+	case AverageHash:
+		hash, err = goimagehash.AverageHash(data)
+	case PerceptualHash:
+		hash, err = goimagehash.PerceptionHash(data)
+	case DifferenceHash:
+		hash, err = goimagehash.DifferenceHash(data)
+	}
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to hash image", err)
+	}
+
+	img.Hash = hash.GetHash()
+	img.Kind = int(hash.GetKind())
+
+	return nil
+}
+
+// Difference returns the Hamming distance between the hashes of the images,
+// which must have been hashed with the same kind of hash.
+func (c *Comparator) Difference(img1, img2 *imgrepo.Image) (int, error) {
+	if img1.Kind == 0 || img2.Kind == 0 {
+		return 0, fmt.Errorf("%q: %w", "image has not been hashed", imgrepo.ErrInvalidArgument)
+	}
+
+	hash1 := goimagehash.NewImageHash(img1.Hash, goimagehash.Kind(img1.Kind))
+	hash2 := goimagehash.NewImageHash(img2.Hash, goimagehash.Kind(img2.Kind))
+
+	dist, err := hash1.Distance(hash2)
+	if err != nil {
+		return 0, fmt.Errorf("%v: %w", err, imgrepo.ErrInvalidArgument)
+	}
+
+	return dist, nil
+}
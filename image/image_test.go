@@ -1,54 +1,172 @@
 package image
 
-// import (
-// 	"os"
-// 	"strings"
-// 	"testing"
-
-// 	"github.com/algao1/imgrepo"
-// 	"github.com/google/go-cmp/cmp"
-// )
-
-// func TestNewImageInvalidFiles(t *testing.T) {
-// 	tests := map[string]struct {
-// 		name   string
-// 		owner  string
-// 		access imgrepo.Permission
-// 		reason string
-// 	}{
-// 		"fileNotFound": {
-// 			name:   "fileNotFound.png",
-// 			owner:  "test",
-// 			access: imgrepo.Public,
-// 			reason: "unable to open file",
-// 		},
-// 		"invalidFileFormat": {
-// 			name:   "invalidFileFormat.bmp",
-// 			owner:  "test",
-// 			access: imgrepo.Public,
-// 			reason: "invalid image format: .bmp",
-// 		},
-// 		"invalidDecode": {
-// 			name:   "emptyImage.jpg",
-// 			owner:  "test",
-// 			access: imgrepo.Public,
-// 			reason: "unable to decode file",
-// 		},
-// 	}
-
-// 	os.Create("invalidFileFormat.bmp")
-// 	os.Create("emptyImage.jpg")
-// 	defer func() {
-// 		os.Remove("invalidFileFormat.bmp")
-// 		os.Remove("emptyImage.jpg")
-// 	}()
-
-// 	for name, tc := range tests {
-// 		t.Run(name, func(t *testing.T) {
-// 			_, err := NewImage(tc.name, tc.owner, tc.access)
-// 			if err != nil && !strings.Contains(err.Error(), tc.reason) {
-// 				t.Fatalf(cmp.Diff(err.Error(), tc.reason))
-// 			}
-// 		})
-// 	}
-// }
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+)
+
+// gradient returns a small image with a diagonal gradient.
+func gradient() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(4 * x), G: uint8(4 * y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+// encode encodes img with the given encoder.
+func encode(t *testing.T, img image.Image, enc func(*bytes.Buffer, image.Image) error) []byte {
+	var buf bytes.Buffer
+	if err := enc(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func readFile(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDecode(t *testing.T) {
+	tests := map[string]struct {
+		data    []byte
+		format  string
+		wantErr error
+	}{
+		"jpeg": {
+			data:   readFile(t, "../_data/apple1.jpg"),
+			format: "jpeg",
+		},
+		"png": {
+			data: encode(t, gradient(), func(b *bytes.Buffer, img image.Image) error {
+				return png.Encode(b, img)
+			}),
+			format: "png",
+		},
+		"gif": {
+			data: encode(t, gradient(), func(b *bytes.Buffer, img image.Image) error {
+				return gif.Encode(b, img, nil)
+			}),
+			format: "gif",
+		},
+		"webp": {
+			data:   readFile(t, "testdata/blue-purple-pink.lossy.webp"),
+			format: "webp",
+		},
+		"svg": {
+			data:    readFile(t, "../_data/orange.svg"),
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+		"truncated": {
+			data:    readFile(t, "../_data/apple1.jpg")[:100],
+			wantErr: imgrepo.ErrInvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, format, err := Decode(bytes.NewReader(tc.data))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Decode() = _, _, %v, want %v", err, tc.wantErr)
+			}
+			if format != tc.format {
+				t.Fatalf("Decode() format = %q, want %q", format, tc.format)
+			}
+		})
+	}
+}
+
+func TestDifference(t *testing.T) {
+	apple := readFile(t, "../_data/apple1.jpg")
+	decoded, _, err := Decode(bytes.NewReader(apple))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same picture, with different bytes.
+	reencoded := encode(t, decoded, func(b *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(b, img, &jpeg.Options{Quality: 30})
+	})
+
+	tests := map[string]struct {
+		kind    int
+		other   []byte
+		maxDist int
+		minDist int
+	}{
+		"average same picture":    {kind: AverageHash, other: reencoded, maxDist: 4},
+		"perceptual same picture": {kind: PerceptualHash, other: reencoded, maxDist: 4},
+		"difference same picture": {kind: DifferenceHash, other: reencoded, maxDist: 4},
+		"perceptual other picture": {
+			kind:    PerceptualHash,
+			other:   readFile(t, "../_data/banana1.jpg"),
+			maxDist: 64,
+			minDist: 10,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewComparator(tc.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var img1, img2 imgrepo.Image
+			if err := c.SetHash(&img1, bytes.NewReader(apple)); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.SetHash(&img2, bytes.NewReader(tc.other)); err != nil {
+				t.Fatal(err)
+			}
+			if img1.Kind != tc.kind || img2.Kind != tc.kind {
+				t.Fatalf("got kinds %d and %d, want %d", img1.Kind, img2.Kind, tc.kind)
+			}
+
+			dist, err := c.Difference(&img1, &img2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dist < tc.minDist || dist > tc.maxDist {
+				t.Fatalf("Difference() = %d, want within [%d, %d]", dist, tc.minDist, tc.maxDist)
+			}
+		})
+	}
+}
+
+func TestDifferenceInvalid(t *testing.T) {
+	c, err := NewComparator(PerceptualHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		img1, img2 imgrepo.Image
+	}{
+		"unhashed":       {img1: imgrepo.Image{Hash: 1, Kind: PerceptualHash}, img2: imgrepo.Image{}},
+		"different kind": {img1: imgrepo.Image{Hash: 1, Kind: PerceptualHash}, img2: imgrepo.Image{Hash: 1, Kind: AverageHash}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := c.Difference(&tc.img1, &tc.img2); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+				t.Fatalf("Difference() = _, %v, want %v", err, imgrepo.ErrInvalidArgument)
+			}
+		})
+	}
+}
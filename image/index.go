@@ -0,0 +1,161 @@
+package image
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"sync"
+
+	"github.com/algao1/imgrepo"
+)
+
+// node is a node of a BK-tree, holding the ids of the images with its hash.
+// Every child is at the distance it is keyed by from the node.
+type node struct {
+	hash     uint64
+	ids      map[string]struct{}
+	children map[int]*node
+}
+
+// Index implements imgrepo.ImageIndex with a BK-tree over the hashes of a
+// single kind. Since the Hamming distance is a metric, a search only visits
+// the subtrees that can hold hashes within the distance searched for.
+//
+// Removed images leave their node in the tree, to route later searches.
+type Index struct {
+	kind int
+
+	mu     sync.RWMutex
+	root   *node
+	hashes map[string]uint64
+}
+
+var _ imgrepo.ImageIndex = (*Index)(nil)
+
+// NewIndex returns an empty Index of hashes of the given kind.
+func NewIndex(kind int) *Index {
+	return &Index{kind: kind, hashes: make(map[string]uint64)}
+}
+
+// distance returns the Hamming distance between two hashes.
+func distance(h1, h2 uint64) int {
+	return bits.OnesCount64(h1 ^ h2)
+}
+
+// Add indexes the image, which must have been hashed with the kind of the
+// index. Adding an image twice moves it to its current hash.
+func (idx *Index) Add(img *imgrepo.Image) error {
+	if img.Kind != idx.kind {
+		return fmt.Errorf("image %s has hash kind %d, want %d: %w", img.Id, img.Kind, idx.kind, imgrepo.ErrInvalidArgument)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.hashes[img.Id]; ok {
+		idx.remove(img.Id)
+	}
+	idx.hashes[img.Id] = img.Hash
+
+	if idx.root == nil {
+		idx.root = &node{hash: img.Hash, ids: make(map[string]struct{}), children: make(map[int]*node)}
+	}
+
+	n := idx.root
+	for {
+		d := distance(n.hash, img.Hash)
+		if d == 0 {
+			n.ids[img.Id] = struct{}{}
+			return nil
+		}
+
+		child, ok := n.children[d]
+		if !ok {
+			n.children[d] = &node{
+				hash:     img.Hash,
+				ids:      map[string]struct{}{img.Id: {}},
+				children: make(map[int]*node),
+			}
+			return nil
+		}
+		n = child
+	}
+}
+
+func (idx *Index) Remove(id string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.hashes[id]; !ok {
+		return fmt.Errorf("image %s: %w", id, imgrepo.ErrNotFound)
+	}
+	idx.remove(id)
+
+	return nil
+}
+
+// remove removes an indexed image, idx.mu must be held.
+func (idx *Index) remove(id string) {
+	hash := idx.hashes[id]
+	delete(idx.hashes, id)
+
+	n := idx.root
+	for n != nil {
+		d := distance(n.hash, hash)
+		if d == 0 {
+			delete(n.ids, id)
+			return
+		}
+		n = n.children[d]
+	}
+}
+
+func (idx *Index) Search(img *imgrepo.Image, maxDist int) ([]imgrepo.Match, error) {
+	if img.Kind != idx.kind {
+		return nil, fmt.Errorf("image has hash kind %d, want %d: %w", img.Kind, idx.kind, imgrepo.ErrInvalidArgument)
+	}
+	if maxDist < 0 {
+		return nil, fmt.Errorf("negative distance %d: %w", maxDist, imgrepo.ErrInvalidArgument)
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var matches []imgrepo.Match
+	stack := []*node{}
+	if idx.root != nil {
+		stack = append(stack, idx.root)
+	}
+
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := distance(n.hash, img.Hash)
+		if d <= maxDist {
+			for id := range n.ids {
+				matches = append(matches, imgrepo.Match{
+					Image:    &imgrepo.Image{Id: id, Hash: n.hash, Kind: idx.kind},
+					Distance: d,
+				})
+			}
+		}
+
+		// By the triangle inequality, only children keyed within maxDist
+		// of d can hold matches.
+		for cd, child := range n.children {
+			if cd >= d-maxDist && cd <= d+maxDist {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].Image.Id < matches[j].Image.Id
+	})
+
+	return matches, nil
+}
@@ -0,0 +1,148 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/google/go-cmp/cmp"
+)
+
+// bruteForce returns the ids of the images within maxDist of hash, in the
+// order Index.Search returns them.
+func bruteForce(imgs []*imgrepo.Image, hash uint64, maxDist int) []string {
+	var ids []string
+	for d := 0; d <= maxDist; d++ {
+		var found []string
+		for _, img := range imgs {
+			if bits.OnesCount64(img.Hash^hash) == d {
+				found = append(found, img.Id)
+			}
+		}
+		sort.Strings(found)
+		ids = append(ids, found...)
+	}
+	return ids
+}
+
+func matchIds(matches []imgrepo.Match) []string {
+	var ids []string
+	for _, m := range matches {
+		ids = append(ids, m.Image.Id)
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// Clusters of hashes a few bits apart, like copies of the same picture.
+	var imgs []*imgrepo.Image
+	for c := 0; c < 50; c++ {
+		base := rng.Uint64()
+		for i := 0; i < 10; i++ {
+			hash := base
+			for b := 0; b < rng.Intn(6); b++ {
+				hash ^= 1 << uint(rng.Intn(64))
+			}
+			imgs = append(imgs, &imgrepo.Image{Id: fmt.Sprintf("%03d-%d", c, i), Hash: hash, Kind: PerceptualHash})
+		}
+	}
+
+	idx := NewIndex(PerceptualHash)
+	for _, img := range imgs {
+		if err := idx.Add(img); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		query   uint64
+		maxDist int
+	}{
+		"exact":        {query: imgs[0].Hash, maxDist: 0},
+		"near":         {query: imgs[42].Hash, maxDist: 5},
+		"far":          {query: imgs[123].Hash, maxDist: 20},
+		"unrelated":    {query: rng.Uint64(), maxDist: 5},
+		"everything":   {query: imgs[7].Hash, maxDist: 64},
+		"flipped bits": {query: imgs[300].Hash ^ 0xF, maxDist: 6},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := idx.Search(&imgrepo.Image{Hash: tc.query, Kind: PerceptualHash}, tc.maxDist)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := bruteForce(imgs, tc.query, tc.maxDist)
+			if diff := cmp.Diff(want, matchIds(got)); diff != "" {
+				t.Fatalf("Search() mismatch (-want +got):\n%s", diff)
+			}
+			for _, m := range got {
+				if d := bits.OnesCount64(m.Image.Hash ^ tc.query); d != m.Distance {
+					t.Fatalf("match %s has distance %d, want %d", m.Image.Id, m.Distance, d)
+				}
+			}
+		})
+	}
+}
+
+func TestIndexRemove(t *testing.T) {
+	idx := NewIndex(AverageHash)
+
+	imgs := []*imgrepo.Image{
+		{Id: "a", Hash: 0x0, Kind: AverageHash},
+		{Id: "b", Hash: 0x0, Kind: AverageHash},
+		{Id: "c", Hash: 0x1, Kind: AverageHash},
+		{Id: "d", Hash: 0x3, Kind: AverageHash},
+	}
+	for _, img := range imgs {
+		if err := idx.Add(img); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Removing the root must not hide its children.
+	if err := idx.Remove("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Remove("a"); !errors.Is(err, imgrepo.ErrNotFound) {
+		t.Fatalf("Remove() = %v, want %v", err, imgrepo.ErrNotFound)
+	}
+
+	got, err := idx.Search(&imgrepo.Image{Hash: 0x0, Kind: AverageHash}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"b", "c", "d"}, matchIds(got)); diff != "" {
+		t.Fatalf("Search() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Adding an image again moves it to its new hash.
+	if err := idx.Add(&imgrepo.Image{Id: "b", Hash: 0xFF, Kind: AverageHash}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = idx.Search(&imgrepo.Image{Hash: 0x0, Kind: AverageHash}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"c", "d"}, matchIds(got)); diff != "" {
+		t.Fatalf("Search() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIndexKind(t *testing.T) {
+	idx := NewIndex(PerceptualHash)
+
+	if err := idx.Add(&imgrepo.Image{Id: "a", Kind: AverageHash}); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+		t.Fatalf("Add() = %v, want %v", err, imgrepo.ErrInvalidArgument)
+	}
+	if _, err := idx.Search(&imgrepo.Image{}, 5); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+		t.Fatalf("Search() = _, %v, want %v", err, imgrepo.ErrInvalidArgument)
+	}
+}
@@ -0,0 +1,120 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// _MaxHeader is how much of an image is read for its metadata, which is
+// stored in its headers.
+const _MaxHeader = 1 << 20
+
+// _ExifTimeLayout is the layout of EXIF times.
+const _ExifTimeLayout = "2006:01:02 15:04:05"
+
+// Inspector implements imgrepo.ImageInspector.
+type Inspector struct{}
+
+var _ imgrepo.ImageInspector = (*Inspector)(nil)
+
+// NewInspector returns an Inspector.
+func NewInspector() *Inspector {
+	return &Inspector{}
+}
+
+// Inspect sniffs the MIME type of the image, decodes its dimensions, and
+// parses its EXIF fields if it has any. Missing or malformed EXIF fields
+// are left out rather than failing the upload.
+func (in *Inspector) Inspect(img *imgrepo.Image, r io.Reader) error {
+	head, err := io.ReadAll(io.LimitReader(r, _MaxHeader))
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to read image", err)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(head))
+	if err != nil {
+		return fmt.Errorf("unable to decode image: %v: %w", err, imgrepo.ErrInvalidArgument)
+	}
+
+	md := imgrepo.Metadata{
+		MimeType: http.DetectContentType(head),
+		Width:    cfg.Width,
+		Height:   cfg.Height,
+	}
+
+	if x, err := exif.Decode(bytes.NewReader(head)); err == nil {
+		setExif(&md, x)
+	}
+	img.Metadata = md
+
+	return nil
+}
+
+// setExif copies the EXIF fields found in x to md.
+func setExif(md *imgrepo.Metadata, x *exif.Exif) {
+	md.Taken = exifTime(x)
+	md.Make = exifString(x, exif.Make)
+	md.Model = exifString(x, exif.Model)
+	md.Orientation = exifOrientation(x)
+
+	if lat, long, err := x.LatLong(); err == nil {
+		md.Location = &imgrepo.Location{Latitude: lat, Longitude: long}
+	}
+}
+
+// exifOrientation returns the orientation of the image, from 1 to 8, or 0 if
+// it has none.
+func exifOrientation(x *exif.Exif) int {
+	tag, err := x.Get(exif.Orientation)
+	if err != nil {
+		return 0
+	}
+
+	o, err := tag.Int(0)
+	if err != nil || o < 1 || o > 8 {
+		return 0
+	}
+	return o
+}
+
+func exifString(x *exif.Exif, name exif.FieldName) string {
+	tag, err := x.Get(name)
+	if err != nil {
+		return ""
+	}
+
+	s, err := tag.StringVal()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(s, "\x00"))
+}
+
+// exifTime returns the capture time of the image. EXIF times rarely carry
+// a time zone, in which case they are taken as UTC, so that they do not
+// depend on the time zone of the server.
+func exifTime(x *exif.Exif) time.Time {
+	s := exifString(x, exif.DateTimeOriginal)
+	if s == "" {
+		s = exifString(x, exif.DateTime)
+	}
+
+	loc, _ := x.TimeZone()
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	t, err := time.ParseInLocation(_ExifTimeLayout, s, loc)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
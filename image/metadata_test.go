@@ -0,0 +1,188 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// field is an entry of a TIFF image file directory.
+type field struct {
+	tag, typ uint16
+	count    uint32
+	val      []byte
+}
+
+const (
+	_ASCII    = 2
+	_Short    = 3
+	_Long     = 4
+	_Rational = 5
+)
+
+func ascii(tag uint16, s string) field {
+	return field{tag: tag, typ: _ASCII, count: uint32(len(s) + 1), val: append([]byte(s), 0)}
+}
+
+func short(tag uint16, v uint16) field {
+	val := make([]byte, 2)
+	binary.LittleEndian.PutUint16(val, v)
+	return field{tag: tag, typ: _Short, count: 1, val: val}
+}
+
+func long(tag uint16, v uint32) field {
+	val := make([]byte, 4)
+	binary.LittleEndian.PutUint32(val, v)
+	return field{tag: tag, typ: _Long, count: 1, val: val}
+}
+
+// degrees encodes degrees, minutes and seconds as rationals.
+func degrees(tag uint16, d, m, s uint32) field {
+	val := make([]byte, 24)
+	for i, v := range []uint32{d, 1, m, 1, s, 1} {
+		binary.LittleEndian.PutUint32(val[4*i:], v)
+	}
+	return field{tag: tag, typ: _Rational, count: 3, val: val}
+}
+
+// ifd encodes an image file directory starting at off, followed by the
+// values that do not fit in its entries.
+func ifd(off uint32, fields []field) []byte {
+	le := binary.LittleEndian
+	size := uint32(2 + 12*len(fields) + 4)
+
+	var head, data bytes.Buffer
+	binary.Write(&head, le, uint16(len(fields)))
+	for _, f := range fields {
+		binary.Write(&head, le, f.tag)
+		binary.Write(&head, le, f.typ)
+		binary.Write(&head, le, f.count)
+		if len(f.val) <= 4 {
+			head.Write(append(f.val, make([]byte, 4-len(f.val))...))
+			continue
+		}
+		binary.Write(&head, le, off+size+uint32(data.Len()))
+		data.Write(f.val)
+		if data.Len()%2 == 1 {
+			data.WriteByte(0)
+		}
+	}
+	binary.Write(&head, le, uint32(0))
+
+	return append(head.Bytes(), data.Bytes()...)
+}
+
+// exifTIFF returns the TIFF structure of an EXIF block with a camera,
+// orientation, capture time and location.
+func exifTIFF() []byte {
+	ifd0 := func(exifOff, gpsOff uint32) []field {
+		return []field{
+			ascii(0x010f, "Imgrepo"),
+			ascii(0x0110, "Camera 1"),
+			short(0x0112, 6),
+			long(0x8769, exifOff),
+			long(0x8825, gpsOff),
+		}
+	}
+	exifIFD := []field{ascii(0x9003, "2021:05:17 10:30:00")}
+	gpsIFD := []field{
+		ascii(0x0001, "N"),
+		degrees(0x0002, 48, 51, 30),
+		ascii(0x0003, "W"),
+		degrees(0x0004, 122, 25, 12),
+	}
+
+	// IFD0 has the same size whatever the pointers, so it is encoded twice.
+	exifOff := 8 + uint32(len(ifd(8, ifd0(0, 0))))
+	exifData := ifd(exifOff, exifIFD)
+	gpsOff := exifOff + uint32(len(exifData))
+
+	tiff := []byte("II\x2a\x00\x08\x00\x00\x00")
+	tiff = append(tiff, ifd(8, ifd0(exifOff, gpsOff))...)
+	tiff = append(tiff, exifData...)
+	tiff = append(tiff, ifd(gpsOff, gpsIFD)...)
+
+	return tiff
+}
+
+// withExif returns a JPEG of img with an EXIF segment.
+func withExif(t *testing.T, img image.Image) []byte {
+	data := encode(t, img, func(b *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(b, img, nil)
+	})
+
+	// The APP1 segment goes right after the start of image marker.
+	app1 := append([]byte("Exif\x00\x00"), exifTIFF()...)
+	var seg bytes.Buffer
+	seg.Write([]byte{0xff, 0xe1})
+	binary.Write(&seg, binary.BigEndian, uint16(len(app1)+2))
+	seg.Write(app1)
+
+	return append(append(append([]byte{}, data[:2]...), seg.Bytes()...), data[2:]...)
+}
+
+func TestInspect(t *testing.T) {
+	tests := map[string]struct {
+		data []byte
+		want imgrepo.Metadata
+	}{
+		"png": {
+			data: encode(t, wide(200), func(b *bytes.Buffer, img image.Image) error {
+				return png.Encode(b, img)
+			}),
+			want: imgrepo.Metadata{MimeType: "image/png", Width: 200, Height: 100},
+		},
+		"jpeg without exif": {
+			data: readFile(t, "../_data/apple1.jpg"),
+			want: imgrepo.Metadata{MimeType: "image/jpeg", Width: 6000, Height: 4000},
+		},
+		"webp": {
+			data: readFile(t, "testdata/blue-purple-pink.lossy.webp"),
+			want: imgrepo.Metadata{MimeType: "image/webp", Width: 150, Height: 100},
+		},
+		"jpeg with exif": {
+			data: withExif(t, gradient()),
+			want: imgrepo.Metadata{
+				MimeType:    "image/jpeg",
+				Width:       64,
+				Height:      64,
+				Taken:       time.Date(2021, 5, 17, 10, 30, 0, 0, time.UTC),
+				Make:        "Imgrepo",
+				Model:       "Camera 1",
+				Orientation: 6,
+				Location:    &imgrepo.Location{Latitude: 48.858333, Longitude: -122.42},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var img imgrepo.Image
+			if err := NewInspector().Inspect(&img, bytes.NewReader(tc.data)); err != nil {
+				t.Fatal(err)
+			}
+
+			opt := cmpopts.EquateApprox(0, 1e-5)
+			if diff := cmp.Diff(tc.want, img.Metadata, opt); diff != "" {
+				t.Errorf("Inspect() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInspectInvalid(t *testing.T) {
+	var img imgrepo.Image
+	err := NewInspector().Inspect(&img, bytes.NewReader(readFile(t, "../_data/orange.svg")))
+	if !errors.Is(err, imgrepo.ErrInvalidArgument) {
+		t.Errorf("got %v, want %v", err, imgrepo.ErrInvalidArgument)
+	}
+}
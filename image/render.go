@@ -0,0 +1,111 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/algao1/imgrepo"
+)
+
+// _Quality is the JPEG quality of renditions.
+const _Quality = 85
+
+// Renderer implements imgrepo.ImageRenderer, resizing images to fit in
+// squares of the given sizes while preserving their aspect ratio.
+type Renderer struct {
+	sizes []int
+}
+
+var _ imgrepo.ImageRenderer = (*Renderer)(nil)
+
+// NewRenderer returns a Renderer generating renditions of the given sizes,
+// in pixels. Without sizes, no renditions are generated.
+func NewRenderer(sizes ...int) (*Renderer, error) {
+	var uniq []int
+	for _, size := range sizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("rendition size %d: %w", size, imgrepo.ErrInvalidArgument)
+		}
+		if !containsSize(uniq, size) {
+			uniq = append(uniq, size)
+		}
+	}
+	sort.Ints(uniq)
+
+	return &Renderer{sizes: uniq}, nil
+}
+
+// ParseSizes parses a comma separated list of sizes, such as "128,512".
+// The empty string is no sizes.
+func ParseSizes(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var sizes []int
+	for _, field := range strings.Split(s, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("rendition size %q: %w", field, imgrepo.ErrInvalidArgument)
+		}
+		sizes = append(sizes, size)
+	}
+
+	return sizes, nil
+}
+
+func containsSize(sizes []int, size int) bool {
+	for _, s := range sizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+// Render returns a JPEG rendition of every size, smallest first. Images are
+// never upscaled, so a rendition may be smaller than its size.
+func (rd *Renderer) Render(r io.Reader) ([]imgrepo.Rendition, error) {
+	if len(rd.sizes) == 0 {
+		return nil, nil
+	}
+
+	src, _, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rends []imgrepo.Rendition
+	for _, size := range rd.sizes {
+		var buf bytes.Buffer
+		err := jpeg.Encode(&buf, resize(src, size), &jpeg.Options{Quality: _Quality})
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to encode rendition", err)
+		}
+		rends = append(rends, imgrepo.Rendition{Size: size, Data: buf.Bytes()})
+	}
+
+	return rends, nil
+}
+
+// resize scales src so that its longest side is at most size, over a white
+// background since JPEG has no transparency.
+func resize(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, h*size/w
+		} else {
+			w, h = w*size/h, size
+		}
+	}
+
+	return flatten(scale(src, b, atLeastOne(w), atLeastOne(h)))
+}
@@ -0,0 +1,123 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+)
+
+// wide returns an image twice as wide as it is high.
+func wide(w int) image.Image {
+	return image.NewRGBA(image.Rect(0, 0, w, w/2))
+}
+
+func TestRender(t *testing.T) {
+	tests := map[string]struct {
+		img   image.Image
+		sizes []int
+		want  []image.Point
+	}{
+		"downscaled": {
+			img:   wide(1024),
+			sizes: []int{512, 128},
+			want:  []image.Point{{128, 64}, {512, 256}},
+		},
+		"not upscaled": {
+			img:   gradient(),
+			sizes: []int{128},
+			want:  []image.Point{{64, 64}},
+		},
+		"tall": {
+			img:   image.NewRGBA(image.Rect(0, 0, 100, 400)),
+			sizes: []int{200},
+			want:  []image.Point{{50, 200}},
+		},
+		"duplicate sizes": {
+			img:   wide(256),
+			sizes: []int{32, 32},
+			want:  []image.Point{{32, 16}},
+		},
+		"no sizes": {
+			img: wide(256),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rd, err := NewRenderer(tc.sizes...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data := encode(t, tc.img, func(b *bytes.Buffer, img image.Image) error {
+				return png.Encode(b, img)
+			})
+			rends, err := rd.Render(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(rends) != len(tc.want) {
+				t.Fatalf("got %d renditions, want %d", len(rends), len(tc.want))
+			}
+			for i, rend := range rends {
+				cfg, err := jpeg.DecodeConfig(bytes.NewReader(rend.Data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := (image.Point{cfg.Width, cfg.Height}); got != tc.want[i] {
+					t.Errorf("rendition %d is %v, want %v", rend.Size, got, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRenderInvalid(t *testing.T) {
+	if _, err := NewRenderer(128, 0); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+		t.Errorf("got %v, want %v", err, imgrepo.ErrInvalidArgument)
+	}
+
+	rd, err := NewRenderer(128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rd.Render(bytes.NewReader([]byte("not an image"))); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+		t.Errorf("got %v, want %v", err, imgrepo.ErrInvalidArgument)
+	}
+}
+
+func TestParseSizes(t *testing.T) {
+	tests := map[string]struct {
+		s       string
+		want    []int
+		wantErr error
+	}{
+		"empty":   {s: ""},
+		"one":     {s: "128", want: []int{128}},
+		"several": {s: "128, 512", want: []int{128, 512}},
+		"invalid": {s: "128,big", wantErr: imgrepo.ErrInvalidArgument},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseSizes(tc.s)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got %v, want %v", err, tc.wantErr)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package image
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/algao1/imgrepo"
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+var (
+	_JPEGMagic  = []byte{0xff, 0xd8}
+	_PNGMagic   = []byte("\x89PNG\r\n\x1a\n")
+	_ExifHeader = []byte("Exif\x00\x00")
+)
+
+// JPEG markers.
+const (
+	_EOI   = 0xd9
+	_SOS   = 0xda
+	_APP0  = 0xe0
+	_APP1  = 0xe1
+	_APP13 = 0xed
+)
+
+// _StrippedChunks are the PNG chunks holding metadata.
+var _StrippedChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// Stripper implements imgrepo.ImageStripper for JPEG and PNG images. The
+// image data is copied as is, so stripping is lossless.
+type Stripper struct{}
+
+var _ imgrepo.ImageStripper = (*Stripper)(nil)
+
+// NewStripper returns a Stripper.
+func NewStripper() *Stripper {
+	return &Stripper{}
+}
+
+// Strip drops the APP1 and APP13 segments of JPEG images, which hold their
+// EXIF, XMP and IPTC blocks, and the eXIf, text and time chunks of PNG
+// images. An orientation other than the default is written back in an EXIF
+// block of its own, so that the image is still displayed upright.
+func (st *Stripper) Strip(w io.Writer, r io.Reader) error {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(_PNGMagic))
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("%q: %w", "unable to read image", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(head, _JPEGMagic):
+		return stripJPEG(w, br)
+	case bytes.HasPrefix(head, _PNGMagic):
+		return stripPNG(w, br)
+	default:
+		_, err = io.Copy(w, br)
+		return err
+	}
+}
+
+// stripJPEG strips the segments before the first scan of a JPEG image, and
+// copies the rest. The segments are buffered, since the orientation is only
+// known once the EXIF segment is found.
+func stripJPEG(w io.Writer, r *bufio.Reader) error {
+	if _, err := r.Discard(len(_JPEGMagic)); err != nil {
+		return fmt.Errorf("%q: %w", "unable to read image", err)
+	}
+
+	var segs [][]byte
+	var orientation int
+	var marker byte
+	for {
+		var err error
+		marker, err = readMarker(r)
+		if err != nil {
+			return err
+		}
+		if marker == _SOS || marker == _EOI {
+			break
+		}
+
+		// Markers without a length have no data.
+		if marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7) {
+			segs = append(segs, []byte{0xff, marker})
+			continue
+		}
+
+		var n [2]byte
+		if _, err := io.ReadFull(r, n[:]); err != nil {
+			return fmt.Errorf("unable to read JPEG segment: %v: %w", err, imgrepo.ErrInvalidArgument)
+		}
+		size := int(binary.BigEndian.Uint16(n[:]))
+		if size < 2 {
+			return fmt.Errorf("JPEG segment of length %d: %w", size, imgrepo.ErrInvalidArgument)
+		}
+		data := make([]byte, size-2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fmt.Errorf("unable to read JPEG segment: %v: %w", err, imgrepo.ErrInvalidArgument)
+		}
+
+		switch marker {
+		case _APP1:
+			if bytes.HasPrefix(data, _ExifHeader) {
+				orientation = orientationOf(data)
+			}
+		case _APP13:
+			// IPTC blocks are dropped whole.
+		default:
+			segs = append(segs, append([]byte{0xff, marker, n[0], n[1]}, data...))
+		}
+	}
+
+	var out bytes.Buffer
+	out.Write(_JPEGMagic)
+
+	// The JFIF segment must stay first.
+	if len(segs) > 0 && segs[0][1] == _APP0 {
+		out.Write(segs[0])
+		segs = segs[1:]
+	}
+	if orientation > 1 {
+		data := append(append([]byte{}, _ExifHeader...), orientationTIFF(orientation)...)
+		out.Write([]byte{0xff, _APP1})
+		binary.Write(&out, binary.BigEndian, uint16(len(data)+2))
+		out.Write(data)
+	}
+	for _, seg := range segs {
+		out.Write(seg)
+	}
+	out.Write([]byte{0xff, marker})
+
+	if _, err := w.Write(out.Bytes()); err != nil {
+		return err
+	}
+	_, err := io.Copy(w, r)
+	return err
+}
+
+// readMarker reads the next JPEG marker, skipping fill bytes.
+func readMarker(r *bufio.Reader) (byte, error) {
+	b, err := r.ReadByte()
+	if err == nil && b != 0xff {
+		return 0, fmt.Errorf("byte %#x instead of JPEG marker: %w", b, imgrepo.ErrInvalidArgument)
+	}
+	for err == nil && b == 0xff {
+		b, err = r.ReadByte()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to read JPEG marker: %v: %w", err, imgrepo.ErrInvalidArgument)
+	}
+	return b, nil
+}
+
+// stripPNG drops the metadata chunks of a PNG image, and copies the others.
+// The orientation is written back before the image data, where the eXIf
+// chunk must be.
+func stripPNG(w io.Writer, r *bufio.Reader) error {
+	if _, err := io.CopyN(w, r, int64(len(_PNGMagic))); err != nil {
+		return err
+	}
+
+	var orientation int
+	for {
+		var head [8]byte
+		_, err := io.ReadFull(r, head[:])
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("unable to read PNG chunk: %v: %w", err, imgrepo.ErrInvalidArgument)
+		}
+		size := int64(binary.BigEndian.Uint32(head[:4]))
+		typ := string(head[4:])
+
+		if _StrippedChunks[typ] {
+			data, err := io.ReadAll(io.LimitReader(r, size))
+			if err == nil && int64(len(data)) < size {
+				err = io.ErrUnexpectedEOF
+			}
+			if err == nil {
+				_, err = r.Discard(4)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to read PNG chunk %s: %v: %w", typ, err, imgrepo.ErrInvalidArgument)
+			}
+
+			if typ == "eXIf" {
+				orientation = orientationOf(data)
+			}
+			continue
+		}
+
+		if typ == "IDAT" && orientation > 1 {
+			if err := writeChunk(w, "eXIf", orientationTIFF(orientation)); err != nil {
+				return err
+			}
+			orientation = 0
+		}
+
+		// The chunk is copied along with its CRC.
+		if _, err := w.Write(head[:]); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(w, r, size+4); err != nil {
+			return fmt.Errorf("unable to copy PNG chunk %s: %v: %w", typ, err, imgrepo.ErrInvalidArgument)
+		}
+	}
+}
+
+// writeChunk writes a PNG chunk.
+func writeChunk(w io.Writer, typ string, data []byte) error {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	buf.WriteString(typ)
+	buf.Write(data)
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(buf.Bytes()[4:]))
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// orientationOf returns the orientation in an EXIF block, or 0 if it has
+// none.
+func orientationOf(data []byte) int {
+	x, err := exif.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0
+	}
+	return exifOrientation(x)
+}
+
+// orientationTIFF returns the TIFF structure of an EXIF block holding only
+// the orientation o.
+func orientationTIFF(o int) []byte {
+	b := make([]byte, 26)
+	copy(b, "MM\x00\x2a")
+	binary.BigEndian.PutUint32(b[4:], 8)       // offset of IFD0
+	binary.BigEndian.PutUint16(b[8:], 1)       // number of entries
+	binary.BigEndian.PutUint16(b[10:], 0x0112) // orientation tag
+	binary.BigEndian.PutUint16(b[12:], 3)      // short
+	binary.BigEndian.PutUint32(b[14:], 1)      // count
+	binary.BigEndian.PutUint16(b[18:], uint16(o))
+	return b
+}
@@ -0,0 +1,132 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/google/go-cmp/cmp"
+)
+
+// withChunks returns the PNG data with the given chunks inserted after its
+// header chunk.
+func withChunks(t *testing.T, data []byte, chunks map[string][]byte) []byte {
+	// The signature is followed by the 25 bytes of the header chunk.
+	const end = 8 + 25
+
+	var buf bytes.Buffer
+	buf.Write(data[:end])
+	for typ, data := range chunks {
+		if err := writeChunk(&buf, typ, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf.Write(data[end:])
+
+	return buf.Bytes()
+}
+
+func TestStrip(t *testing.T) {
+	plainJPEG := encode(t, gradient(), func(b *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(b, img, nil)
+	})
+	plainPNG := encode(t, wide(200), func(b *bytes.Buffer, img image.Image) error {
+		return png.Encode(b, img)
+	})
+	webp := readFile(t, "testdata/blue-purple-pink.lossy.webp")
+
+	tests := map[string]struct {
+		data []byte
+		want []byte
+	}{
+		"jpeg without metadata": {
+			data: plainJPEG,
+			want: plainJPEG,
+		},
+		"png without metadata": {
+			data: plainPNG,
+			want: plainPNG,
+		},
+		"png with metadata": {
+			data: withChunks(t, plainPNG, map[string][]byte{
+				"eXIf": exifTIFF(),
+				"tEXt": []byte("Comment\x00Home"),
+			}),
+			want: withChunks(t, plainPNG, map[string][]byte{"eXIf": orientationTIFF(6)}),
+		},
+		"webp": {
+			data: webp,
+			want: webp,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := NewStripper().Strip(&out, bytes.NewReader(tc.data)); err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(out.Bytes(), tc.want) {
+				t.Errorf("Strip() = %d bytes, want %d bytes", out.Len(), len(tc.want))
+			}
+			if _, _, err := Decode(bytes.NewReader(out.Bytes())); err != nil {
+				t.Errorf("Decode() = %v", err)
+			}
+		})
+	}
+}
+
+func TestStripJPEG(t *testing.T) {
+	data := withExif(t, gradient())
+
+	var out bytes.Buffer
+	if err := NewStripper().Strip(&out, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the orientation is left.
+	var img imgrepo.Image
+	if err := NewInspector().Inspect(&img, bytes.NewReader(out.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	want := imgrepo.Metadata{MimeType: "image/jpeg", Width: 64, Height: 64, Orientation: 6}
+	if diff := cmp.Diff(want, img.Metadata); diff != "" {
+		t.Errorf("Inspect() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The image data is unchanged.
+	before, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := jpeg.Decode(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before.(*image.YCbCr).Y, after.(*image.YCbCr).Y) {
+		t.Error("Strip() changed the image data")
+	}
+}
+
+func TestStripInvalid(t *testing.T) {
+	tests := map[string][]byte{
+		"truncated jpeg": withExif(t, gradient())[:12],
+		"truncated png": encode(t, wide(200), func(b *bytes.Buffer, img image.Image) error {
+			return png.Encode(b, img)
+		})[:40],
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := NewStripper().Strip(&bytes.Buffer{}, bytes.NewReader(data))
+			if !errors.Is(err, imgrepo.ErrInvalidArgument) {
+				t.Errorf("got %v, want %v", err, imgrepo.ErrInvalidArgument)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/algao1/imgrepo"
+	xdraw "golang.org/x/image/draw"
+)
+
+// _MaxDimension is the largest width or height a Transform may ask for.
+const _MaxDimension = 4096
+
+// Transformer implements imgrepo.ImageTransformer.
+type Transformer struct{}
+
+var _ imgrepo.ImageTransformer = (*Transformer)(nil)
+
+// NewTransformer returns a Transformer.
+func NewTransformer() *Transformer {
+	return &Transformer{}
+}
+
+// ValidateTransform checks that the transform can be applied.
+func ValidateTransform(t imgrepo.Transform) error {
+	if t.Width < 0 || t.Height < 0 || t.Width > _MaxDimension || t.Height > _MaxDimension {
+		return fmt.Errorf("size %dx%d out of range: %w", t.Width, t.Height, imgrepo.ErrInvalidArgument)
+	}
+	if t.Fit < imgrepo.Contain || t.Fit > imgrepo.Crop {
+		return fmt.Errorf("unknown fit %d: %w", t.Fit, imgrepo.ErrInvalidArgument)
+	}
+	if t.Quality < 0 || t.Quality > 100 {
+		return fmt.Errorf("quality %d out of range: %w", t.Quality, imgrepo.ErrInvalidArgument)
+	}
+
+	switch t.Format {
+	case "", "jpeg", "png", "gif", "webp":
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q: %w", t.Format, imgrepo.ErrInvalidArgument)
+	}
+}
+
+func (tf *Transformer) Transform(r io.Reader, t imgrepo.Transform) (*imgrepo.Transformed, error) {
+	if err := ValidateTransform(t); err != nil {
+		return nil, err
+	}
+
+	src, format, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if t.Format != "" {
+		format = t.Format
+	}
+
+	dst := transform(src, t)
+
+	quality := t.Quality
+	if quality == 0 {
+		quality = _Quality
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, flatten(dst), &jpeg.Options{Quality: quality})
+	case "png":
+		err = png.Encode(&buf, dst)
+	case "gif":
+		err = gif.Encode(&buf, dst, nil)
+	case "webp":
+		err = encodeWebP(&buf, dst, quality)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode image as %s: %w", format, err)
+	}
+
+	return &imgrepo.Transformed{Format: format, Data: buf.Bytes()}, nil
+}
+
+// transform resizes or crops src to the box of t.
+func transform(src image.Image, t imgrepo.Transform) image.Image {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	w, h := t.Width, t.Height
+	if (w == 0 && h == 0) || sw == 0 || sh == 0 {
+		return src
+	}
+
+	if t.Fit == imgrepo.Crop {
+		if w == 0 || w > sw {
+			w = sw
+		}
+		if h == 0 || h > sh {
+			h = sh
+		}
+		return crop(src, center(b, w, h))
+	}
+
+	// A missing side follows from the aspect ratio, so that both fits are
+	// the same. It is bounded like the sides of the box, so that long, thin
+	// images do not blow up, and the other side shrinks to keep the ratio.
+	if w == 0 {
+		w = atLeastOne(sw * h / sh)
+		if w > _MaxDimension {
+			w, h = _MaxDimension, atLeastOne(sh*_MaxDimension/sw)
+		}
+		return scale(src, b, w, h)
+	} else if h == 0 {
+		h = atLeastOne(sh * w / sw)
+		if h > _MaxDimension {
+			w, h = atLeastOne(sw*_MaxDimension/sh), _MaxDimension
+		}
+		return scale(src, b, w, h)
+	}
+
+	if t.Fit == imgrepo.Contain {
+		if sw*h > sh*w {
+			h = atLeastOne(sh * w / sw)
+		} else {
+			w = atLeastOne(sw * h / sh)
+		}
+		return scale(src, b, w, h)
+	}
+
+	// Cover scales the largest part of src with the aspect ratio of the box.
+	sr := center(b, sw, atLeastOne(sw*h/w))
+	if sw*h > sh*w {
+		sr = center(b, atLeastOne(sh*w/h), sh)
+	}
+	return scale(src, sr, w, h)
+}
+
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// center returns the rectangle of the given size in the center of b.
+func center(b image.Rectangle, w, h int) image.Rectangle {
+	min := b.Min.Add(image.Pt((b.Dx()-w)/2, (b.Dy()-h)/2))
+	return image.Rectangle{Min: min, Max: min.Add(image.Pt(w, h))}
+}
+
+// crop copies the rectangle sr of src.
+func crop(src image.Image, sr image.Rectangle) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, sr.Dx(), sr.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, sr.Min, draw.Src)
+	return dst
+}
+
+// scale scales the rectangle sr of src to the given size.
+func scale(src image.Image, sr image.Rectangle, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.BiLinear.Scale(dst, dst.Bounds(), src, sr, draw.Src, nil)
+	return dst
+}
+
+// flatten draws img over a white background, for formats without
+// transparency.
+func flatten(img image.Image) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
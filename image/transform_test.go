@@ -0,0 +1,123 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+)
+
+func TestTransform(t *testing.T) {
+	tests := map[string]struct {
+		img        image.Image
+		transform  imgrepo.Transform
+		wantSize   image.Point
+		wantFormat string
+	}{
+		"original": {
+			img:        wide(200),
+			wantSize:   image.Pt(200, 100),
+			wantFormat: "png",
+		},
+		"contain": {
+			img:        wide(200),
+			transform:  imgrepo.Transform{Width: 50, Height: 50, Format: "jpeg"},
+			wantSize:   image.Pt(50, 25),
+			wantFormat: "jpeg",
+		},
+		"contain upscaled": {
+			img:        wide(200),
+			transform:  imgrepo.Transform{Width: 400},
+			wantSize:   image.Pt(400, 200),
+			wantFormat: "png",
+		},
+		"cover": {
+			img:        wide(200),
+			transform:  imgrepo.Transform{Width: 50, Height: 50, Fit: imgrepo.Cover},
+			wantSize:   image.Pt(50, 50),
+			wantFormat: "png",
+		},
+		"cover height only": {
+			img:        wide(200),
+			transform:  imgrepo.Transform{Height: 50, Fit: imgrepo.Cover},
+			wantSize:   image.Pt(100, 50),
+			wantFormat: "png",
+		},
+		"crop": {
+			img:        wide(200),
+			transform:  imgrepo.Transform{Width: 50, Height: 50, Fit: imgrepo.Crop, Format: "gif"},
+			wantSize:   image.Pt(50, 50),
+			wantFormat: "gif",
+		},
+		"long and thin": {
+			img:        image.NewRGBA(image.Rect(0, 0, 50000, 10)),
+			transform:  imgrepo.Transform{Height: _MaxDimension},
+			wantSize:   image.Pt(_MaxDimension, 1),
+			wantFormat: "png",
+		},
+		"tall and narrow": {
+			img:        image.NewRGBA(image.Rect(0, 0, 10, 50000)),
+			transform:  imgrepo.Transform{Width: 100, Fit: imgrepo.Cover},
+			wantSize:   image.Pt(1, _MaxDimension),
+			wantFormat: "png",
+		},
+		"crop larger than image": {
+			img:        wide(200),
+			transform:  imgrepo.Transform{Width: 300, Fit: imgrepo.Crop},
+			wantSize:   image.Pt(200, 100),
+			wantFormat: "png",
+		},
+	}
+
+	tf := NewTransformer()
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := encode(t, tc.img, func(b *bytes.Buffer, img image.Image) error {
+				return png.Encode(b, img)
+			})
+
+			out, err := tf.Transform(bytes.NewReader(data), tc.transform)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(out.Data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tc.wantFormat || out.Format != tc.wantFormat {
+				t.Errorf("got format %s (%s), want %s", format, out.Format, tc.wantFormat)
+			}
+			if got := image.Pt(cfg.Width, cfg.Height); got != tc.wantSize {
+				t.Errorf("got size %v, want %v", got, tc.wantSize)
+			}
+		})
+	}
+}
+
+func TestTransformInvalid(t *testing.T) {
+	tests := map[string]imgrepo.Transform{
+		"negative width": {Width: -1},
+		"too high":       {Height: _MaxDimension + 1},
+		"unknown fit":    {Fit: imgrepo.Crop + 1},
+		"unknown format": {Format: "bmp"},
+		"quality":        {Quality: 101},
+	}
+
+	tf := NewTransformer()
+	data := encode(t, gradient(), func(b *bytes.Buffer, img image.Image) error {
+		return png.Encode(b, img)
+	})
+
+	for name, transform := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tf.Transform(bytes.NewReader(data), transform)
+			if !errors.Is(err, imgrepo.ErrInvalidArgument) {
+				t.Errorf("got %v, want %v", err, imgrepo.ErrInvalidArgument)
+			}
+		})
+	}
+}
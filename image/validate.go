@@ -0,0 +1,80 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+
+	"github.com/algao1/imgrepo"
+)
+
+// _SniffLen is how much of an image is read to detect its type.
+const _SniffLen = 512
+
+// DecodableTypes are the MIME types of the formats Decode supports.
+var DecodableTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// Validator implements imgrepo.ImageValidator, accepting images of the
+// given types, detected from their content, up to a number of pixels. The
+// dimensions are read from the headers, so oversized images are rejected
+// without being decoded.
+type Validator struct {
+	types     map[string]bool
+	maxPixels int64
+}
+
+var _ imgrepo.ImageValidator = (*Validator)(nil)
+
+// NewValidator returns a Validator accepting images of the given MIME types,
+// which must be decodable, with at most maxPixels pixels.
+func NewValidator(types []string, maxPixels int64) (*Validator, error) {
+	if maxPixels <= 0 {
+		return nil, fmt.Errorf("maximum pixel count %d: %w", maxPixels, imgrepo.ErrInvalidArgument)
+	}
+
+	v := &Validator{types: make(map[string]bool), maxPixels: maxPixels}
+	for _, t := range types {
+		if !decodable(t) {
+			return nil, fmt.Errorf("type %q cannot be decoded: %w", t, imgrepo.ErrInvalidArgument)
+		}
+		v.types[t] = true
+	}
+
+	return v, nil
+}
+
+func decodable(t string) bool {
+	for _, d := range DecodableTypes {
+		if d == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *Validator) Validate(r io.Reader) error {
+	head := make([]byte, _SniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("%q: %w", "unable to read image", err)
+	}
+	head = head[:n]
+
+	mime := http.DetectContentType(head)
+	if !v.types[mime] {
+		return fmt.Errorf("content type %s is not allowed: %w", mime, imgrepo.ErrInvalidArgument)
+	}
+
+	cfg, _, err := image.DecodeConfig(io.MultiReader(bytes.NewReader(head), r))
+	if err != nil {
+		return fmt.Errorf("unable to decode image: %v: %w", err, imgrepo.ErrInvalidArgument)
+	}
+
+	if pixels := int64(cfg.Width) * int64(cfg.Height); pixels > v.maxPixels {
+		return fmt.Errorf("image of %dx%d pixels exceeds %d pixels: %w", cfg.Width, cfg.Height, v.maxPixels, imgrepo.ErrTooLarge)
+	}
+
+	return nil
+}
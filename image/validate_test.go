@@ -0,0 +1,98 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+)
+
+func TestValidate(t *testing.T) {
+	pngData := func(img image.Image) []byte {
+		return encode(t, img, func(b *bytes.Buffer, img image.Image) error {
+			return png.Encode(b, img)
+		})
+	}
+
+	tests := map[string]struct {
+		types     []string
+		maxPixels int64
+		data      []byte
+		wantErr   error
+	}{
+		"jpeg": {
+			types:     DecodableTypes,
+			maxPixels: 50000000,
+			data:      readFile(t, "../_data/apple1.jpg"),
+		},
+		"type not allowed": {
+			types:     []string{"image/jpeg"},
+			maxPixels: 50000000,
+			data:      pngData(gradient()),
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+		"svg": {
+			types:     DecodableTypes,
+			maxPixels: 50000000,
+			data:      readFile(t, "../_data/orange.svg"),
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+		"text": {
+			types:     DecodableTypes,
+			maxPixels: 50000000,
+			data:      []byte("SECRET=hunter2\n"),
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+		"empty": {
+			types:     DecodableTypes,
+			maxPixels: 50000000,
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+		"too many pixels": {
+			types:     DecodableTypes,
+			maxPixels: 1000000,
+			data:      pngData(image.NewGray(image.Rect(0, 0, 2000, 1000))),
+			wantErr:   imgrepo.ErrTooLarge,
+		},
+		"exactly max pixels": {
+			types:     DecodableTypes,
+			maxPixels: 2000000,
+			data:      pngData(image.NewGray(image.Rect(0, 0, 2000, 1000))),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v, err := NewValidator(tc.types, tc.maxPixels)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := v.Validate(bytes.NewReader(tc.data)); !errors.Is(err, tc.wantErr) {
+				t.Errorf("got %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewValidatorInvalid(t *testing.T) {
+	tests := map[string]struct {
+		types     []string
+		maxPixels int64
+	}{
+		"not decodable":   {types: []string{"image/svg+xml"}, maxPixels: 1},
+		"no pixels":       {types: DecodableTypes, maxPixels: 0},
+		"negative pixels": {types: DecodableTypes, maxPixels: -1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewValidator(tc.types, tc.maxPixels); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+				t.Errorf("got %v, want %v", err, imgrepo.ErrInvalidArgument)
+			}
+		})
+	}
+}
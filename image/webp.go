@@ -0,0 +1,22 @@
+//go:build cgo
+// +build cgo
+
+package image
+
+import (
+	"image"
+	"io"
+
+	"github.com/chai2010/webp"
+)
+
+// encodeWebP encodes img as a lossy WebP with libwebp, which needs cgo.
+func encodeWebP(w io.Writer, img image.Image, quality int) error {
+	data, err := webp.EncodeRGBA(img, float32(quality))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
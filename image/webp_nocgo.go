@@ -0,0 +1,17 @@
+//go:build !cgo
+// +build !cgo
+
+package image
+
+import (
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/algao1/imgrepo"
+)
+
+// encodeWebP fails, since WebP encoding needs libwebp, and so cgo.
+func encodeWebP(w io.Writer, img image.Image, quality int) error {
+	return fmt.Errorf("%q: %w", "webp encoding requires cgo", imgrepo.ErrInvalidArgument)
+}
@@ -0,0 +1,29 @@
+//go:build cgo
+// +build cgo
+
+package image
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+)
+
+func TestTransformWebP(t *testing.T) {
+	data := readFile(t, "../_data/apple1.jpg")
+
+	out, err := NewTransformer().Transform(bytes.NewReader(data), imgrepo.Transform{Width: 64, Format: "webp", Quality: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(out.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "webp" || cfg.Width != 64 {
+		t.Errorf("got %s %dx%d, want 64 pixels wide webp", format, cfg.Width, cfg.Height)
+	}
+}
@@ -1,5 +1,11 @@
 package imgrepo
 
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
 // ADD image(s) to the repository:
 // 		X: one / bulk / enormous amount of images
 // 		X: private or public (permissions)
@@ -7,8 +13,6 @@ package imgrepo
 
 // TODO:
 // > Add a total #match to ListImages.
-// > Probably should store Owner info alongside session token,
-// 	 		address at a later date.
 // > Refactor code involving server.go and client.go
 //      consider moving them in proto/ to contain dependency
 
@@ -19,75 +23,710 @@ const (
 	Private
 )
 
+// DuplicatePolicy determines what happens to an upload that is perceptually
+// identical to an image the uploader already owns.
+type DuplicatePolicy int
+
+const (
+	AllowDuplicates DuplicatePolicy = iota
+	SkipDuplicates
+	RejectDuplicates
+)
+
+// Privacy determines what metadata of an uploaded image is kept. Stripped
+// images are stored without their EXIF, XMP and IPTC blocks, other than
+// their orientation.
+type Privacy int
+
+const (
+	// DefaultPrivacy applies the default of the server to public images,
+	// and keeps the metadata of private ones.
+	DefaultPrivacy Privacy = iota
+	// KeepMetadata stores the image as uploaded.
+	KeepMetadata
+	// StripLocation strips the image, and keeps its capture time and camera
+	// in the registry, but not its location.
+	StripLocation
+	// StripMetadata strips the image, and keeps only its type, dimensions
+	// and orientation in the registry.
+	StripMetadata
+)
+
+// Role determines what an account may do. Each role may do anything the
+// roles before it may.
+type Role int
+
+const (
+	UserRole Role = iota
+	ModeratorRole
+	AdminRole
+)
+
+// Account is a registered user, without their password.
+type Account struct {
+	Username string
+	Role     Role
+	Disabled bool // disabled accounts can neither log in nor use their sessions
+}
+
+// Usage is the storage used by the images of a user. Images sharing a blob
+// are counted once each.
+type Usage struct {
+	Username string
+	Images   int
+	Bytes    int64
+}
+
 // Image contains information about the image.
 type Image struct {
 	Id     string `bson:"_id" json:"_id,omitempty"`
 	Name   string
 	Owner  string
 	Access Permission
-	Raw    []byte `bson:"-"` // unused in registry
-	Hash   uint64 // unimplemented
-	Kind   int    // unimplemented
+	Digest string // hex encoded SHA-256 of the raw image
+	Size   int64  // size of the raw image in bytes, 0 if uploaded before sizes were kept
+	Hash   uint64 // perceptual hash of the decoded image
+	Kind   int    // kind of hash, 0 if the image has not been hashed
+
+	Renditions []int    // sizes of the stored renditions, smallest first
+	Tags       []string // lower case labels, sorted
+	Metadata   Metadata
+
+	// Grant is what makes a private image viewable to others.
+	Grant `bson:",inline"`
+}
+
+// Viewable reports whether the requester, a member of the groups, may view
+// the image.
+func (img *Image) Viewable(requester string, groups []string) bool {
+	return img.Owner == requester || img.Access == Public || img.Includes(requester, groups)
+}
+
+// Grant names the users and groups the owner of an image, or album, granted
+// read access to.
+type Grant struct {
+	Readers []string `bson:",omitempty"`
+	Groups  []string `bson:",omitempty"`
+}
+
+// Includes reports whether the grant includes the requester, or one of the
+// groups the requester is a member of.
+func (g Grant) Includes(requester string, groups []string) bool {
+	for _, user := range g.Readers {
+		if user == requester {
+			return true
+		}
+	}
+	for _, group := range g.Groups {
+		for _, member := range groups {
+			if group == member {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Empty reports whether the grant names no one.
+func (g Grant) Empty() bool {
+	return len(g.Readers) == 0 && len(g.Groups) == 0
+}
+
+// Metadata describes the content of an image, as found on upload. The EXIF
+// fields are zero when the image has none.
+type Metadata struct {
+	MimeType string // sniffed from the content, regardless of the name
+	Width    int
+	Height   int
+
+	Taken       time.Time // capture time
+	Make        string    // camera make
+	Model       string    // camera model
+	Orientation int       // EXIF orientation, from 1 to 8
+	Location    *Location // where the image was captured
+}
+
+// Location is a GPS position, in degrees.
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Field names a field of an image that can be changed after upload.
+type Field string
+
+const (
+	NameField     Field = "name"
+	AccessField   Field = "access"
+	TakenField    Field = "metadata.taken"
+	LocationField Field = "metadata.location"
+	TagsField     Field = "tags"
+)
+
+// TagFilter selects the images with any of the tags in Any, and all of the
+// tags in All. Empty lists select every image.
+type TagFilter struct {
+	Any []string
+	All []string
+}
+
+// Matches reports whether an image with the given tags is selected.
+func (f TagFilter) Matches(tags []string) bool {
+	has := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		has[t] = true
+	}
+
+	for _, t := range f.All {
+		if !has[t] {
+			return false
+		}
+	}
+	for _, t := range f.Any {
+		if has[t] {
+			return true
+		}
+	}
+	return len(f.Any) == 0
+}
+
+// Album is a named collection of images with an access of its own, so that
+// a public album shows the images of its owner whatever their access.
+type Album struct {
+	Id     string `bson:"_id" json:"_id,omitempty"`
+	Name   string
+	Owner  string
+	Access Permission
+	Images []string // ids of the images, in the order they were added
+
+	// Grant is what makes a private album viewable to others.
+	Grant `bson:",inline"`
+}
+
+// Viewable reports whether the requester, a member of the groups, may view
+// the album.
+func (a *Album) Viewable(requester string, groups []string) bool {
+	return a.Owner == requester || a.Access == Public || a.Includes(requester, groups)
+}
+
+// Group is a named set of users, which images and albums can be shared
+// with. Admins manage the group, and are members themselves.
+type Group struct {
+	Name    string `bson:"_id"`
+	Admins  []string
+	Members []string
+}
+
+// IsAdmin reports whether the user administers the group.
+func (g *Group) IsAdmin(user string) bool {
+	for _, admin := range g.Admins {
+		if admin == user {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMember reports whether the user is a member of the group.
+func (g *Group) IsMember(user string) bool {
+	for _, member := range g.Members {
+		if member == user {
+			return true
+		}
+	}
+	return false
+}
+
+// ShareLink lets anyone holding its token download an image of the owner,
+// without an account, until it expires or has been used up.
+type ShareLink struct {
+	Id      string `bson:"_id"`
+	ImageId string
+	Owner   string
+	Created time.Time
+
+	// Expires is zero if the link never expires.
+	Expires time.Time
+
+	// MaxDownloads is 0 if the link may be used any number of times.
+	MaxDownloads int
+	Downloads    int
+
+	// Token is the secret the link is redeemed with. It is derived from the
+	// id, and not stored.
+	Token string `bson:"-"`
+}
+
+// Expired reports whether the link can no longer be used at time now.
+func (l *ShareLink) Expired(now time.Time) bool {
+	return (!l.Expires.IsZero() && !now.Before(l.Expires)) ||
+		(l.MaxDownloads > 0 && l.Downloads >= l.MaxDownloads)
+}
+
+// ListFilter selects the images listed by ImageRegistry.List.
+type ListFilter struct {
+	TagFilter
+
+	// Shared only selects the images others granted the requester, or one
+	// of their groups, read access to.
+	Shared bool
+}
+
+// Rendition is a resized copy of an image, encoded as JPEG, which fits in
+// a square of Size pixels.
+type Rendition struct {
+	Size int
+	Data []byte
+}
+
+// RenditionId returns the storage id of the rendition of the given size of
+// the blob with the given storage id.
+func RenditionId(blobId string, size int) string {
+	return fmt.Sprintf("%s-%d", blobId, size)
+}
+
+// ImageRenderer generates the renditions of images.
+type ImageRenderer interface {
+	// Render decodes the image from r, and returns its renditions.
+	// Returns nil on success, and error otherwise.
+	Render(r io.Reader) ([]Rendition, error)
+}
+
+// Fit determines how an image is resized to the box of a Transform.
+type Fit int
+
+const (
+	Contain Fit = iota // scale to fit in the box, preserving the aspect ratio
+	Cover              // scale to cover the box, and crop what overflows
+	Crop               // cut the box from the center, without scaling
+)
+
+// Transform describes how an image is converted on download. A zero width
+// or height follows from the aspect ratio, or the image if both are zero,
+// an empty format keeps the format of the image, and a zero quality is the
+// default of the format.
+type Transform struct {
+	Width   int
+	Height  int
+	Fit     Fit
+	Format  string // one of jpeg, png, gif or webp
+	Quality int    // from 1 to 100, only used by lossy formats
+}
+
+// Key returns the key caching the transformed image with the given digest.
+func (t Transform) Key(digest string) string {
+	return fmt.Sprintf("%s/%dx%d/%d/%s/%d", digest, t.Width, t.Height, t.Fit, t.Format, t.Quality)
 }
 
-// Unimplemented feature.
+// Transformed is an image converted by a Transform.
+type Transformed struct {
+	Format string
+	Data   []byte
+}
+
+// ImageTransformer converts images on download.
+type ImageTransformer interface {
+	// Transform decodes the image from r, and returns it converted by t.
+	// Returns nil on success, and error otherwise.
+	Transform(r io.Reader, t Transform) (*Transformed, error)
+}
+
+// ImageCache keeps transformed images, so that repeated requests are cheap.
+type ImageCache interface {
+	// Get returns the image cached under key, if any.
+	Get(key string) (*Transformed, bool)
+
+	// Put caches the image under key, possibly evicting other images.
+	Put(key string, img *Transformed)
+}
+
+// ImageValidator rejects unwanted images before they are decoded.
+type ImageValidator interface {
+	// Validate reads the headers of the image from r, and checks its type
+	// and dimensions.
+	// Returns nil on success, and error otherwise.
+	Validate(r io.Reader) error
+}
+
+// ImageInspector extracts the metadata of images.
+type ImageInspector interface {
+	// Inspect reads the image from r, and sets img.Metadata. Only the
+	// headers of the image may be read.
+	// Returns nil on success, and error otherwise.
+	Inspect(img *Image, r io.Reader) error
+}
+
+// ImageStripper removes the metadata blocks of images.
+type ImageStripper interface {
+	// Strip copies the image from r to w without its EXIF, XMP and IPTC
+	// blocks, keeping only its orientation. Images of formats it does not
+	// know are copied as is.
+	// Returns nil on success, and error otherwise.
+	Strip(w io.Writer, r io.Reader) error
+}
+
+// ImageComparator compares images by their content, rather than their bytes.
 type ImageComparator interface {
-	SetHash(img *Image) error
-	Difference(img *Image) (int, error)
+	// SetHash decodes the image from r, and sets img.Hash and img.Kind.
+	// Returns nil on success, and error otherwise.
+	SetHash(img *Image, r io.Reader) error
+
+	// Difference returns the distance between the hashes of two images,
+	// where 0 means the images are most likely the same picture.
+	// Returns nil on success, and error otherwise.
+	Difference(img1, img2 *Image) (int, error)
+}
+
+// Match is an image found by a similarity search, with the distance between
+// its hash and the hash searched for.
+type Match struct {
+	Image    *Image
+	Distance int
+}
+
+// ImageIndex finds images with similar hashes, without comparing the hash
+// searched for against every image.
+type ImageIndex interface {
+	// Add indexes the image by its hash.
+	// Returns nil on success, and error otherwise.
+	Add(img *Image) error
+
+	// Remove removes the image with the corresponding id from the index.
+	// Returns nil on success, and error otherwise.
+	Remove(id string) error
+
+	// Search returns the indexed images whose hash is within maxDist of
+	// the hash of img, closest first. The images only carry their id and
+	// hash.
+	// Returns nil on success, and error otherwise.
+	Search(img *Image, maxDist int) ([]Match, error)
 }
 
 // ImageStorage manages the storage of the raw image.
 type ImageStorage interface {
-	// Upload uploads the image to the blob storage.
+	// Upload streams the image from r to the blob storage under id.
+	// Returns nil on success, and error otherwise.
+	Upload(id string, r io.Reader) error
+
+	// Download returns a reader streaming the image with the corresponding
+	// id, which must be closed by the caller.
 	// Returns nil on success, and error otherwise.
-	Upload(img *Image) error
+	Download(id string) (io.ReadCloser, error)
 
-	// Download downloads the image with the corresponding id.
+	// Delete deletes the image with the corresponding id.
 	// Returns nil on success, and error otherwise.
-	Download(id string) ([]byte, error)
+	Delete(id string) error
 }
 
 // ImageRegistry manages access (upload/download/list) of images.
 type ImageRegistry interface {
 	// Upload generates an entry (with id) in the registry, and
-	// uploads the image to the blob storage.
+	// streams the image from r to the blob storage, along with its
+	// renditions, which are listed in img.Renditions.
+	// Returns nil on success, and error otherwise.
+	Upload(img *Image, r io.Reader, renditions ...Rendition) error
+
+	// Download looks for the id in the registry, and returns the entry
+	// with a reader streaming the image, which must be closed by the caller.
+	// Returns nil on success, and error otherwise.
+	Download(requester, id string) (*Image, io.ReadCloser, error)
+
+	// Rendition is like Download, but streams the rendition of the given
+	// size instead of the image.
+	// Returns nil on success, and error otherwise.
+	Rendition(requester, id string, size int) (*Image, io.ReadCloser, error)
+
+	// List returns a list of images viewable by the requester, and selected
+	// by the filter.
+	List(size int, requester string, lastId string, filter ListFilter) ([]*Image, error)
+
+	// Find returns the images with the given ids viewable by the requester,
+	// in the same order. Ids that are missing, or not viewable, are skipped.
+	Find(requester string, ids ...string) ([]*Image, error)
+
+	// Stat returns the entry with the given id, regardless of access.
+	// Returns nil on success, and error otherwise.
+	Stat(id string) (*Image, error)
+
+	// Walk calls fn with every image in the registry, regardless of access,
+	// and stops at the first error, which is returned.
+	Walk(fn func(img *Image) error) error
+
+	// Update sets the given fields of the entry with id img.Id to their
+	// values in img, and returns the updated entry. Only the owner may
+	// update an image.
+	// Returns nil on success, and error otherwise.
+	Update(requester string, img *Image, fields ...Field) (*Image, error)
+
+	// Replace swaps the blob of the entry with id img.Id, if its digest is
+	// still digest, for the image streamed from r, whose Digest and Size
+	// are those of img, along with its renditions. The access and metadata
+	// of the entry are set to those of img in the same step, and the entry
+	// keeps its id. Only the owner may replace an image.
+	// Returns nil on success, and error otherwise.
+	Replace(requester, digest string, img *Image, r io.Reader, renditions ...Rendition) (*Image, error)
+
+	// Share grants the users and groups of g read access to the entry with
+	// the given id, and returns the updated entry. Only the owner may share
+	// an image.
+	// Returns nil on success, and error otherwise.
+	Share(requester, id string, g Grant) (*Image, error)
+
+	// Unshare revokes the read access of the users and groups of g to the
+	// entry with the given id, and returns the updated entry. Only the owner
+	// may unshare an image.
+	// Returns nil on success, and error otherwise.
+	Unshare(requester, id string, g Grant) (*Image, error)
+
+	// Delete removes the entry from the registry, and deletes the image
+	// from the blob storage. Only the owner may delete an image.
+	// Returns nil on success, and error otherwise.
+	Delete(requester, id string) error
+}
+
+// AlbumRegistry manages albums, and the images in them. Only the owner of an
+// album may change it, and only add the images they own.
+type AlbumRegistry interface {
+	// Create generates an entry (with id) for the album, which starts with
+	// the images in album.Images.
+	// Returns nil on success, and error otherwise.
+	Create(album *Album) error
+
+	// Find returns the album with the corresponding id, if viewable by the
+	// requester.
+	// Returns nil on success, and error otherwise.
+	Find(requester, id string) (*Album, error)
+
+	// List returns a list of albums viewable by the requester.
+	List(size int, requester string, lastId string) ([]*Album, error)
+
+	// Rename sets the name of the album.
+	// Returns nil on success, and error otherwise.
+	Rename(requester, id, name string) error
+
+	// SetAccess sets the access of the album, leaving the access of its
+	// images as is.
+	// Returns nil on success, and error otherwise.
+	SetAccess(requester, id string, access Permission) error
+
+	// Add adds the images with the given ids to the album, skipping those
+	// already in it.
+	// Returns nil on success, and error otherwise.
+	Add(requester, id string, imageIds ...string) error
+
+	// Remove removes the images with the given ids from the album.
+	// Returns nil on success, and error otherwise.
+	Remove(requester, id string, imageIds ...string) error
+
+	// Share grants the users and groups of g read access to the album.
+	// Returns nil on success, and error otherwise.
+	Share(requester, id string, g Grant) error
+
+	// Unshare revokes the read access of the users and groups of g to the
+	// album.
 	// Returns nil on success, and error otherwise.
-	Upload(img *Image) error
+	Unshare(requester, id string, g Grant) error
 
-	// Download looks for the id in the registry, and downloads
-	// the image if it exists.
+	// Images returns a list of the images in the album, if viewable by the
+	// requester, paginated like ImageRegistry.List. The album grants access
+	// to all of its images whatever their own access, and they are returned
+	// as stored, so callers must hide what only their owner may see. Images
+	// deleted since they were added are skipped.
+	Images(size int, requester, id, lastId string) ([]*Image, error)
+
+	// Delete removes the album, but not its images.
+	// Returns nil on success, and error otherwise.
+	Delete(requester, id string) error
+}
+
+// UploadStore persists the state of resumable uploads until they are
+// committed to the ImageRegistry.
+type UploadStore interface {
+	// Begin starts an upload of the image, and returns the upload id.
+	// Returns nil on success, and error otherwise.
+	Begin(img *Image) (string, error)
+
+	// Write appends the data from r to the upload, where r starts at offset.
+	// The offset may be before the committed offset, in which case the
+	// overlapping data is skipped, but not after it.
+	// Returns the committed offset, even if an error occurs.
+	Write(id string, offset int64, r io.Reader) (int64, error)
+
+	// Query returns the image being uploaded, and the committed offset.
+	// Returns nil on success, and error otherwise.
+	Query(id string) (*Image, int64, error)
+
+	// Open returns a reader over the uploaded data, which must be closed
+	// by the caller.
+	// Returns nil on success, and error otherwise.
+	Open(id string) (io.ReadCloser, error)
+
+	// Remove discards the upload.
+	// Returns nil on success, and error otherwise.
+	Remove(id string) error
+
+	// Claim marks the upload as being committed, until release is called.
+	// Writes and other claims of a claimed upload fail with
+	// ErrPermissionDenied, so its data stays the same during the commit.
 	// Returns nil on success, and error otherwise.
-	Download(requester, id string) (*Image, error)
+	Claim(id string) (release func(), err error)
 
-	// List returns a list of images viewable by the requester.
-	List(size int, requester string, lastId string) ([]*Image, error)
+	// Sweep discards the uploads last written before the given time, unless
+	// they are claimed, and returns how many it discarded. Uploads are
+	// never committed if the client goes away, so they must be swept.
+	// Returns nil on success, and error otherwise.
+	Sweep(before time.Time) (int, error)
 }
 
 // UserService manages user account information, such as registering
 // an account, and logging in.
 type UserService interface {
-	// Register registers an account.
+	// Register registers an account, with the user role.
 	// Returns nil on success, and error otherwise.
 	Register(username, password string) error
 
-	// Login verifies that the account is valid.
+	// Login verifies that the account is valid, and not disabled.
 	// Returns nil on success, and error otherwise.
 	Login(username, password string) error
+
+	// Exists reports whether an account is registered under the username.
+	Exists(username string) (bool, error)
+
+	// Account returns the account registered under the username.
+	// Returns nil on success, and error otherwise.
+	Account(username string) (*Account, error)
+
+	// Accounts returns every account, sorted by username.
+	Accounts() ([]*Account, error)
+
+	// SetRole sets the role of the account.
+	// Returns nil on success, and error otherwise.
+	SetRole(username string, role Role) error
+
+	// SetDisabled disables the account, or enables it again.
+	// Returns nil on success, and error otherwise.
+	SetDisabled(username string, disabled bool) error
+}
+
+// GroupService manages groups of users. Only the admins of a group may
+// change it, but any member may leave it.
+type GroupService interface {
+	// Create creates a group, administered by the requester. The names of
+	// deleted groups are not reused.
+	// Returns nil on success, and error otherwise.
+	Create(requester, name string) (*Group, error)
+
+	// Find returns the group, if the requester is a member.
+	// Returns nil on success, and error otherwise.
+	Find(requester, name string) (*Group, error)
+
+	// Exists reports whether a group is named name.
+	Exists(name string) (bool, error)
+
+	// Groups returns the names of the groups the user is a member of,
+	// sorted.
+	Groups(user string) ([]string, error)
+
+	// AddMembers adds the users to the group, and returns the group.
+	// Returns nil on success, and error otherwise.
+	AddMembers(requester, name string, users ...string) (*Group, error)
+
+	// RemoveMembers removes the users from the group, and returns the
+	// group. Members may remove themselves, but the last admin may not.
+	// Returns nil on success, and error otherwise.
+	RemoveMembers(requester, name string, users ...string) (*Group, error)
+
+	// AddAdmins makes the users admins, and members, of the group, and
+	// returns the group.
+	// Returns nil on success, and error otherwise.
+	AddAdmins(requester, name string, users ...string) (*Group, error)
+
+	// RemoveAdmins makes the users members of the group only, and returns
+	// the group. A group always keeps an admin.
+	// Returns nil on success, and error otherwise.
+	RemoveAdmins(requester, name string, users ...string) (*Group, error)
+
+	// Delete removes the members of the group, which can no longer be
+	// found.
+	// Returns nil on success, and error otherwise.
+	Delete(requester, name string) error
+}
+
+// LinkRegistry keeps the share links of images. It does not check that the
+// images exist, or that their owner created the links.
+type LinkRegistry interface {
+	// Create adds the link, whose id must be unique, created now.
+	// Returns nil on success, and error otherwise.
+	Create(link *ShareLink) error
+
+	// Find returns the link with the given id.
+	// Returns nil on success, and error otherwise.
+	Find(id string) (*ShareLink, error)
+
+	// List returns the links owned by the requester, newest first.
+	List(requester string) ([]*ShareLink, error)
+
+	// Redeem counts a download of the link, and returns the link. Returns
+	// ErrExpired if the link has expired, or was used up.
+	Redeem(id string) (*ShareLink, error)
+
+	// Revoke deletes a link owned by the requester.
+	// Returns nil on success, and error otherwise.
+	Revoke(requester, id string) error
 }
 
 // SessionService manages user sessions.
 type SessionService interface {
-	// NewSession creates a session, and returns an UUID key.
-	NewSession() (string, error)
+	// NewSession creates a session for the user, and returns an UUID key.
+	NewSession(username string) (string, error)
 
-	// IsSession checks if a session exists with the UUID key.
-	IsSession(uuid string) error
+	// IsSession checks if a session exists with the UUID key, and returns
+	// the username it belongs to. Returns ErrUnauthenticated if there is no
+	// such session, or it expired.
+	IsSession(uuid string) (string, error)
 }
 
 type ImageClient interface {
 	Register(username, password string) error
 	Login(username, password string) error
-	Upload(img *Image) error
-	Download(id string) (*Image, error)
-	List(lastId string) ([]*Image, error)
+	Upload(img *Image, r io.ReadSeeker, policy DuplicatePolicy, privacy Privacy) ([]string, error)
+	ResumeUpload(uploadId string, r io.ReadSeeker, policy DuplicatePolicy, privacy Privacy) ([]string, error)
+	Download(id string, rendition int, w io.Writer) (*Image, error)
+	Transform(id string, t Transform, w io.Writer) (*Image, error)
+	List(lastId string, filter ListFilter) ([]*Image, error)
+	Update(img *Image, fields ...Field) (*Image, error)
+	Share(id string, g Grant) (*Image, error)
+	Unshare(id string, g Grant) (*Image, error)
+	CreateGroup(name string) (*Group, error)
+	Group(name string) (*Group, error)
+	Groups() ([]string, error)
+	AddMembers(name string, users ...string) (*Group, error)
+	RemoveMembers(name string, users ...string) (*Group, error)
+	AddAdmins(name string, users ...string) (*Group, error)
+	RemoveAdmins(name string, users ...string) (*Group, error)
+	DeleteGroup(name string) error
+	CreateAlbum(name string, access Permission, imageIds ...string) (*Album, error)
+	Albums(lastId string) ([]*Album, error)
+	AlbumImages(id, lastId string) ([]*Image, error)
+	AddToAlbum(id string, imageIds ...string) (*Album, error)
+	RemoveFromAlbum(id string, imageIds ...string) (*Album, error)
+	ShareAlbum(id string, g Grant) (*Album, error)
+	UnshareAlbum(id string, g Grant) (*Album, error)
+	DownloadFromAlbum(albumId, id string, rendition int, w io.Writer) (*Image, error)
+	DeleteAlbum(id string) error
+	CreateShareLink(id string, expires time.Time, maxDownloads int) (*ShareLink, error)
+	ShareLinks() ([]*ShareLink, error)
+	RevokeShareLink(id string) error
+	DownloadShared(token string, rendition int, w io.Writer) (*Image, error)
+	Delete(id string) error
+	Similar(id string, r io.Reader, maxDist int) ([]Match, error)
+}
+
+// AdminClient is the client of the RPCs restricted to moderators and admins.
+type AdminClient interface {
+	Users() ([]*Account, error)
+	SetRole(username string, role Role) error
+	SetDisabled(username string, disabled bool) error
+	ForceDelete(id string) error
+	StorageUsage() ([]Usage, error)
 }
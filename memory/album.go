@@ -0,0 +1,297 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/algao1/imgrepo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AlbumRegistry keeps albums in memory, next to an ImageRegistry, which
+// holds their images. Ids are generated as ObjectIDs, like
+// mongo.AlbumRegistry. Albums only hold the ids of their images, so deleting
+// an image leaves its id behind until removed.
+type AlbumRegistry struct {
+	mu     sync.RWMutex
+	albums map[string]imgrepo.Album
+	images *ImageRegistry
+	groups imgrepo.GroupService
+}
+
+var _ imgrepo.AlbumRegistry = (*AlbumRegistry)(nil)
+
+// NewAlbumRegistry returns an empty AlbumRegistry, whose images are held by
+// ir.
+func NewAlbumRegistry(ir *ImageRegistry) *AlbumRegistry {
+	return &AlbumRegistry{
+		albums: make(map[string]imgrepo.Album),
+		images: ir,
+		groups: ir.groups,
+	}
+}
+
+// update applies fn to the album under the registry lock, only if it is
+// owned by the requester. The lists of the album are replaced rather than
+// changed by fn, since earlier results share them.
+func (ar *AlbumRegistry) update(requester, id string, fn func(album *imgrepo.Album)) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	album, ok := ar.albums[id]
+	if !ok {
+		return fmt.Errorf("album %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	if album.Owner != requester {
+		return fmt.Errorf("unable to update album %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	fn(&album)
+	ar.albums[id] = album
+
+	return nil
+}
+
+// checkImages checks that the images with the given ids exist, and are
+// owned by the requester.
+func (ar *AlbumRegistry) checkImages(requester string, ids []string) error {
+	ar.images.mu.RLock()
+	defer ar.images.mu.RUnlock()
+
+	for _, id := range ids {
+		img, ok := ar.images.images[id]
+		if !ok {
+			return fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+		}
+		if img.Owner != requester {
+			return fmt.Errorf("unable to add file %s: %w", id, imgrepo.ErrPermissionDenied)
+		}
+	}
+
+	return nil
+}
+
+// Create adds the album, which must have a name, and may only hold images
+// owned by album.Owner.
+func (ar *AlbumRegistry) Create(album *imgrepo.Album) error {
+	if album.Name == "" {
+		return fmt.Errorf("album without name: %w", imgrepo.ErrInvalidArgument)
+	}
+	if album.Access != imgrepo.Public && album.Access != imgrepo.Private {
+		return fmt.Errorf("unknown access %d: %w", album.Access, imgrepo.ErrInvalidArgument)
+	}
+
+	if err := ar.checkImages(album.Owner, album.Images); err != nil {
+		return err
+	}
+
+	album.Images = union(nil, album.Images)
+	album.Id = primitive.NewObjectID().Hex()
+
+	ar.mu.Lock()
+	ar.albums[album.Id] = *album
+	ar.mu.Unlock()
+
+	return nil
+}
+
+func (ar *AlbumRegistry) Find(requester, id string) (*imgrepo.Album, error) {
+	ar.mu.RLock()
+	album, ok := ar.albums[id]
+	ar.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("album %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	// The groups of the requester are only looked up if they matter.
+	var groups []string
+	if !album.Viewable(requester, nil) && len(album.Groups) > 0 {
+		var err error
+		groups, err = ar.groups.Groups(requester)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to find groups", err)
+		}
+	}
+	if !album.Viewable(requester, groups) {
+		return nil, fmt.Errorf("unable to access album %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	return &album, nil
+}
+
+func (ar *AlbumRegistry) List(size int, requester, lastId string) ([]*imgrepo.Album, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("negative page size %d: %w", size, imgrepo.ErrInvalidArgument)
+	}
+
+	groups, err := ar.groups.Groups(requester)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find groups", err)
+	}
+
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	ids := make([]string, 0, len(ar.albums))
+	for id, album := range ar.albums {
+		if !album.Viewable(requester, groups) {
+			continue
+		}
+		if len(lastId) > 0 && id >= lastId {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	if size > 0 && len(ids) > size {
+		ids = ids[:size]
+	}
+
+	var res []*imgrepo.Album
+	for _, id := range ids {
+		album := ar.albums[id]
+		res = append(res, &album)
+	}
+
+	return res, nil
+}
+
+func (ar *AlbumRegistry) Rename(requester, id, name string) error {
+	if name == "" {
+		return fmt.Errorf("album without name: %w", imgrepo.ErrInvalidArgument)
+	}
+
+	return ar.update(requester, id, func(album *imgrepo.Album) {
+		album.Name = name
+	})
+}
+
+func (ar *AlbumRegistry) SetAccess(requester, id string, access imgrepo.Permission) error {
+	if access != imgrepo.Public && access != imgrepo.Private {
+		return fmt.Errorf("unknown access %d: %w", access, imgrepo.ErrInvalidArgument)
+	}
+
+	return ar.update(requester, id, func(album *imgrepo.Album) {
+		album.Access = access
+	})
+}
+
+// Add checks the album before the images, so that others cannot tell which
+// images exist by adding them to albums they do not own.
+func (ar *AlbumRegistry) Add(requester, id string, imageIds ...string) error {
+	if len(imageIds) == 0 {
+		return fmt.Errorf("%q: %w", "no images to add", imgrepo.ErrInvalidArgument)
+	}
+
+	ar.mu.RLock()
+	album, ok := ar.albums[id]
+	ar.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("album %s: %w", id, imgrepo.ErrNotFound)
+	}
+	if album.Owner != requester {
+		return fmt.Errorf("unable to update album %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	if err := ar.checkImages(requester, imageIds); err != nil {
+		return err
+	}
+
+	return ar.update(requester, id, func(album *imgrepo.Album) {
+		album.Images = union(album.Images, imageIds)
+	})
+}
+
+func (ar *AlbumRegistry) Remove(requester, id string, imageIds ...string) error {
+	if len(imageIds) == 0 {
+		return fmt.Errorf("%q: %w", "no images to remove", imgrepo.ErrInvalidArgument)
+	}
+
+	return ar.update(requester, id, func(album *imgrepo.Album) {
+		album.Images = difference(album.Images, imageIds)
+	})
+}
+
+func (ar *AlbumRegistry) Share(requester, id string, g imgrepo.Grant) error {
+	if g.Empty() {
+		return fmt.Errorf("%q: %w", "no users or groups to share with", imgrepo.ErrInvalidArgument)
+	}
+
+	return ar.update(requester, id, func(album *imgrepo.Album) {
+		album.Readers = union(album.Readers, g.Readers)
+		album.Groups = union(album.Groups, g.Groups)
+	})
+}
+
+func (ar *AlbumRegistry) Unshare(requester, id string, g imgrepo.Grant) error {
+	if g.Empty() {
+		return fmt.Errorf("%q: %w", "no users or groups to unshare", imgrepo.ErrInvalidArgument)
+	}
+
+	return ar.update(requester, id, func(album *imgrepo.Album) {
+		album.Readers = difference(album.Readers, g.Readers)
+		album.Groups = difference(album.Groups, g.Groups)
+	})
+}
+
+// Images lists the images of the album newest first, like the images of
+// ImageRegistry.List. Only images of the owner of the album are listed,
+// whatever their access, since the album decides who may view them.
+func (ar *AlbumRegistry) Images(size int, requester, id, lastId string) ([]*imgrepo.Image, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("negative page size %d: %w", size, imgrepo.ErrInvalidArgument)
+	}
+
+	album, err := ar.Find(requester, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ar.images.mu.RLock()
+	defer ar.images.mu.RUnlock()
+
+	var ids []string
+	for _, imgId := range album.Images {
+		img, ok := ar.images.images[imgId]
+		if !ok || img.Owner != album.Owner {
+			continue
+		}
+		if len(lastId) > 0 && imgId >= lastId {
+			continue
+		}
+		ids = append(ids, imgId)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	if size > 0 && len(ids) > size {
+		ids = ids[:size]
+	}
+
+	var res []*imgrepo.Image
+	for _, imgId := range ids {
+		img := ar.images.images[imgId]
+		res = append(res, &img)
+	}
+
+	return res, nil
+}
+
+func (ar *AlbumRegistry) Delete(requester, id string) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	album, ok := ar.albums[id]
+	if !ok {
+		return fmt.Errorf("album %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	if album.Owner != requester {
+		return fmt.Errorf("unable to delete album %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+	delete(ar.albums, id)
+
+	return nil
+}
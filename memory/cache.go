@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/algao1/imgrepo"
+)
+
+type entry struct {
+	key string
+	img *imgrepo.Transformed
+}
+
+// ImageCache keeps transformed images in memory, up to a total size in
+// bytes, evicting the least recently used images first.
+type ImageCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	order    *list.List // of *entry, most recently used first
+	entries  map[string]*list.Element
+}
+
+var _ imgrepo.ImageCache = (*ImageCache)(nil)
+
+// NewImageCache returns an empty ImageCache holding up to maxBytes of image
+// data. Images larger than maxBytes are never cached.
+func NewImageCache(maxBytes int) *ImageCache {
+	return &ImageCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *ImageCache) Get(key string) (*imgrepo.Transformed, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+
+	return e.Value.(*entry).img, true
+}
+
+func (c *ImageCache) Put(key string, img *imgrepo.Transformed) {
+	if len(img.Data) > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+	c.entries[key] = c.order.PushFront(&entry{key: key, img: img})
+	c.size += len(img.Data)
+
+	for c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove evicts an entry, c.mu must be held.
+func (c *ImageCache) remove(e *list.Element) {
+	ent := c.order.Remove(e).(*entry)
+	delete(c.entries, ent.key)
+	c.size -= len(ent.img.Data)
+}
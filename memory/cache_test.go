@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/algao1/imgrepo"
+)
+
+func TestImageCache(t *testing.T) {
+	c := NewImageCache(10)
+
+	put := func(key string, size int) {
+		c.Put(key, &imgrepo.Transformed{Format: "png", Data: make([]byte, size)})
+	}
+	put("a", 4)
+	put("b", 4)
+
+	// Getting a makes b the least recently used.
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	put("c", 4)
+
+	tests := map[string]bool{"a": true, "b": false, "c": true}
+	for key, want := range tests {
+		if _, ok := c.Get(key); ok != want {
+			t.Errorf("Get(%q) = _, %v, want %v", key, ok, want)
+		}
+	}
+
+	put("large", 11)
+	if _, ok := c.Get("large"); ok {
+		t.Error("image larger than the cache was cached")
+	}
+
+	// Replacing an entry does not count it twice.
+	put("a", 6)
+	if _, ok := c.Get("c"); !ok {
+		t.Error("c evicted by a replaced entry")
+	}
+	if c.size != 10 {
+		t.Errorf("got size %d, want 10", c.size)
+	}
+}
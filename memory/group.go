@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/algao1/imgrepo"
+)
+
+// GroupService keeps groups in memory. Deleted groups are kept without
+// members, so that their names are not reused.
+type GroupService struct {
+	mu     sync.RWMutex
+	groups map[string]*imgrepo.Group
+}
+
+var _ imgrepo.GroupService = (*GroupService)(nil)
+
+// NewGroupService returns a GroupService with no groups.
+func NewGroupService() *GroupService {
+	return &GroupService{groups: make(map[string]*imgrepo.Group)}
+}
+
+// copyGroup returns a copy of the group, which the caller may keep.
+func copyGroup(g *imgrepo.Group) *imgrepo.Group {
+	return &imgrepo.Group{
+		Name:    g.Name,
+		Admins:  append([]string(nil), g.Admins...),
+		Members: append([]string(nil), g.Members...),
+	}
+}
+
+// find returns the group, if it has not been deleted, gs.mu must be held.
+func (gs *GroupService) find(name string) (*imgrepo.Group, error) {
+	g, ok := gs.groups[name]
+	if !ok || len(g.Admins) == 0 {
+		return nil, fmt.Errorf("group %s: %w", name, imgrepo.ErrNotFound)
+	}
+	return g, nil
+}
+
+func (gs *GroupService) Create(requester, name string) (*imgrepo.Group, error) {
+	if name == "" {
+		return nil, fmt.Errorf("group without name: %w", imgrepo.ErrInvalidArgument)
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if _, ok := gs.groups[name]; ok {
+		return nil, fmt.Errorf("group %s: %w", name, imgrepo.ErrAlreadyExists)
+	}
+
+	g := &imgrepo.Group{Name: name, Admins: []string{requester}, Members: []string{requester}}
+	gs.groups[name] = g
+
+	return copyGroup(g), nil
+}
+
+// Find does not tell others whether the group exists.
+func (gs *GroupService) Find(requester, name string) (*imgrepo.Group, error) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	g, err := gs.find(name)
+	if err != nil || !g.IsMember(requester) {
+		return nil, fmt.Errorf("group %s: %w", name, imgrepo.ErrNotFound)
+	}
+
+	return copyGroup(g), nil
+}
+
+func (gs *GroupService) Exists(name string) (bool, error) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	_, err := gs.find(name)
+	return err == nil, nil
+}
+
+func (gs *GroupService) Groups(user string) ([]string, error) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	var res []string
+	for name, g := range gs.groups {
+		if g.IsMember(user) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+
+	return res, nil
+}
+
+func (gs *GroupService) AddMembers(requester, name string, users ...string) (*imgrepo.Group, error) {
+	return gs.change(requester, name, users, false, func(g *imgrepo.Group) {
+		g.Members = union(g.Members, users)
+	})
+}
+
+func (gs *GroupService) RemoveMembers(requester, name string, users ...string) (*imgrepo.Group, error) {
+	leaving := len(users) == 1 && users[0] == requester
+	return gs.change(requester, name, users, leaving, func(g *imgrepo.Group) {
+		g.Members = difference(g.Members, users)
+		g.Admins = difference(g.Admins, users)
+	})
+}
+
+func (gs *GroupService) AddAdmins(requester, name string, users ...string) (*imgrepo.Group, error) {
+	return gs.change(requester, name, users, false, func(g *imgrepo.Group) {
+		g.Members = union(g.Members, users)
+		g.Admins = union(g.Admins, users)
+	})
+}
+
+func (gs *GroupService) RemoveAdmins(requester, name string, users ...string) (*imgrepo.Group, error) {
+	return gs.change(requester, name, users, false, func(g *imgrepo.Group) {
+		g.Admins = difference(g.Admins, users)
+	})
+}
+
+// change applies fn to a copy of the group, if the requester administers
+// the group or is a member allowed to make the change, and keeps the copy
+// if it still has an admin.
+func (gs *GroupService) change(requester, name string, users []string, allowMember bool, fn func(g *imgrepo.Group)) (*imgrepo.Group, error) {
+	if len(users) == 0 {
+		return nil, fmt.Errorf("%q: %w", "no users given", imgrepo.ErrInvalidArgument)
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	g, err := gs.find(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !g.IsAdmin(requester) && !(allowMember && g.IsMember(requester)) {
+		return nil, fmt.Errorf("unable to change group %s: %w", name, imgrepo.ErrPermissionDenied)
+	}
+
+	res := copyGroup(g)
+	fn(res)
+	if len(res.Admins) == 0 {
+		return nil, fmt.Errorf("group %s would have no admins: %w", name, imgrepo.ErrInvalidArgument)
+	}
+	gs.groups[name] = res
+
+	return copyGroup(res), nil
+}
+
+func (gs *GroupService) Delete(requester, name string) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	g, err := gs.find(name)
+	if err != nil {
+		return err
+	}
+
+	if !g.IsAdmin(requester) {
+		return fmt.Errorf("unable to delete group %s: %w", name, imgrepo.ErrPermissionDenied)
+	}
+	gs.groups[name] = &imgrepo.Group{Name: name}
+
+	return nil
+}
+
+// union returns the users in a, followed by the users in b but not in a.
+func union(a, b []string) []string {
+	res := append([]string(nil), a...)
+	for _, user := range b {
+		if !containsUser(res, user) {
+			res = append(res, user)
+		}
+	}
+	return res
+}
+
+// difference returns the users in a but not in b.
+func difference(a, b []string) []string {
+	var res []string
+	for _, user := range a {
+		if !containsUser(b, user) {
+			res = append(res, user)
+		}
+	}
+	return res
+}
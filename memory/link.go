@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/algao1/imgrepo"
+)
+
+// LinkRegistry keeps share links in memory.
+type LinkRegistry struct {
+	mu    sync.Mutex
+	links map[string]imgrepo.ShareLink
+	now   func() time.Time
+}
+
+var _ imgrepo.LinkRegistry = (*LinkRegistry)(nil)
+
+// NewLinkRegistry returns a LinkRegistry with no links.
+func NewLinkRegistry() *LinkRegistry {
+	return &LinkRegistry{
+		links: make(map[string]imgrepo.ShareLink),
+		now:   time.Now,
+	}
+}
+
+func (lr *LinkRegistry) Create(link *imgrepo.ShareLink) error {
+	if link.Id == "" {
+		return fmt.Errorf("link without id: %w", imgrepo.ErrInvalidArgument)
+	}
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if _, ok := lr.links[link.Id]; ok {
+		return fmt.Errorf("link %s: %w", link.Id, imgrepo.ErrAlreadyExists)
+	}
+
+	link.Created = lr.now()
+	stored := *link
+	stored.Token = ""
+	lr.links[link.Id] = stored
+
+	return nil
+}
+
+func (lr *LinkRegistry) Find(id string) (*imgrepo.ShareLink, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	link, ok := lr.links[id]
+	if !ok {
+		return nil, fmt.Errorf("link %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	return &link, nil
+}
+
+func (lr *LinkRegistry) List(requester string) ([]*imgrepo.ShareLink, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	var res []*imgrepo.ShareLink
+	for _, link := range lr.links {
+		if link.Owner == requester {
+			link := link
+			res = append(res, &link)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].Created.Equal(res[j].Created) {
+			return res[i].Created.After(res[j].Created)
+		}
+		return res[i].Id > res[j].Id
+	})
+
+	return res, nil
+}
+
+func (lr *LinkRegistry) Redeem(id string) (*imgrepo.ShareLink, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	link, ok := lr.links[id]
+	if !ok {
+		return nil, fmt.Errorf("link %s: %w", id, imgrepo.ErrNotFound)
+	}
+	if link.Expired(lr.now()) {
+		return nil, fmt.Errorf("link %s: %w", id, imgrepo.ErrExpired)
+	}
+
+	link.Downloads++
+	lr.links[id] = link
+
+	return &link, nil
+}
+
+func (lr *LinkRegistry) Revoke(requester, id string) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	link, ok := lr.links[id]
+	if !ok {
+		return fmt.Errorf("link %s: %w", id, imgrepo.ErrNotFound)
+	}
+	if link.Owner != requester {
+		return fmt.Errorf("unable to revoke link %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+	delete(lr.links, id)
+
+	return nil
+}
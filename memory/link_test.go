@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"github.com/google/go-cmp/cmp"
+)
+
+// tmpLinkRegistry returns a LinkRegistry with the unlimited link "a" and the
+// single-use link "b" of "alice", and the link "c" of "bob" expiring in an
+// hour. The clock of the registry is returned to move time along.
+func tmpLinkRegistry(t *testing.T) (*LinkRegistry, *time.Time) {
+	clock := time.Date(2021, 5, 17, 10, 30, 0, 0, time.UTC)
+	lr := NewLinkRegistry()
+	lr.now = func() time.Time { return clock }
+
+	links := []*imgrepo.ShareLink{
+		{Id: "a", ImageId: "img1", Owner: "alice"},
+		{Id: "b", ImageId: "img2", Owner: "alice", MaxDownloads: 1},
+		{Id: "c", ImageId: "img3", Owner: "bob", Expires: clock.Add(time.Hour)},
+	}
+	for _, link := range links {
+		if err := lr.Create(link); err != nil {
+			t.Fatal(err)
+		}
+		clock = clock.Add(time.Minute)
+	}
+	return lr, &clock
+}
+
+func TestLinkCreate(t *testing.T) {
+	tests := map[string]struct {
+		id      string
+		wantErr error
+	}{
+		"new":   {id: "d"},
+		"taken": {id: "a", wantErr: imgrepo.ErrAlreadyExists},
+		"empty": {id: "", wantErr: imgrepo.ErrInvalidArgument},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			lr, clock := tmpLinkRegistry(t)
+
+			link := &imgrepo.ShareLink{Id: tc.id, ImageId: "img1", Owner: "alice", Token: "token"}
+			err := lr.Create(link)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Create() = %v, want %v", err, tc.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			got, err := lr.Find(tc.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := &imgrepo.ShareLink{Id: tc.id, ImageId: "img1", Owner: "alice", Created: *clock}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Find() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLinkList(t *testing.T) {
+	tests := map[string]struct {
+		requester string
+		want      []string
+	}{
+		"newest first": {requester: "alice", want: []string{"b", "a"}},
+		"other owner":  {requester: "bob", want: []string{"c"}},
+		"no links":     {requester: "carol"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			lr, _ := tmpLinkRegistry(t)
+
+			links, err := lr.List(tc.requester)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, link := range links {
+				got = append(got, link.Id)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("List() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLinkRedeem(t *testing.T) {
+	tests := map[string]struct {
+		id            string
+		redeems       int
+		after         time.Duration
+		wantDownloads int
+		wantErr       error
+	}{
+		"unlimited":    {id: "a", redeems: 3, wantDownloads: 3},
+		"single use":   {id: "b", redeems: 1, wantDownloads: 1},
+		"used up":      {id: "b", redeems: 2, wantErr: imgrepo.ErrExpired},
+		"not expired":  {id: "c", redeems: 1, after: 30 * time.Minute, wantDownloads: 1},
+		"expired":      {id: "c", redeems: 1, after: 2 * time.Hour, wantErr: imgrepo.ErrExpired},
+		"missing link": {id: "d", redeems: 1, wantErr: imgrepo.ErrNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			lr, clock := tmpLinkRegistry(t)
+			*clock = clock.Add(tc.after)
+
+			// Only the last redeem may fail.
+			var link *imgrepo.ShareLink
+			var err error
+			for i := 0; i < tc.redeems; i++ {
+				if link, err = lr.Redeem(tc.id); err != nil && i < tc.redeems-1 {
+					t.Fatal(err)
+				}
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Redeem() = _, %v, want %v", err, tc.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			if link.Downloads != tc.wantDownloads {
+				t.Errorf("Redeem() downloads = %d, want %d", link.Downloads, tc.wantDownloads)
+			}
+		})
+	}
+}
+
+func TestLinkRevoke(t *testing.T) {
+	tests := map[string]struct {
+		requester string
+		id        string
+		wantErr   error
+	}{
+		"owner":        {requester: "alice", id: "a"},
+		"not owner":    {requester: "bob", id: "a", wantErr: imgrepo.ErrPermissionDenied},
+		"missing link": {requester: "alice", id: "d", wantErr: imgrepo.ErrNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			lr, _ := tmpLinkRegistry(t)
+
+			err := lr.Revoke(tc.requester, tc.id)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Revoke() = %v, want %v", err, tc.wantErr)
+			}
+
+			_, err = lr.Find(tc.id)
+			if revoked := errors.Is(err, imgrepo.ErrNotFound); revoked != (tc.wantErr != imgrepo.ErrPermissionDenied) {
+				t.Errorf("Find() after Revoke() = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,607 @@
+package memory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/algao1/imgrepo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImageRegistry keeps image entries in memory. Ids are generated as
+// ObjectIDs so that they sort, and carry timestamps, like mongo.ImageRegistry.
+//
+// Blobs are stored under the digest of their content, and shared by every
+// entry with the same digest. They are reference counted, and deleted along
+// with their renditions and the last entry referencing them.
+type ImageRegistry struct {
+	mu      sync.RWMutex
+	images  map[string]imgrepo.Image
+	blobs   map[string]*blob
+	storage imgrepo.ImageStorage
+	groups  imgrepo.GroupService
+
+	// blobMu guards blobLocks, which serialize the changes to each blob.
+	blobMu    sync.Mutex
+	blobLocks map[string]*blobLock
+}
+
+// blob is the state of a stored blob.
+type blob struct {
+	refs       int
+	renditions []int
+}
+
+// blobLock serializes the changes to a blob, and counts the callers holding
+// or waiting on it.
+type blobLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+var _ imgrepo.ImageRegistry = (*ImageRegistry)(nil)
+
+// NewImageRegistry returns an empty ImageRegistry backed by store, whose
+// images can be shared with the groups of groups.
+func NewImageRegistry(store imgrepo.ImageStorage, groups imgrepo.GroupService) *ImageRegistry {
+	return &ImageRegistry{
+		images:    make(map[string]imgrepo.Image),
+		blobs:     make(map[string]*blob),
+		storage:   store,
+		groups:    groups,
+		blobLocks: make(map[string]*blobLock),
+	}
+}
+
+// lockBlob serializes the changes to the blob with the given digest, and
+// returns the function releasing it. The lock is dropped once nothing holds
+// or waits on it, so that locks do not pile up with every blob ever changed.
+func (ir *ImageRegistry) lockBlob(digest string) func() {
+	ir.blobMu.Lock()
+	l, ok := ir.blobLocks[digest]
+	if !ok {
+		l = new(blobLock)
+		ir.blobLocks[digest] = l
+	}
+	l.refs++
+	ir.blobMu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+
+		ir.blobMu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(ir.blobLocks, digest)
+		}
+		ir.blobMu.Unlock()
+	}
+}
+
+// lockBlobs is like lockBlob for several blobs, which are locked in order so
+// that concurrent callers cannot deadlock.
+func (ir *ImageRegistry) lockBlobs(digests ...string) func() {
+	sort.Strings(digests)
+
+	var unlocks []func()
+	for i, digest := range digests {
+		if i > 0 && digest == digests[i-1] {
+			continue
+		}
+		unlocks = append(unlocks, ir.lockBlob(digest))
+	}
+
+	return func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+}
+
+// Upload adds an entry for img, whose Digest must be the SHA-256 of r.
+// The blob is only streamed from r if no other entry references it, and
+// only the renditions the blob does not have yet are stored.
+func (ir *ImageRegistry) Upload(img *imgrepo.Image, r io.Reader, renditions ...imgrepo.Rendition) error {
+	if img.Digest == "" {
+		return fmt.Errorf("image without digest: %w", imgrepo.ErrInvalidArgument)
+	}
+	img.Id = primitive.NewObjectID().Hex()
+
+	unlock := ir.lockBlob(img.Digest)
+	defer unlock()
+
+	if err := ir.acquire(img, r, renditions); err != nil {
+		return err
+	}
+
+	ir.mu.Lock()
+	ir.images[img.Id] = *img
+	ir.mu.Unlock()
+
+	return nil
+}
+
+// acquire adds a reference to the blob of img, streaming it from r if it is
+// not stored yet, along with the renditions it does not have yet, and sets
+// img.Renditions. lockBlob must be held.
+func (ir *ImageRegistry) acquire(img *imgrepo.Image, r io.Reader, renditions []imgrepo.Rendition) error {
+	ir.mu.RLock()
+	b, ok := ir.blobs[img.Digest]
+	ir.mu.RUnlock()
+
+	var sizes []int
+	if ok {
+		sizes = append(sizes, b.renditions...)
+	} else {
+		err := ir.storage.Upload(img.Digest, r)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to upload image to storage", err)
+		}
+	}
+
+	added, err := uploadRenditions(ir.storage, img.Digest, sizes, renditions)
+	if err != nil {
+		if !ok {
+			ir.storage.Delete(img.Digest)
+		}
+		return err
+	}
+	sizes = append(sizes, added...)
+	sort.Ints(sizes)
+	img.Renditions = sizes
+
+	ir.mu.Lock()
+	if !ok {
+		b = new(blob)
+		ir.blobs[img.Digest] = b
+	}
+	b.refs++
+	b.renditions = sizes
+	ir.mu.Unlock()
+
+	return nil
+}
+
+// release drops a reference to the blob with the given digest, and deletes
+// it along with its renditions if it was the last one. The renditions are
+// deleted first, since they are useless without the blob. lockBlob must be
+// held.
+func (ir *ImageRegistry) release(digest string) error {
+	ir.mu.RLock()
+	b := ir.blobs[digest]
+	ir.mu.RUnlock()
+
+	if b.refs == 1 {
+		for _, size := range b.renditions {
+			err := deleteBlob(ir.storage, imgrepo.RenditionId(digest, size))
+			if err != nil {
+				return fmt.Errorf("%q: %w", "unable to delete rendition from storage", err)
+			}
+		}
+
+		err := deleteBlob(ir.storage, digest)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to delete image from storage", err)
+		}
+	}
+
+	ir.mu.Lock()
+	if b.refs--; b.refs == 0 {
+		delete(ir.blobs, digest)
+	}
+	ir.mu.Unlock()
+
+	return nil
+}
+
+// uploadRenditions stores the renditions of the blob whose sizes are not in
+// sizes, and returns the sizes stored. If one fails, the ones already stored
+// are deleted again.
+func uploadRenditions(is imgrepo.ImageStorage, blobId string, sizes []int, renditions []imgrepo.Rendition) ([]int, error) {
+	var added []int
+	for _, rd := range renditions {
+		if containsSize(sizes, rd.Size) || containsSize(added, rd.Size) {
+			continue
+		}
+
+		err := is.Upload(imgrepo.RenditionId(blobId, rd.Size), bytes.NewReader(rd.Data))
+		if err != nil {
+			for _, size := range added {
+				is.Delete(imgrepo.RenditionId(blobId, size))
+			}
+			return nil, fmt.Errorf("%q: %w", "unable to upload rendition to storage", err)
+		}
+		added = append(added, rd.Size)
+	}
+
+	return added, nil
+}
+
+func containsSize(sizes []int, size int) bool {
+	for _, s := range sizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+func (ir *ImageRegistry) Download(requester, id string) (*imgrepo.Image, io.ReadCloser, error) {
+	return ir.download(requester, id, 0)
+}
+
+func (ir *ImageRegistry) Rendition(requester, id string, size int) (*imgrepo.Image, io.ReadCloser, error) {
+	if size <= 0 {
+		return nil, nil, fmt.Errorf("rendition size %d: %w", size, imgrepo.ErrInvalidArgument)
+	}
+	return ir.download(requester, id, size)
+}
+
+// download streams the image, or its rendition of the given size if the
+// size is not 0.
+func (ir *ImageRegistry) download(requester, id string, size int) (*imgrepo.Image, io.ReadCloser, error) {
+	ir.mu.RLock()
+	img, ok := ir.images[id]
+	ir.mu.RUnlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	ok, err := viewable(ir.groups, &img, requester)
+	if err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, fmt.Errorf("unable to access file %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	blobId := img.Digest
+	if size != 0 {
+		if !containsSize(img.Renditions, size) {
+			return nil, nil, fmt.Errorf("rendition %d of file %s: %w", size, id, imgrepo.ErrNotFound)
+		}
+		blobId = imgrepo.RenditionId(img.Digest, size)
+	}
+
+	rc, err := ir.storage.Download(blobId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%q: %w", "unable to download file", err)
+	}
+
+	return &img, rc, nil
+}
+
+// List returns up to size images viewable by the requester and selected by
+// the filter with ids less than lastId, newest first. A size of zero means
+// no limit.
+func (ir *ImageRegistry) List(size int, requester, lastId string, filter imgrepo.ListFilter) ([]*imgrepo.Image, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("negative page size %d: %w", size, imgrepo.ErrInvalidArgument)
+	}
+
+	groups, err := ir.groups.Groups(requester)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find groups", err)
+	}
+
+	ir.mu.RLock()
+	defer ir.mu.RUnlock()
+
+	ids := make([]string, 0, len(ir.images))
+	for id, img := range ir.images {
+		if !img.Viewable(requester, groups) {
+			continue
+		}
+		if filter.Shared && (img.Owner == requester || !img.Includes(requester, groups)) {
+			continue
+		}
+		if len(lastId) > 0 && id >= lastId {
+			continue
+		}
+		if !filter.Matches(img.Tags) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	if size > 0 && len(ids) > size {
+		ids = ids[:size]
+	}
+
+	var res []*imgrepo.Image
+	for _, id := range ids {
+		img := ir.images[id]
+		res = append(res, &img)
+	}
+
+	return res, nil
+}
+
+func (ir *ImageRegistry) Find(requester string, ids ...string) ([]*imgrepo.Image, error) {
+	groups, err := ir.groups.Groups(requester)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find groups", err)
+	}
+
+	ir.mu.RLock()
+	defer ir.mu.RUnlock()
+
+	var res []*imgrepo.Image
+	for _, id := range ids {
+		img, ok := ir.images[id]
+		if !ok || !img.Viewable(requester, groups) {
+			continue
+		}
+		res = append(res, &img)
+	}
+
+	return res, nil
+}
+
+func (ir *ImageRegistry) Stat(id string) (*imgrepo.Image, error) {
+	ir.mu.RLock()
+	defer ir.mu.RUnlock()
+
+	img, ok := ir.images[id]
+	if !ok {
+		return nil, fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	return &img, nil
+}
+
+// Walk calls fn with a snapshot of the registry, so fn may use the registry.
+func (ir *ImageRegistry) Walk(fn func(img *imgrepo.Image) error) error {
+	ir.mu.RLock()
+	imgs := make([]imgrepo.Image, 0, len(ir.images))
+	for _, img := range ir.images {
+		imgs = append(imgs, img)
+	}
+	ir.mu.RUnlock()
+
+	for i := range imgs {
+		if err := fn(&imgs[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update changes the entry under the registry lock, so that concurrent
+// updates of different fields are not lost.
+func (ir *ImageRegistry) Update(requester string, img *imgrepo.Image, fields ...imgrepo.Field) (*imgrepo.Image, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%q: %w", "no fields to update", imgrepo.ErrInvalidArgument)
+	}
+
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	res, ok := ir.images[img.Id]
+	if !ok {
+		return nil, fmt.Errorf("file %s: %w", img.Id, imgrepo.ErrNotFound)
+	}
+
+	if res.Owner != requester {
+		return nil, fmt.Errorf("unable to update file %s: %w", img.Id, imgrepo.ErrPermissionDenied)
+	}
+
+	for _, f := range fields {
+		switch f {
+		case imgrepo.NameField:
+			res.Name = img.Name
+		case imgrepo.AccessField:
+			res.Access = img.Access
+		case imgrepo.TakenField:
+			res.Metadata.Taken = img.Metadata.Taken
+		case imgrepo.LocationField:
+			res.Metadata.Location = nil
+			if loc := img.Metadata.Location; loc != nil {
+				res.Metadata.Location = &imgrepo.Location{Latitude: loc.Latitude, Longitude: loc.Longitude}
+			}
+		case imgrepo.TagsField:
+			res.Tags = append([]string(nil), img.Tags...)
+		default:
+			return nil, fmt.Errorf("field %q cannot be updated: %w", f, imgrepo.ErrInvalidArgument)
+		}
+	}
+	ir.images[img.Id] = res
+
+	return &res, nil
+}
+
+// Replace acquires the new blob before the entry is swapped, and releases the
+// old one after, restoring the entry if it cannot be, so that a failure never
+// leaves the entry without a blob.
+func (ir *ImageRegistry) Replace(requester, digest string, img *imgrepo.Image, r io.Reader, renditions ...imgrepo.Rendition) (*imgrepo.Image, error) {
+	if img.Digest == "" {
+		return nil, fmt.Errorf("image without digest: %w", imgrepo.ErrInvalidArgument)
+	}
+
+	ir.mu.RLock()
+	cur, ok := ir.images[img.Id]
+	ir.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("file %s: %w", img.Id, imgrepo.ErrNotFound)
+	}
+
+	if cur.Owner != requester {
+		return nil, fmt.Errorf("unable to replace file %s: %w", img.Id, imgrepo.ErrPermissionDenied)
+	}
+
+	unlock := ir.lockBlobs(digest, img.Digest)
+	defer unlock()
+
+	// The entry may have been deleted, or replaced, while waiting for the
+	// blobs.
+	ir.mu.RLock()
+	cur, ok = ir.images[img.Id]
+	ir.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("file %s: %w", img.Id, imgrepo.ErrNotFound)
+	}
+	if cur.Digest != digest {
+		return nil, fmt.Errorf("file %s changed since digest %s: %w", img.Id, digest, imgrepo.ErrConflict)
+	}
+
+	if err := ir.acquire(img, r, renditions); err != nil {
+		return nil, err
+	}
+
+	res := ir.swap(img.Id, img)
+	if err := ir.release(digest); err != nil {
+		ir.swap(img.Id, &cur)
+		if rerr := ir.release(img.Digest); rerr != nil {
+			return nil, fmt.Errorf("unable to release blob %s: %v: %w", img.Digest, rerr, err)
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// swap sets the blob, access and metadata of the entry to those of img, under
+// the registry lock, and returns the updated entry.
+func (ir *ImageRegistry) swap(id string, img *imgrepo.Image) *imgrepo.Image {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	res := ir.images[id]
+	res.Digest = img.Digest
+	res.Size = img.Size
+	res.Renditions = img.Renditions
+	res.Access = img.Access
+	res.Metadata = img.Metadata
+	ir.images[id] = res
+
+	return &res
+}
+
+// Share adds the users and groups of g to the grant of the entry, under the
+// registry lock.
+func (ir *ImageRegistry) Share(requester, id string, g imgrepo.Grant) (*imgrepo.Image, error) {
+	return ir.share(requester, id, g, func(res *imgrepo.Grant) {
+		res.Readers = union(res.Readers, g.Readers)
+		res.Groups = union(res.Groups, g.Groups)
+	})
+}
+
+// Unshare removes the users and groups of g from the grant of the entry,
+// under the registry lock.
+func (ir *ImageRegistry) Unshare(requester, id string, g imgrepo.Grant) (*imgrepo.Image, error) {
+	return ir.share(requester, id, g, func(res *imgrepo.Grant) {
+		res.Readers = difference(res.Readers, g.Readers)
+		res.Groups = difference(res.Groups, g.Groups)
+	})
+}
+
+// share applies fn to the grant of the entry, if the entry is owned by the
+// requester. The lists of the grant are replaced rather than changed, since
+// earlier results share them.
+func (ir *ImageRegistry) share(requester, id string, g imgrepo.Grant, fn func(res *imgrepo.Grant)) (*imgrepo.Image, error) {
+	if g.Empty() {
+		return nil, fmt.Errorf("%q: %w", "no users or groups to share with", imgrepo.ErrInvalidArgument)
+	}
+
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	res, ok := ir.images[id]
+	if !ok {
+		return nil, fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	if res.Owner != requester {
+		return nil, fmt.Errorf("unable to share file %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	fn(&res.Grant)
+	ir.images[id] = res
+
+	return &res, nil
+}
+
+// viewable reports whether the requester may view the image, looking up the
+// groups of the requester only if the image is shared with groups.
+func viewable(gs imgrepo.GroupService, img *imgrepo.Image, requester string) (bool, error) {
+	if img.Viewable(requester, nil) || len(img.Groups) == 0 {
+		return img.Viewable(requester, nil), nil
+	}
+
+	groups, err := gs.Groups(requester)
+	if err != nil {
+		return false, fmt.Errorf("%q: %w", "unable to find groups", err)
+	}
+
+	return img.Viewable(requester, groups), nil
+}
+
+func containsUser(users []string, user string) bool {
+	for _, u := range users {
+		if u == user {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteBlob deletes the stored object with the given id. Objects already
+// gone were deleted by an earlier attempt that failed partway, so they are
+// not an error, and the deletion can be retried.
+func deleteBlob(is imgrepo.ImageStorage, id string) error {
+	if err := is.Delete(id); err != nil && !errors.Is(err, imgrepo.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// Delete removes the entry, and the blob if no other entry references it.
+func (ir *ImageRegistry) Delete(requester, id string) error {
+	ir.mu.RLock()
+	img, ok := ir.images[id]
+	ir.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	if img.Owner != requester {
+		return fmt.Errorf("unable to delete file %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	unlock := ir.lockBlob(img.Digest)
+	defer unlock()
+
+	// The entry may have been deleted, or replaced, while waiting for the
+	// blob.
+	ir.mu.RLock()
+	cur, ok := ir.images[id]
+	ir.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	}
+	if cur.Digest != img.Digest {
+		return fmt.Errorf("file %s changed while deleting: %w", id, imgrepo.ErrConflict)
+	}
+
+	// The blob lock is held throughout, so the entry is only removed once
+	// the blob is gone.
+	if err := ir.release(img.Digest); err != nil {
+		return err
+	}
+
+	ir.mu.Lock()
+	delete(ir.images, id)
+	ir.mu.Unlock()
+
+	return nil
+}
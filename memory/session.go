@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"github.com/google/uuid"
+)
+
+// _SessionTTL matches the expiry used by redis.SessionService.
+const _SessionTTL = 30 * time.Minute
+
+type session struct {
+	username string
+	expiry   time.Time
+}
+
+// SessionService keeps sessions in memory. Expired sessions are removed
+// when they are next looked up.
+type SessionService struct {
+	mu       sync.Mutex
+	sessions map[string]session
+	now      func() time.Time
+}
+
+var _ imgrepo.SessionService = (*SessionService)(nil)
+
+// NewSessionService returns a SessionService with no sessions.
+func NewSessionService() *SessionService {
+	return &SessionService{
+		sessions: make(map[string]session),
+		now:      time.Now,
+	}
+}
+
+func (s *SessionService) NewSession(username string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uuid := uuid.NewString()
+	s.sessions[uuid] = session{username: username, expiry: s.now().Add(_SessionTTL)}
+
+	return uuid, nil
+}
+
+func (s *SessionService) IsSession(uuid string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[uuid]
+	if ok && !s.now().Before(sess.expiry) {
+		delete(s.sessions, uuid)
+		ok = false
+	}
+	if !ok {
+		return "", fmt.Errorf("no session found: %w", imgrepo.ErrUnauthenticated)
+	}
+
+	return sess.username, nil
+}
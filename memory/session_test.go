@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionExpiry(t *testing.T) {
+	now := time.Now()
+
+	ss := NewSessionService()
+	ss.now = func() time.Time { return now }
+
+	uuid, err := ss.NewSession("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := ss.IsSession(uuid)
+	if err != nil {
+		t.Fatalf("IsSession() = _, %v, want nil", err)
+	} else if user != "test" {
+		t.Fatalf("IsSession() = %q, want %q", user, "test")
+	}
+
+	if _, err := ss.IsSession("unknown"); err == nil {
+		t.Fatal("expected error for unknown session")
+	}
+
+	now = now.Add(_SessionTTL)
+	if _, err := ss.IsSession(uuid); err == nil {
+		t.Fatal("expected error for expired session")
+	}
+}
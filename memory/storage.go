@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/algao1/imgrepo"
+)
+
+// ImageStorage stores raw images in memory.
+type ImageStorage struct {
+	mu    sync.RWMutex
+	store map[string][]byte
+}
+
+var _ imgrepo.ImageStorage = (*ImageStorage)(nil)
+
+// NewImageStorage returns an empty ImageStorage.
+func NewImageStorage() *ImageStorage {
+	return &ImageStorage{store: make(map[string][]byte)}
+}
+
+func (is *ImageStorage) Upload(id string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to upload file", err)
+	}
+
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	is.store[id] = data
+
+	return nil
+}
+
+func (is *ImageStorage) Download(id string) (io.ReadCloser, error) {
+	is.mu.RLock()
+	defer is.mu.RUnlock()
+
+	data, ok := is.store[id]
+	if !ok {
+		return nil, fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	// Stored slices are never modified, so they can be shared with readers.
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (is *ImageStorage) Delete(id string) error {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	if _, ok := is.store[id]; !ok {
+		return fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	}
+	delete(is.store, id)
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+)
+
+func randomBytes(len int) []byte {
+	token := make([]byte, len)
+	rand.Read(token)
+	return token
+}
+
+// readAll downloads the image with the given id from is.
+func readAll(is imgrepo.ImageStorage, id string) ([]byte, error) {
+	rc, err := is.Download(id)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+func TestStorageUploadDownload(t *testing.T) {
+	data := randomBytes(1000)
+
+	is := NewImageStorage()
+	if err := is.Upload("__test", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readAll(is, "__test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("downloaded bytes differ from uploaded bytes")
+	}
+
+	if err := is.Delete("__test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := is.Download("__test"); err == nil {
+		t.Fatal("expected error downloading deleted file")
+	}
+}
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"github.com/google/uuid"
+)
+
+type upload struct {
+	img     imgrepo.Image
+	data    []byte
+	written time.Time
+	claimed bool
+}
+
+// UploadStore keeps resumable uploads in memory.
+type UploadStore struct {
+	mu      sync.Mutex
+	uploads map[string]*upload
+	now     func() time.Time
+}
+
+var _ imgrepo.UploadStore = (*UploadStore)(nil)
+
+// NewUploadStore returns an empty UploadStore.
+func NewUploadStore() *UploadStore {
+	return &UploadStore{uploads: make(map[string]*upload), now: time.Now}
+}
+
+func (us *UploadStore) Begin(img *imgrepo.Image) (string, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	id := uuid.NewString()
+	us.uploads[id] = &upload{img: *img, written: us.now()}
+
+	return id, nil
+}
+
+func (us *UploadStore) Write(id string, offset int64, r io.Reader) (int64, error) {
+	// Whatever was read before an error is still committed.
+	data, rerr := io.ReadAll(r)
+
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	up, ok := us.uploads[id]
+	if !ok {
+		return 0, fmt.Errorf("upload %s: %w", id, imgrepo.ErrNotFound)
+	}
+	if up.claimed {
+		return int64(len(up.data)), fmt.Errorf("upload %s is being committed: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	size := int64(len(up.data))
+	if offset < 0 {
+		return size, fmt.Errorf("negative offset %d: %w", offset, imgrepo.ErrInvalidArgument)
+	} else if offset > size {
+		return size, fmt.Errorf("offset %d is past committed offset %d: %w", offset, size, imgrepo.ErrInvalidArgument)
+	}
+
+	// Skip the data that has already been committed.
+	if skip := size - offset; skip < int64(len(data)) {
+		up.data = append(up.data, data[skip:]...)
+	}
+	up.written = us.now()
+
+	if rerr != nil {
+		return int64(len(up.data)), fmt.Errorf("%q: %w", "unable to read upload", rerr)
+	}
+
+	return int64(len(up.data)), nil
+}
+
+func (us *UploadStore) Query(id string) (*imgrepo.Image, int64, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	up, ok := us.uploads[id]
+	if !ok {
+		return nil, 0, fmt.Errorf("upload %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	img := up.img
+	return &img, int64(len(up.data)), nil
+}
+
+func (us *UploadStore) Open(id string) (io.ReadCloser, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	up, ok := us.uploads[id]
+	if !ok {
+		return nil, fmt.Errorf("upload %s: %w", id, imgrepo.ErrNotFound)
+	}
+
+	// Writes only ever append, so the current slice can be shared.
+	return io.NopCloser(bytes.NewReader(up.data)), nil
+}
+
+func (us *UploadStore) Remove(id string) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	if _, ok := us.uploads[id]; !ok {
+		return fmt.Errorf("upload %s: %w", id, imgrepo.ErrNotFound)
+	}
+	delete(us.uploads, id)
+
+	return nil
+}
+
+func (us *UploadStore) Claim(id string) (func(), error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	up, ok := us.uploads[id]
+	if !ok {
+		return nil, fmt.Errorf("upload %s: %w", id, imgrepo.ErrNotFound)
+	}
+	if up.claimed {
+		return nil, fmt.Errorf("upload %s is being committed: %w", id, imgrepo.ErrPermissionDenied)
+	}
+	up.claimed = true
+
+	return func() {
+		us.mu.Lock()
+		up.claimed = false
+		us.mu.Unlock()
+	}, nil
+}
+
+func (us *UploadStore) Sweep(before time.Time) (int, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	n := 0
+	for id, up := range us.uploads {
+		if !up.claimed && up.written.Before(before) {
+			delete(us.uploads, id)
+			n++
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/algao1/imgrepo"
+)
+
+func TestResumableUpload(t *testing.T) {
+	data := randomBytes(1000)
+	us := NewUploadStore()
+
+	id, err := us.Begin(&imgrepo.Image{Name: "test.png", Owner: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first write fails part way through.
+	r := io.MultiReader(bytes.NewReader(data[:400]), iotest.ErrReader(iotest.ErrTimeout))
+	off, err := us.Write(id, 0, r)
+	if err == nil {
+		t.Fatal("expected error from interrupted write")
+	}
+	if off != 400 {
+		t.Fatalf("expected committed offset 400, got %d", off)
+	}
+
+	// Writing past the committed offset leaves a gap.
+	if _, err := us.Write(id, 500, bytes.NewReader(data[500:])); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+
+	// A negative offset would skip data that was never committed.
+	if _, err := us.Write(id, -100, bytes.NewReader(data)); !errors.Is(err, imgrepo.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+
+	// Overlapping data is skipped.
+	off, err = us.Write(id, 300, bytes.NewReader(data[300:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if off != int64(len(data)) {
+		t.Fatalf("expected committed offset %d, got %d", len(data), off)
+	}
+
+	rc, err := us.Open(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("uploaded bytes differ from written bytes")
+	}
+
+	if err := us.Remove(id); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := us.Query(id); !errors.Is(err, imgrepo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClaimUpload(t *testing.T) {
+	us := NewUploadStore()
+
+	id, err := us.Begin(&imgrepo.Image{Name: "test.png", Owner: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := us.Claim(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A claimed upload can neither be written nor claimed again.
+	if _, err := us.Write(id, 0, bytes.NewReader(randomBytes(10))); !errors.Is(err, imgrepo.ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+	if _, err := us.Claim(id); !errors.Is(err, imgrepo.ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+
+	release()
+	if _, err := us.Write(id, 0, bytes.NewReader(randomBytes(10))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := us.Claim(id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSweepUploads(t *testing.T) {
+	start := time.Now()
+	now := start
+	us := NewUploadStore()
+	us.now = func() time.Time { return now }
+
+	old, err := us.Begin(&imgrepo.Image{Name: "old.png"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimed, err := us.Begin(&imgrepo.Image{Name: "claimed.png"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := us.Claim(claimed); err != nil {
+		t.Fatal(err)
+	}
+	written, err := us.Begin(&imgrepo.Image{Name: "written.png"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Writing keeps an upload from being swept.
+	now = start.Add(time.Hour)
+	if _, err := us.Write(written, 0, bytes.NewReader(randomBytes(10))); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := us.Sweep(start.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 upload swept, got %d", n)
+	}
+
+	if _, _, err := us.Query(old); !errors.Is(err, imgrepo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	for _, id := range []string{claimed, written} {
+		if _, _, err := us.Query(id); err != nil {
+			t.Fatalf("upload %s: %v", id, err)
+		}
+	}
+}
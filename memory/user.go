@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/algao1/imgrepo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// _BcryptCost matches the cost used by mongo.UserService.
+const _BcryptCost = 14
+
+// account is a registered user, with the hash of their password.
+type account struct {
+	hash     []byte
+	role     imgrepo.Role
+	disabled bool
+}
+
+// UserService keeps user accounts in memory.
+type UserService struct {
+	mu    sync.RWMutex
+	users map[string]*account
+}
+
+var _ imgrepo.UserService = (*UserService)(nil)
+
+// NewUserService returns a UserService with no accounts.
+func NewUserService() *UserService {
+	return &UserService{users: make(map[string]*account)}
+}
+
+func (us *UserService) Register(user, password string) error {
+	if user == "" || password == "" {
+		return fmt.Errorf("username and password must be non-empty: %w", imgrepo.ErrInvalidArgument)
+	}
+
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), _BcryptCost)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to encrypt password", err)
+	}
+
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	if _, ok := us.users[user]; ok {
+		return fmt.Errorf("username %s: %w", user, imgrepo.ErrAlreadyExists)
+	}
+	us.users[user] = &account{hash: bytes}
+
+	return nil
+}
+
+func (us *UserService) Login(user, password string) error {
+	us.mu.RLock()
+	acc, ok := us.users[user]
+	var hash []byte
+	var disabled bool
+	if ok {
+		hash, disabled = acc.hash, acc.disabled
+	}
+	us.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("incorrect username or password: %w", imgrepo.ErrUnauthenticated)
+	}
+
+	err := bcrypt.CompareHashAndPassword(hash, []byte(password))
+	if err != nil {
+		return fmt.Errorf("incorrect username or password: %w", imgrepo.ErrUnauthenticated)
+	}
+
+	// The password is checked first, so that only the owner of the account
+	// learns that it is disabled.
+	if disabled {
+		return fmt.Errorf("account %s is disabled: %w", user, imgrepo.ErrPermissionDenied)
+	}
+
+	return nil
+}
+
+func (us *UserService) Exists(user string) (bool, error) {
+	us.mu.RLock()
+	defer us.mu.RUnlock()
+
+	_, ok := us.users[user]
+	return ok, nil
+}
+
+func (us *UserService) Account(user string) (*imgrepo.Account, error) {
+	us.mu.RLock()
+	defer us.mu.RUnlock()
+
+	acc, ok := us.users[user]
+	if !ok {
+		return nil, fmt.Errorf("user %s: %w", user, imgrepo.ErrNotFound)
+	}
+
+	return &imgrepo.Account{Username: user, Role: acc.role, Disabled: acc.disabled}, nil
+}
+
+func (us *UserService) Accounts() ([]*imgrepo.Account, error) {
+	us.mu.RLock()
+	defer us.mu.RUnlock()
+
+	res := make([]*imgrepo.Account, 0, len(us.users))
+	for user, acc := range us.users {
+		res = append(res, &imgrepo.Account{Username: user, Role: acc.role, Disabled: acc.disabled})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Username < res[j].Username })
+
+	return res, nil
+}
+
+func (us *UserService) SetRole(user string, role imgrepo.Role) error {
+	if role < imgrepo.UserRole || role > imgrepo.AdminRole {
+		return fmt.Errorf("unknown role %d: %w", role, imgrepo.ErrInvalidArgument)
+	}
+
+	return us.update(user, func(acc *account) { acc.role = role })
+}
+
+func (us *UserService) SetDisabled(user string, disabled bool) error {
+	return us.update(user, func(acc *account) { acc.disabled = disabled })
+}
+
+// update calls fn with the account of the user, under the lock.
+func (us *UserService) update(user string, fn func(acc *account)) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	acc, ok := us.users[user]
+	if !ok {
+		return fmt.Errorf("user %s: %w", user, imgrepo.ErrNotFound)
+	}
+	fn(acc)
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegisterUser(t *testing.T) {
+	tests := map[string]struct {
+		username  string
+		password  string
+		expectErr bool
+	}{
+		"register new user":       {username: "nadmin", password: "password", expectErr: false},
+		"register duplicate user": {username: "admin", password: "password", expectErr: true},
+		"register empty password": {username: "eadmin", password: "", expectErr: true},
+	}
+
+	us := NewUserService()
+
+	// Setup existing user account.
+	us.Register("admin", "password")
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := us.Register(tc.username, tc.password)
+			if err != nil && !tc.expectErr {
+				t.Fatal(err)
+			} else if err == nil && tc.expectErr {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestLoginUser(t *testing.T) {
+	tests := map[string]struct {
+		username  string
+		password  string
+		expectErr bool
+	}{
+		"invalid username":        {username: "admin2", password: "password", expectErr: true},
+		"invalid password":        {username: "admin", password: "password2", expectErr: true},
+		"valid username/password": {username: "admin", password: "password", expectErr: false},
+	}
+
+	us := NewUserService()
+
+	// Setup existing user account.
+	us.Register("admin", "password")
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := us.Login(tc.username, tc.password)
+			if tc.expectErr && !errors.Is(err, imgrepo.ErrUnauthenticated) {
+				t.Fatalf("Login() = %v, want %v", err, imgrepo.ErrUnauthenticated)
+			} else if err != nil && !tc.expectErr {
+				t.Fatal(err)
+			} else if err == nil && tc.expectErr {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestUserExists(t *testing.T) {
+	tests := map[string]struct {
+		username string
+		want     bool
+	}{
+		"registered":   {username: "admin", want: true},
+		"unregistered": {username: "admin2", want: false},
+	}
+
+	us := NewUserService()
+
+	// Setup existing user account.
+	us.Register("admin", "password")
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := us.Exists(tc.username)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("Exists() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetAccount(t *testing.T) {
+	tests := map[string]struct {
+		username     string
+		role         imgrepo.Role
+		disabled     bool
+		want         *imgrepo.Account
+		wantErr      error
+		wantLoginErr error
+	}{
+		"promote": {
+			username: "admin",
+			role:     imgrepo.AdminRole,
+			want:     &imgrepo.Account{Username: "admin", Role: imgrepo.AdminRole},
+		},
+		"disable": {
+			username:     "admin",
+			disabled:     true,
+			want:         &imgrepo.Account{Username: "admin", Disabled: true},
+			wantLoginErr: imgrepo.ErrPermissionDenied,
+		},
+		"unknown role": {username: "admin", role: 42, wantErr: imgrepo.ErrInvalidArgument},
+		"missing user": {username: "admin2", wantErr: imgrepo.ErrNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			us := NewUserService()
+
+			// Setup existing user account.
+			us.Register("admin", "password")
+
+			err := us.SetRole(tc.username, tc.role)
+			if err == nil {
+				err = us.SetDisabled(tc.username, tc.disabled)
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("SetRole() and SetDisabled() = %v, want %v", err, tc.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			got, err := us.Account(tc.username)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Account() mismatch (-want +got):\n%s", diff)
+			}
+
+			if err := us.Login(tc.username, "password"); !errors.Is(err, tc.wantLoginErr) {
+				t.Errorf("Login() = %v, want %v", err, tc.wantLoginErr)
+			}
+		})
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	us := NewUserService()
+
+	// Setup existing user accounts.
+	us.Register("mod", "password")
+	us.Register("admin", "password")
+	if err := us.SetRole("mod", imgrepo.ModeratorRole); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := us.Accounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*imgrepo.Account{
+		{Username: "admin"},
+		{Username: "mod", Role: imgrepo.ModeratorRole},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Accounts() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := us.Account("missing"); !errors.Is(err, imgrepo.ErrNotFound) {
+		t.Errorf("Account() = _, %v, want %v", err, imgrepo.ErrNotFound)
+	}
+}
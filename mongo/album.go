@@ -0,0 +1,358 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AlbumRegistry keeps albums in a MongoDB collection, next to the collection
+// of an ImageRegistry, which holds their images. Albums only hold the ids of
+// their images, so deleting an image leaves its id behind until removed.
+type AlbumRegistry struct {
+	col    *mongo.Collection
+	images *mongo.Collection
+	groups imgrepo.GroupService
+}
+
+var _ imgrepo.AlbumRegistry = (*AlbumRegistry)(nil)
+
+// NewAlbumRegistry returns an AlbumRegistry with the MongoDB collection
+// configured, in the database of the ImageRegistry.
+func NewAlbumRegistry(ir *ImageRegistry, col string) *AlbumRegistry {
+	return &AlbumRegistry{
+		col:    ir.col.Database().Collection(col),
+		images: ir.col,
+		groups: ir.groups,
+	}
+}
+
+// find returns the album with the given id.
+func (ar *AlbumRegistry) find(ctx context.Context, id string) (*imgrepo.Album, error) {
+	var album imgrepo.Album
+	err := ar.col.FindOne(ctx, bson.M{"_id": id}).Decode(&album)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("album %s: %w", id, imgrepo.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find album", err)
+	}
+
+	return &album, nil
+}
+
+// update applies the update to the album, only if it is owned by the
+// requester. The owner is checked apart only to tell why nothing changed.
+func (ar *AlbumRegistry) update(ctx context.Context, requester, id string, update bson.M) error {
+	res, err := ar.col.UpdateOne(ctx, bson.M{"_id": id, "owner": requester}, update)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to update album", err)
+	}
+
+	if res.MatchedCount == 0 {
+		if _, err := ar.find(ctx, id); err != nil {
+			return err
+		}
+		return fmt.Errorf("unable to update album %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	return nil
+}
+
+// checkImages checks that the images with the given ids exist, and are
+// owned by the requester.
+func (ar *AlbumRegistry) checkImages(ctx context.Context, requester string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	cursor, err := ar.images.Find(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"owner": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "cursor not found", err)
+	}
+	defer cursor.Close(ctx)
+
+	owners := make(map[string]string)
+	for cursor.Next(ctx) {
+		var img imgrepo.Image
+		if err := cursor.Decode(&img); err != nil {
+			return fmt.Errorf("%q: %w", "unable to complete query", err)
+		}
+		owners[img.Id] = img.Owner
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("%q: %w", "unable to complete query", err)
+	}
+
+	for _, id := range ids {
+		owner, ok := owners[id]
+		if !ok {
+			return fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+		}
+		if owner != requester {
+			return fmt.Errorf("unable to add file %s: %w", id, imgrepo.ErrPermissionDenied)
+		}
+	}
+
+	return nil
+}
+
+// Create adds the album, which must have a name, and may only hold images
+// owned by album.Owner.
+func (ar *AlbumRegistry) Create(album *imgrepo.Album) error {
+	if album.Name == "" {
+		return fmt.Errorf("album without name: %w", imgrepo.ErrInvalidArgument)
+	}
+	if album.Access != imgrepo.Public && album.Access != imgrepo.Private {
+		return fmt.Errorf("unknown access %d: %w", album.Access, imgrepo.ErrInvalidArgument)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := ar.checkImages(ctx, album.Owner, album.Images); err != nil {
+		return err
+	}
+
+	// Images are added with $addToSet, which needs an array without
+	// duplicates.
+	seen := make(map[string]bool)
+	ids := []string{}
+	for _, id := range album.Images {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	album.Images = ids
+	album.Id = primitive.NewObjectID().Hex()
+
+	_, err := ar.col.InsertOne(ctx, album)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to create album", err)
+	}
+
+	return nil
+}
+
+func (ar *AlbumRegistry) Find(requester, id string) (*imgrepo.Album, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	album, err := ar.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The groups of the requester are only looked up if they matter.
+	var groups []string
+	if !album.Viewable(requester, nil) && len(album.Groups) > 0 {
+		groups, err = ar.groups.Groups(requester)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to find groups", err)
+		}
+	}
+	if !album.Viewable(requester, groups) {
+		return nil, fmt.Errorf("unable to access album %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	return album, nil
+}
+
+func (ar *AlbumRegistry) List(size int, requester, lastId string) ([]*imgrepo.Album, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("negative page size %d: %w", size, imgrepo.ErrInvalidArgument)
+	}
+
+	groups, err := ar.groups.Groups(requester)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find groups", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filters := viewable(requester, groups)
+	if len(lastId) > 0 {
+		filters["_id"] = bson.M{"$lt": lastId}
+	}
+
+	cursor, err := ar.col.Find(ctx, filters,
+		options.Find().SetSort(bson.M{"_id": -1}),
+		options.Find().SetLimit(int64(size)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "cursor not found", err)
+	}
+	defer cursor.Close(ctx)
+
+	var res []*imgrepo.Album
+	for cursor.Next(ctx) {
+		var album imgrepo.Album
+		if err := cursor.Decode(&album); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+		}
+		res = append(res, &album)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+	}
+
+	return res, nil
+}
+
+func (ar *AlbumRegistry) Rename(requester, id, name string) error {
+	if name == "" {
+		return fmt.Errorf("album without name: %w", imgrepo.ErrInvalidArgument)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return ar.update(ctx, requester, id, bson.M{"$set": bson.M{"name": name}})
+}
+
+func (ar *AlbumRegistry) SetAccess(requester, id string, access imgrepo.Permission) error {
+	if access != imgrepo.Public && access != imgrepo.Private {
+		return fmt.Errorf("unknown access %d: %w", access, imgrepo.ErrInvalidArgument)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return ar.update(ctx, requester, id, bson.M{"$set": bson.M{"access": access}})
+}
+
+// Add checks the album before the images, so that others cannot tell which
+// images exist by adding them to albums they do not own.
+func (ar *AlbumRegistry) Add(requester, id string, imageIds ...string) error {
+	if len(imageIds) == 0 {
+		return fmt.Errorf("%q: %w", "no images to add", imgrepo.ErrInvalidArgument)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	album, err := ar.find(ctx, id)
+	if err != nil {
+		return err
+	}
+	if album.Owner != requester {
+		return fmt.Errorf("unable to update album %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	if err := ar.checkImages(ctx, requester, imageIds); err != nil {
+		return err
+	}
+
+	return ar.update(ctx, requester, id, bson.M{"$addToSet": bson.M{"images": bson.M{"$each": imageIds}}})
+}
+
+func (ar *AlbumRegistry) Remove(requester, id string, imageIds ...string) error {
+	if len(imageIds) == 0 {
+		return fmt.Errorf("%q: %w", "no images to remove", imgrepo.ErrInvalidArgument)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return ar.update(ctx, requester, id, bson.M{"$pullAll": bson.M{"images": imageIds}})
+}
+
+func (ar *AlbumRegistry) Share(requester, id string, g imgrepo.Grant) error {
+	if g.Empty() {
+		return fmt.Errorf("%q: %w", "no users or groups to share with", imgrepo.ErrInvalidArgument)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return ar.update(ctx, requester, id, bson.M{"$addToSet": grantUpdate(g, true)})
+}
+
+func (ar *AlbumRegistry) Unshare(requester, id string, g imgrepo.Grant) error {
+	if g.Empty() {
+		return fmt.Errorf("%q: %w", "no users or groups to unshare", imgrepo.ErrInvalidArgument)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return ar.update(ctx, requester, id, bson.M{"$pullAll": grantUpdate(g, false)})
+}
+
+// Images lists the images of the album newest first, like the images of
+// ImageRegistry.List. Only images of the owner of the album are listed,
+// whatever their access, since the album decides who may view them.
+func (ar *AlbumRegistry) Images(size int, requester, id, lastId string) ([]*imgrepo.Image, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("negative page size %d: %w", size, imgrepo.ErrInvalidArgument)
+	}
+
+	album, err := ar.Find(requester, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(album.Images) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids := bson.M{"$in": album.Images}
+	if len(lastId) > 0 {
+		ids["$lt"] = lastId
+	}
+	filters := bson.M{"_id": ids, "owner": album.Owner}
+
+	cursor, err := ar.images.Find(ctx, filters,
+		options.Find().SetSort(bson.M{"_id": -1}),
+		options.Find().SetLimit(int64(size)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "cursor not found", err)
+	}
+	defer cursor.Close(ctx)
+
+	var res []*imgrepo.Image
+	for cursor.Next(ctx) {
+		var img imgrepo.Image
+		if err := cursor.Decode(&img); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+		}
+		res = append(res, &img)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+	}
+
+	return res, nil
+}
+
+func (ar *AlbumRegistry) Delete(requester, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := ar.col.DeleteOne(ctx, bson.M{"_id": id, "owner": requester})
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to delete album", err)
+	}
+
+	if res.DeletedCount == 0 {
+		if _, err := ar.find(ctx, id); err != nil {
+			return err
+		}
+		return fmt.Errorf("unable to delete album %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	return nil
+}
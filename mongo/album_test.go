@@ -0,0 +1,383 @@
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/memory"
+	"github.com/google/go-cmp/cmp"
+)
+
+// tmpAlbumRegistry returns an AlbumRegistry, and the ImageRegistry holding
+// its images, with the given images uploaded. The group "team" has the
+// members "test" and "test3".
+func tmpAlbumRegistry(t *testing.T, images []*imgrepo.Image) (*AlbumRegistry, *ImageRegistry) {
+	gs := memory.NewGroupService()
+	if _, err := gs.Create("test", "team"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.AddMembers("test", "team", "test3"); err != nil {
+		t.Fatal(err)
+	}
+
+	ir, err := tmpImageRegistry(memory.NewImageStorage(), gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ar := NewAlbumRegistry(ir, "_test.albums")
+
+	t.Cleanup(func() {
+		ar.col.Drop(context.TODO())
+		ir.col.Drop(context.TODO())
+		ir.blobs.Drop(context.TODO())
+	})
+
+	for _, img := range images {
+		img.Digest = digest(nil)
+		if err := ir.Upload(img, bytes.NewReader(nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return ar, ir
+}
+
+func TestAlbumFind(t *testing.T) {
+	tests := map[string]struct {
+		requester string
+		access    imgrepo.Permission
+		grant     imgrepo.Grant
+		wantErr   error
+	}{
+		"owner private": {requester: "test", access: imgrepo.Private},
+		"other public":  {requester: "test2", access: imgrepo.Public},
+		"other private": {requester: "test2", access: imgrepo.Private, wantErr: imgrepo.ErrPermissionDenied},
+		"reader":        {requester: "test2", access: imgrepo.Private, grant: imgrepo.Grant{Readers: []string{"test2"}}},
+		"group member":  {requester: "test3", access: imgrepo.Private, grant: imgrepo.Grant{Groups: []string{"team"}}},
+		"other group":   {requester: "test2", access: imgrepo.Private, grant: imgrepo.Grant{Groups: []string{"team"}}, wantErr: imgrepo.ErrPermissionDenied},
+	}
+
+	ar, _ := tmpAlbumRegistry(t, nil)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			album := &imgrepo.Album{Name: "album", Owner: "test", Access: tc.access}
+			if err := ar.Create(album); err != nil {
+				t.Fatal(err)
+			}
+			if !tc.grant.Empty() {
+				if err := ar.Share("test", album.Id, tc.grant); err != nil {
+					t.Fatal(err)
+				}
+				album.Grant = tc.grant
+			}
+
+			got, err := ar.Find(tc.requester, album.Id)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Find() = _, %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && !cmp.Equal(album, got) {
+				t.Fatalf("Create() and Find() mismatch (-want +got):\n%s", cmp.Diff(album, got))
+			}
+		})
+	}
+}
+
+func TestAlbumCreate(t *testing.T) {
+	images := []*imgrepo.Image{
+		{Owner: "test", Access: imgrepo.Private},
+		{Owner: "test2", Access: imgrepo.Public},
+	}
+
+	tests := map[string]struct {
+		album   imgrepo.Album
+		imgIdx  []int
+		wantErr error
+	}{
+		"empty":          {album: imgrepo.Album{Name: "album", Owner: "test"}},
+		"own images":     {album: imgrepo.Album{Name: "album", Owner: "test"}, imgIdx: []int{0}},
+		"others' images": {album: imgrepo.Album{Name: "album", Owner: "test"}, imgIdx: []int{1}, wantErr: imgrepo.ErrPermissionDenied},
+		"no name":        {album: imgrepo.Album{Owner: "test"}, wantErr: imgrepo.ErrInvalidArgument},
+		"unknown access": {album: imgrepo.Album{Name: "album", Owner: "test", Access: 42}, wantErr: imgrepo.ErrInvalidArgument},
+	}
+
+	ar, _ := tmpAlbumRegistry(t, images)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			album := tc.album
+			for _, idx := range tc.imgIdx {
+				album.Images = append(album.Images, images[idx].Id)
+			}
+
+			err := ar.Create(&album)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Create() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlbumUpdate(t *testing.T) {
+	images := []*imgrepo.Image{
+		{Owner: "test", Access: imgrepo.Private},
+		{Owner: "test", Access: imgrepo.Public},
+		{Owner: "test2", Access: imgrepo.Public},
+	}
+
+	tests := map[string]struct {
+		requester string
+		id        string
+		update    func(ar *AlbumRegistry, requester, id string) error
+		want      func(album *imgrepo.Album)
+		wantErr   error
+	}{
+		"rename": {
+			requester: "test",
+			update: func(ar *AlbumRegistry, requester, id string) error {
+				return ar.Rename(requester, id, "renamed")
+			},
+			want: func(album *imgrepo.Album) { album.Name = "renamed" },
+		},
+		"publish": {
+			requester: "test",
+			update: func(ar *AlbumRegistry, requester, id string) error {
+				return ar.SetAccess(requester, id, imgrepo.Public)
+			},
+			want: func(album *imgrepo.Album) { album.Access = imgrepo.Public },
+		},
+		"add": {
+			requester: "test",
+			update: func(ar *AlbumRegistry, requester, id string) error {
+				return ar.Add(requester, id, images[1].Id, images[0].Id)
+			},
+			want: func(album *imgrepo.Album) { album.Images = []string{images[0].Id, images[1].Id} },
+		},
+		"remove": {
+			requester: "test",
+			update: func(ar *AlbumRegistry, requester, id string) error {
+				return ar.Remove(requester, id, images[0].Id)
+			},
+			want: func(album *imgrepo.Album) { album.Images = []string{} },
+		},
+		"add others' image": {
+			requester: "test",
+			update: func(ar *AlbumRegistry, requester, id string) error {
+				return ar.Add(requester, id, images[2].Id)
+			},
+			wantErr: imgrepo.ErrPermissionDenied,
+		},
+		"add missing image": {
+			requester: "test",
+			update: func(ar *AlbumRegistry, requester, id string) error {
+				return ar.Add(requester, id, "missing")
+			},
+			wantErr: imgrepo.ErrNotFound,
+		},
+		"other": {
+			requester: "test2",
+			update: func(ar *AlbumRegistry, requester, id string) error {
+				return ar.Rename(requester, id, "renamed")
+			},
+			wantErr: imgrepo.ErrPermissionDenied,
+		},
+		"missing": {
+			requester: "test",
+			id:        "missing",
+			update: func(ar *AlbumRegistry, requester, id string) error {
+				return ar.SetAccess(requester, id, imgrepo.Public)
+			},
+			wantErr: imgrepo.ErrNotFound,
+		},
+	}
+
+	ar, _ := tmpAlbumRegistry(t, images)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			album := &imgrepo.Album{Name: "album", Owner: "test", Access: imgrepo.Private, Images: []string{images[0].Id}}
+			if err := ar.Create(album); err != nil {
+				t.Fatal(err)
+			}
+
+			id := album.Id
+			if tc.id != "" {
+				id = tc.id
+			}
+
+			err := tc.update(ar, tc.requester, id)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("update = %v, want %v", err, tc.wantErr)
+			}
+			if tc.want != nil {
+				tc.want(album)
+			}
+
+			got, err := ar.Find("test", album.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(album, got); diff != "" {
+				t.Errorf("update mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAlbumImages(t *testing.T) {
+	images := []*imgrepo.Image{
+		{Owner: "test", Access: imgrepo.Private},
+		{Owner: "test", Access: imgrepo.Public},
+		{Owner: "test", Access: imgrepo.Private},
+		{Owner: "test", Access: imgrepo.Private},
+	}
+
+	ar, ir := tmpAlbumRegistry(t, images)
+
+	public := &imgrepo.Album{Name: "public", Owner: "test", Access: imgrepo.Public}
+	private := &imgrepo.Album{Name: "private", Owner: "test", Access: imgrepo.Private}
+	shared := &imgrepo.Album{Name: "shared", Owner: "test", Access: imgrepo.Private}
+	for _, album := range []*imgrepo.Album{public, private, shared} {
+		album.Images = []string{images[0].Id, images[1].Id, images[2].Id, images[3].Id}
+		if err := ar.Create(album); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ar.Share("test", shared.Id, imgrepo.Grant{Readers: []string{"test2"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleted images are skipped.
+	if err := ir.Delete("test", images[3].Id); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		requester string
+		album     *imgrepo.Album
+		size      int
+		lastIdx   int
+		want      []int
+		wantErr   error
+	}{
+		"owner": {
+			requester: "test",
+			album:     private,
+			size:      20,
+			lastIdx:   -1,
+			want:      []int{2, 1, 0},
+		},
+		"other public": {
+			requester: "test2",
+			album:     public,
+			size:      20,
+			lastIdx:   -1,
+			want:      []int{2, 1, 0},
+		},
+		"other private": {
+			requester: "test2",
+			album:     private,
+			size:      20,
+			lastIdx:   -1,
+			wantErr:   imgrepo.ErrPermissionDenied,
+		},
+		"reader shared": {
+			requester: "test2",
+			album:     shared,
+			size:      20,
+			lastIdx:   -1,
+			want:      []int{2, 1, 0},
+		},
+		"other shared": {
+			requester: "test3",
+			album:     shared,
+			size:      20,
+			lastIdx:   -1,
+			wantErr:   imgrepo.ErrPermissionDenied,
+		},
+		"first page": {
+			requester: "test2",
+			album:     public,
+			size:      2,
+			lastIdx:   -1,
+			want:      []int{2, 1},
+		},
+		"next page": {
+			requester: "test2",
+			album:     public,
+			size:      2,
+			lastIdx:   1,
+			want:      []int{0},
+		},
+		"negative size": {
+			requester: "test",
+			album:     public,
+			size:      -1,
+			lastIdx:   -1,
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var lastId string
+			if tc.lastIdx >= 0 {
+				lastId = images[tc.lastIdx].Id
+			}
+
+			got, err := ar.Images(tc.size, tc.requester, tc.album.Id, lastId)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Images() = _, %v, want %v", err, tc.wantErr)
+			}
+
+			want := make([]*imgrepo.Image, len(tc.want))
+			for i, idx := range tc.want {
+				want[i] = images[idx]
+			}
+
+			if err := cmpSlices(want, got); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestAlbumDelete(t *testing.T) {
+	tests := map[string]struct {
+		requester string
+		wantErr   error
+	}{
+		"owner": {requester: "test"},
+		"other": {requester: "test2", wantErr: imgrepo.ErrPermissionDenied},
+	}
+
+	images := []*imgrepo.Image{{Owner: "test", Access: imgrepo.Private}}
+	ar, ir := tmpAlbumRegistry(t, images)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			album := &imgrepo.Album{Name: "album", Owner: "test", Images: []string{images[0].Id}}
+			if err := ar.Create(album); err != nil {
+				t.Fatal(err)
+			}
+
+			err := ar.Delete(tc.requester, album.Id)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Delete() = %v, want %v", err, tc.wantErr)
+			}
+
+			_, err = ar.Find("test", album.Id)
+			if deleted := errors.Is(err, imgrepo.ErrNotFound); deleted != (tc.wantErr == nil) {
+				t.Errorf("Find() after Delete() = %v", err)
+			}
+
+			// The images of the album are kept.
+			if _, err := ir.find(context.TODO(), images[0].Id); err != nil {
+				t.Errorf("find() after Delete() = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package mongo
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// _Registry encodes uint64 values, like imgrepo.Image.Hash, as the int64
+// with the same bits. The default codecs refuse values above math.MaxInt64,
+// which perceptual hashes routinely are.
+var _Registry = bson.NewRegistryBuilder().
+	RegisterTypeEncoder(reflect.TypeOf(uint64(0)), bsoncodec.ValueEncoderFunc(encodeUint64)).
+	RegisterTypeDecoder(reflect.TypeOf(uint64(0)), bsoncodec.ValueDecoderFunc(decodeUint64)).
+	Build()
+
+func encodeUint64(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	return vw.WriteInt64(int64(val.Uint()))
+}
+
+func decodeUint64(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	var i64 int64
+	switch vr.Type() {
+	case bsontype.Int64:
+		v, err := vr.ReadInt64()
+		if err != nil {
+			return err
+		}
+		i64 = v
+	case bsontype.Int32:
+		v, err := vr.ReadInt32()
+		if err != nil {
+			return err
+		}
+		i64 = int64(v)
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot decode %v into a uint64", vr.Type())
+	}
+
+	val.SetUint(uint64(i64))
+	return nil
+}
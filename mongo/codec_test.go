@@ -0,0 +1,37 @@
+package mongo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestUint64Codec(t *testing.T) {
+	tests := map[string]struct {
+		hash uint64
+	}{
+		"zero":    {hash: 0},
+		"small":   {hash: 42},
+		"max":     {hash: math.MaxUint64},
+		"top bit": {hash: 1 << 63},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := bson.MarshalWithRegistry(_Registry, imgrepo.Image{Hash: tc.hash})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got imgrepo.Image
+			if err := bson.UnmarshalWithRegistry(_Registry, data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Hash != tc.hash {
+				t.Fatalf("got hash %d, want %d", got.Hash, tc.hash)
+			}
+		})
+	}
+}
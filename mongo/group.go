@@ -0,0 +1,257 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GroupService keeps groups in a MongoDB collection, named by their _id.
+// Deleted groups are kept without members, so that their names are not
+// reused.
+type GroupService struct {
+	col *mongo.Collection
+
+	// mu serializes the changes to groups, which are checked before they
+	// are made. This assumes a single server updates the groups.
+	mu sync.Mutex
+}
+
+var _ imgrepo.GroupService = (*GroupService)(nil)
+
+// NewGroupService returns a GroupService with the MongoDB collection configured.
+func NewGroupService(uri, db, col string) (*GroupService, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := connect(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create GroupService", err)
+	}
+
+	gs := &GroupService{col: client.Database(db).Collection(col)}
+
+	// The groups of a user are looked up on every listing.
+	_, err = gs.col.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "members", Value: 1}}})
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to index members", err)
+	}
+
+	return gs, nil
+}
+
+// find returns the group, if it has not been deleted.
+func (gs *GroupService) find(ctx context.Context, name string) (*imgrepo.Group, error) {
+	var g imgrepo.Group
+	err := gs.col.FindOne(ctx, bson.M{"_id": name}).Decode(&g)
+	if err == mongo.ErrNoDocuments || (err == nil && len(g.Admins) == 0) {
+		return nil, fmt.Errorf("group %s: %w", name, imgrepo.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find group", err)
+	}
+
+	return &g, nil
+}
+
+func (gs *GroupService) Create(requester, name string) (*imgrepo.Group, error) {
+	if name == "" {
+		return nil, fmt.Errorf("group without name: %w", imgrepo.ErrInvalidArgument)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	g := &imgrepo.Group{Name: name, Admins: []string{requester}, Members: []string{requester}}
+	_, err := gs.col.InsertOne(ctx, g)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("group %s: %w", name, imgrepo.ErrAlreadyExists)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create group", err)
+	}
+
+	return g, nil
+}
+
+// Find does not tell others whether the group exists.
+func (gs *GroupService) Find(requester, name string) (*imgrepo.Group, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	g, err := gs.find(ctx, name)
+	if err != nil || !g.IsMember(requester) {
+		return nil, fmt.Errorf("group %s: %w", name, imgrepo.ErrNotFound)
+	}
+
+	return g, nil
+}
+
+func (gs *GroupService) Exists(name string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	n, err := gs.col.CountDocuments(ctx, bson.M{"_id": name, "admins.0": bson.M{"$exists": true}}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("%q: %w", "unable to find group", err)
+	}
+
+	return n > 0, nil
+}
+
+func (gs *GroupService) Groups(user string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := gs.col.Find(ctx,
+		bson.M{"members": user},
+		options.Find().SetProjection(bson.M{"_id": 1}).SetSort(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "cursor not found", err)
+	}
+	defer cursor.Close(ctx)
+
+	var res []string
+	for cursor.Next(ctx) {
+		var g imgrepo.Group
+		if err := cursor.Decode(&g); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+		}
+		res = append(res, g.Name)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+	}
+
+	return res, nil
+}
+
+func (gs *GroupService) AddMembers(requester, name string, users ...string) (*imgrepo.Group, error) {
+	return gs.change(requester, name, users, false, func(g *imgrepo.Group) {
+		g.Members = union(g.Members, users)
+	})
+}
+
+func (gs *GroupService) RemoveMembers(requester, name string, users ...string) (*imgrepo.Group, error) {
+	leaving := len(users) == 1 && users[0] == requester
+	return gs.change(requester, name, users, leaving, func(g *imgrepo.Group) {
+		g.Members = difference(g.Members, users)
+		g.Admins = difference(g.Admins, users)
+	})
+}
+
+func (gs *GroupService) AddAdmins(requester, name string, users ...string) (*imgrepo.Group, error) {
+	return gs.change(requester, name, users, false, func(g *imgrepo.Group) {
+		g.Members = union(g.Members, users)
+		g.Admins = union(g.Admins, users)
+	})
+}
+
+func (gs *GroupService) RemoveAdmins(requester, name string, users ...string) (*imgrepo.Group, error) {
+	return gs.change(requester, name, users, false, func(g *imgrepo.Group) {
+		g.Admins = difference(g.Admins, users)
+	})
+}
+
+// change applies fn to the group, if the requester administers the group
+// or is a member allowed to make the change, and stores the result if it
+// still has an admin.
+func (gs *GroupService) change(requester, name string, users []string, allowMember bool, fn func(g *imgrepo.Group)) (*imgrepo.Group, error) {
+	if len(users) == 0 {
+		return nil, fmt.Errorf("%q: %w", "no users given", imgrepo.ErrInvalidArgument)
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	g, err := gs.find(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !g.IsAdmin(requester) && !(allowMember && g.IsMember(requester)) {
+		return nil, fmt.Errorf("unable to change group %s: %w", name, imgrepo.ErrPermissionDenied)
+	}
+
+	fn(g)
+	if len(g.Admins) == 0 {
+		return nil, fmt.Errorf("group %s would have no admins: %w", name, imgrepo.ErrInvalidArgument)
+	}
+
+	_, err = gs.col.UpdateOne(ctx,
+		bson.M{"_id": name},
+		bson.M{"$set": bson.M{"admins": g.Admins, "members": g.Members}},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to update group", err)
+	}
+
+	return g, nil
+}
+
+func (gs *GroupService) Delete(requester, name string) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	g, err := gs.find(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if !g.IsAdmin(requester) {
+		return fmt.Errorf("unable to delete group %s: %w", name, imgrepo.ErrPermissionDenied)
+	}
+
+	_, err = gs.col.UpdateOne(ctx,
+		bson.M{"_id": name},
+		bson.M{"$set": bson.M{"admins": bson.A{}, "members": bson.A{}}},
+	)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to delete group", err)
+	}
+
+	return nil
+}
+
+// union returns the users in a, followed by the users in b but not in a.
+func union(a, b []string) []string {
+	res := append([]string(nil), a...)
+	for _, user := range b {
+		if !containsUser(res, user) {
+			res = append(res, user)
+		}
+	}
+	return res
+}
+
+// difference returns the users in a but not in b, and never nil, since
+// MongoDB stores nil as null rather than an empty array.
+func difference(a, b []string) []string {
+	res := []string{}
+	for _, user := range a {
+		if !containsUser(b, user) {
+			res = append(res, user)
+		}
+	}
+	return res
+}
+
+func containsUser(users []string, user string) bool {
+	for _, u := range users {
+		if u == user {
+			return true
+		}
+	}
+	return false
+}
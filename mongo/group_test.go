@@ -0,0 +1,286 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/joho/godotenv"
+)
+
+// tmpGroupService returns a GroupService with the group "team", administered
+// by "admin" with the member "member", and the deleted group "old".
+func tmpGroupService(t *testing.T) *GroupService {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		panic(err)
+	}
+
+	gs, err := NewGroupService(os.Getenv("MONGO_URI"), os.Getenv("MONGO_DB"), "_test.groups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { gs.col.Drop(context.TODO()) })
+
+	if _, err := gs.Create("admin", "team"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.AddMembers("admin", "team", "member"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.Create("admin", "old"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.Delete("admin", "old"); err != nil {
+		t.Fatal(err)
+	}
+	return gs
+}
+
+func TestGroupCreate(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		wantErr error
+	}{
+		"new":     {name: "new"},
+		"taken":   {name: "team", wantErr: imgrepo.ErrAlreadyExists},
+		"deleted": {name: "old", wantErr: imgrepo.ErrAlreadyExists},
+		"empty":   {name: "", wantErr: imgrepo.ErrInvalidArgument},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gs := tmpGroupService(t)
+
+			g, err := gs.Create("other", tc.name)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Create() = _, %v, want %v", err, tc.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			want := &imgrepo.Group{Name: tc.name, Admins: []string{"other"}, Members: []string{"other"}}
+			if diff := cmp.Diff(want, g); diff != "" {
+				t.Errorf("Create() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroupFind(t *testing.T) {
+	tests := map[string]struct {
+		requester  string
+		name       string
+		wantExists bool
+		wantErr    error
+	}{
+		"admin":      {requester: "admin", name: "team", wantExists: true},
+		"member":     {requester: "member", name: "team", wantExists: true},
+		"non-member": {requester: "other", name: "team", wantExists: true, wantErr: imgrepo.ErrNotFound},
+		"deleted":    {requester: "admin", name: "old", wantErr: imgrepo.ErrNotFound},
+		"missing":    {requester: "admin", name: "missing", wantErr: imgrepo.ErrNotFound},
+	}
+
+	gs := tmpGroupService(t)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := gs.Find(tc.requester, tc.name)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Find() = _, %v, want %v", err, tc.wantErr)
+			} else if err == nil && !g.IsMember(tc.requester) {
+				t.Errorf("Find() = %v, want member %s", g, tc.requester)
+			}
+
+			ok, err := gs.Exists(tc.name)
+			if err != nil {
+				t.Fatal(err)
+			} else if ok != tc.wantExists {
+				t.Errorf("Exists() = %v, want %v", ok, tc.wantExists)
+			}
+		})
+	}
+}
+
+func TestGroupChange(t *testing.T) {
+	tests := map[string]struct {
+		op        string
+		requester string
+		name      string
+		users     []string
+		want      *imgrepo.Group
+		wantErr   error
+	}{
+		"add members": {
+			op:        "add",
+			requester: "admin",
+			users:     []string{"member", "new"},
+			want:      &imgrepo.Group{Admins: []string{"admin"}, Members: []string{"admin", "member", "new"}},
+		},
+		"add members as member": {
+			op:        "add",
+			requester: "member",
+			users:     []string{"new"},
+			wantErr:   imgrepo.ErrPermissionDenied,
+		},
+		"remove member": {
+			op:        "remove",
+			requester: "admin",
+			users:     []string{"member"},
+			want:      &imgrepo.Group{Admins: []string{"admin"}, Members: []string{"admin"}},
+		},
+		"leave": {
+			op:        "remove",
+			requester: "member",
+			users:     []string{"member"},
+			want:      &imgrepo.Group{Admins: []string{"admin"}, Members: []string{"admin"}},
+		},
+		"remove other as member": {
+			op:        "remove",
+			requester: "member",
+			users:     []string{"admin"},
+			wantErr:   imgrepo.ErrPermissionDenied,
+		},
+		"last admin leaves": {
+			op:        "remove",
+			requester: "admin",
+			users:     []string{"admin"},
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+		"add admins": {
+			op:        "add admins",
+			requester: "admin",
+			users:     []string{"member", "new"},
+			want:      &imgrepo.Group{Admins: []string{"admin", "member", "new"}, Members: []string{"admin", "member", "new"}},
+		},
+		"remove last admin": {
+			op:        "remove admins",
+			requester: "admin",
+			users:     []string{"admin"},
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+		"no users": {
+			op:        "add",
+			requester: "admin",
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+		"deleted": {
+			op:        "add",
+			requester: "admin",
+			name:      "old",
+			users:     []string{"new"},
+			wantErr:   imgrepo.ErrNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gs := tmpGroupService(t)
+			ops := map[string]func(requester, name string, users ...string) (*imgrepo.Group, error){
+				"add":           gs.AddMembers,
+				"remove":        gs.RemoveMembers,
+				"add admins":    gs.AddAdmins,
+				"remove admins": gs.RemoveAdmins,
+			}
+
+			group := "team"
+			if tc.name != "" {
+				group = tc.name
+			}
+
+			want, err := gs.Find("admin", "team")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want != nil {
+				want = tc.want
+				want.Name = group
+			}
+
+			g, err := ops[tc.op](tc.requester, group, tc.users...)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("%s = _, %v, want %v", tc.op, err, tc.wantErr)
+			} else if err == nil {
+				if diff := cmp.Diff(want, g); diff != "" {
+					t.Errorf("%s mismatch (-want +got):\n%s", tc.op, diff)
+				}
+			} else if group != "team" {
+				return
+			}
+
+			// Changes are stored, and failed changes leave the group unchanged.
+			stored, err := gs.Find("admin", "team")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, stored, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Find() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroups(t *testing.T) {
+	tests := map[string]struct {
+		user string
+		want []string
+	}{
+		"admin":  {user: "admin", want: []string{"other", "team"}},
+		"member": {user: "member", want: []string{"team"}},
+		"none":   {user: "other"},
+	}
+
+	gs := tmpGroupService(t)
+	if _, err := gs.Create("admin", "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := gs.Groups(tc.user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Groups() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroupDelete(t *testing.T) {
+	tests := map[string]struct {
+		requester string
+		name      string
+		wantErr   error
+	}{
+		"admin":   {requester: "admin", name: "team"},
+		"member":  {requester: "member", name: "team", wantErr: imgrepo.ErrPermissionDenied},
+		"deleted": {requester: "admin", name: "old", wantErr: imgrepo.ErrNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gs := tmpGroupService(t)
+
+			err := gs.Delete(tc.requester, tc.name)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Delete() = %v, want %v", err, tc.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			// The members no longer belong to the group.
+			if ok, err := gs.Exists(tc.name); err != nil || ok {
+				t.Errorf("Exists() = %v, %v, want false", ok, err)
+			}
+			if groups, err := gs.Groups("member"); err != nil || len(groups) > 0 {
+				t.Errorf("Groups() = %v, %v, want none", groups, err)
+			}
+		})
+	}
+}
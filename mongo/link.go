@@ -0,0 +1,159 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/algao1/imgrepo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LinkRegistry keeps share links in a MongoDB collection. Times are stored
+// with millisecond precision.
+type LinkRegistry struct {
+	col *mongo.Collection
+	now func() time.Time
+}
+
+var _ imgrepo.LinkRegistry = (*LinkRegistry)(nil)
+
+// NewLinkRegistry returns a LinkRegistry with the MongoDB collection configured.
+func NewLinkRegistry(uri, db, col string) (*LinkRegistry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := connect(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create LinkRegistry", err)
+	}
+
+	lr := &LinkRegistry{col: client.Database(db).Collection(col), now: time.Now}
+
+	_, err = lr.col.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "owner", Value: 1}}})
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to index owners", err)
+	}
+
+	return lr, nil
+}
+
+func (lr *LinkRegistry) Create(link *imgrepo.ShareLink) error {
+	if link.Id == "" {
+		return fmt.Errorf("link without id: %w", imgrepo.ErrInvalidArgument)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	link.Created = lr.now().Truncate(time.Millisecond)
+	_, err := lr.col.InsertOne(ctx, link)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("link %s: %w", link.Id, imgrepo.ErrAlreadyExists)
+	} else if err != nil {
+		return fmt.Errorf("%q: %w", "unable to create link", err)
+	}
+
+	return nil
+}
+
+func (lr *LinkRegistry) Find(id string) (*imgrepo.ShareLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var link imgrepo.ShareLink
+	err := lr.col.FindOne(ctx, bson.M{"_id": id}).Decode(&link)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("link %s: %w", id, imgrepo.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find link", err)
+	}
+
+	return &link, nil
+}
+
+func (lr *LinkRegistry) List(requester string) ([]*imgrepo.ShareLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := lr.col.Find(ctx,
+		bson.M{"owner": requester},
+		options.Find().SetSort(bson.D{{Key: "created", Value: -1}, {Key: "_id", Value: -1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "cursor not found", err)
+	}
+	defer cursor.Close(ctx)
+
+	var res []*imgrepo.ShareLink
+	for cursor.Next(ctx) {
+		var link imgrepo.ShareLink
+		if err := cursor.Decode(&link); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+		}
+		res = append(res, &link)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+	}
+
+	return res, nil
+}
+
+// Redeem checks and counts the download in a single update, so that
+// concurrent downloads cannot exceed the maximum.
+func (lr *LinkRegistry) Redeem(id string) (*imgrepo.ShareLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id": id,
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"expires": time.Time{}},
+				bson.M{"expires": bson.M{"$gt": lr.now()}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"maxdownloads": 0},
+				bson.M{"$expr": bson.M{"$lt": bson.A{"$downloads", "$maxdownloads"}}},
+			}},
+		},
+	}
+
+	var link imgrepo.ShareLink
+	err := lr.col.FindOneAndUpdate(ctx, filter,
+		bson.M{"$inc": bson.M{"downloads": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&link)
+	if err == mongo.ErrNoDocuments {
+		if _, err := lr.Find(id); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("link %s: %w", id, imgrepo.ErrExpired)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to redeem link", err)
+	}
+
+	return &link, nil
+}
+
+func (lr *LinkRegistry) Revoke(requester, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := lr.col.DeleteOne(ctx, bson.M{"_id": id, "owner": requester})
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to revoke link", err)
+	}
+
+	if res.DeletedCount == 0 {
+		if _, err := lr.Find(id); err != nil {
+			return err
+		}
+		return fmt.Errorf("unable to revoke link %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	return nil
+}
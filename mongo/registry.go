@@ -1,8 +1,13 @@
 package mongo
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/algao1/imgrepo"
@@ -12,15 +17,43 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ImageRegistry keeps image entries in a MongoDB collection. Blobs are
+// stored under the digest of their content, and shared by every entry with
+// the same digest. Their reference counts, and the sizes of their renditions,
+// are kept in a second collection, named after the first with a ".blobs"
+// suffix.
 type ImageRegistry struct {
 	col     *mongo.Collection
+	blobs   *mongo.Collection
 	storage imgrepo.ImageStorage
+	groups  imgrepo.GroupService
+
+	// mu guards locks, which serialize the changes to each blob. This
+	// assumes a single server updates the registry.
+	mu    sync.Mutex
+	locks map[string]*blobLock
+}
+
+// blob is the reference count of a stored blob, and the sizes of its
+// renditions.
+type blob struct {
+	Digest     string `bson:"_id"`
+	Refs       int    `bson:"refs"`
+	Renditions []int  `bson:"renditions"`
+}
+
+// blobLock serializes the changes to a blob, and counts the callers holding
+// or waiting on it.
+type blobLock struct {
+	mu   sync.Mutex
+	refs int
 }
 
 var _ imgrepo.ImageRegistry = (*ImageRegistry)(nil)
 
-// NewImageRegistry returns a ImageRegistry with the MongoDB collection configured.
-func NewImageRegistry(store imgrepo.ImageStorage, uri, db, col string) (*ImageRegistry, error) {
+// NewImageRegistry returns a ImageRegistry with the MongoDB collection configured,
+// whose images can be shared with the groups of groups.
+func NewImageRegistry(store imgrepo.ImageStorage, groups imgrepo.GroupService, uri, db, col string) (*ImageRegistry, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -29,70 +62,405 @@ func NewImageRegistry(store imgrepo.ImageStorage, uri, db, col string) (*ImageRe
 		return nil, fmt.Errorf("%q: %w", "unable to create UserService", err)
 	}
 
-	return &ImageRegistry{
+	ir := &ImageRegistry{
 		col:     client.Database(db).Collection(col),
+		blobs:   client.Database(db).Collection(col + ".blobs"),
 		storage: store,
-	}, nil
+		groups:  groups,
+		locks:   make(map[string]*blobLock),
+	}
+
+	// Listing by tags, or the images shared with a user or their groups,
+	// looks up the entries through the indexes of their tags and grants.
+	_, err = ir.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tags", Value: 1}}},
+		{Keys: bson.D{{Key: "readers", Value: 1}}},
+		{Keys: bson.D{{Key: "groups", Value: 1}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to index tags and grants", err)
+	}
+
+	return ir, nil
 }
 
-func (ir *ImageRegistry) Upload(img *imgrepo.Image) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// lockBlob serializes the changes to the blob with the given digest, and
+// returns the function releasing it. The lock is dropped once nothing holds
+// or waits on it, so that locks do not pile up with every blob ever changed.
+func (ir *ImageRegistry) lockBlob(digest string) func() {
+	ir.mu.Lock()
+	l, ok := ir.locks[digest]
+	if !ok {
+		l = new(blobLock)
+		ir.locks[digest] = l
+	}
+	l.refs++
+	ir.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+
+		ir.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(ir.locks, digest)
+		}
+		ir.mu.Unlock()
+	}
+}
+
+// lockBlobs is like lockBlob for several blobs, which are locked in order so
+// that concurrent callers cannot deadlock.
+func (ir *ImageRegistry) lockBlobs(digests ...string) func() {
+	sort.Strings(digests)
+
+	var unlocks []func()
+	for i, digest := range digests {
+		if i > 0 && digest == digests[i-1] {
+			continue
+		}
+		unlocks = append(unlocks, ir.lockBlob(digest))
+	}
+
+	return func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+}
+
+// blobId returns the storage id of the image. Images uploaded before blobs
+// were deduplicated have no digest, and are stored under their own id.
+func blobId(img *imgrepo.Image) string {
+	if img.Digest == "" {
+		return img.Id
+	}
+	return img.Digest
+}
+
+// digestIs returns the filter selecting the entries with the given digest.
+// Images uploaded before blobs were deduplicated may have no digest at all.
+func digestIs(digest string) interface{} {
+	if digest == "" {
+		return bson.M{"$in": bson.A{"", nil}}
+	}
+	return digest
+}
+
+// findBlob returns the blob, which has no references if it is not stored,
+// lockBlob must be held.
+func (ir *ImageRegistry) findBlob(ctx context.Context, digest string) (*blob, error) {
+	b := blob{Digest: digest}
+	err := ir.blobs.FindOne(ctx, bson.M{"_id": digest}).Decode(&b)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("%q: %w", "unable to find blob", err)
+	}
 
+	return &b, nil
+}
+
+// release drops a reference to the blob of img, and deletes the blob if it
+// was the last one, lockBlob must be held.
+func (ir *ImageRegistry) release(ctx context.Context, img *imgrepo.Image) error {
+	if img.Digest == "" {
+		return deleteBlob(ir.storage, img.Id)
+	}
+
+	b, err := ir.findBlob(ctx, img.Digest)
+	if err != nil {
+		return err
+	}
+
+	if b.Refs > 1 {
+		_, err = ir.blobs.UpdateOne(ctx, bson.M{"_id": img.Digest}, bson.M{"$inc": bson.M{"refs": -1}})
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to release blob", err)
+		}
+		return nil
+	}
+
+	// The count is removed after the blob and its renditions, so that a
+	// failure never leaves an unreferenced blob behind.
+	for _, size := range b.Renditions {
+		if err := deleteBlob(ir.storage, imgrepo.RenditionId(img.Digest, size)); err != nil {
+			return err
+		}
+	}
+	if err := deleteBlob(ir.storage, img.Digest); err != nil {
+		return err
+	}
+
+	_, err = ir.blobs.DeleteOne(ctx, bson.M{"_id": img.Digest})
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to release blob", err)
+	}
+
+	return nil
+}
+
+// deleteBlob deletes the stored object with the given id. Objects already
+// gone were deleted by an earlier attempt that failed partway, so they are
+// not an error, and the deletion can be retried.
+func deleteBlob(is imgrepo.ImageStorage, id string) error {
+	if err := is.Delete(id); err != nil && !errors.Is(err, imgrepo.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// find returns the registry entry with the given id.
+func (ir *ImageRegistry) find(ctx context.Context, id string) (*imgrepo.Image, error) {
+	var img imgrepo.Image
+	err := ir.col.FindOne(ctx, bson.M{"_id": id}).Decode(&img)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("file %s: %w", id, imgrepo.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find file", err)
+	}
+
+	return &img, nil
+}
+
+// Upload adds an entry for img, whose Digest must be the SHA-256 of r.
+// The blob is only streamed from r if no other entry references it, and
+// only the renditions the blob does not have yet are stored.
+func (ir *ImageRegistry) Upload(img *imgrepo.Image, r io.Reader, renditions ...imgrepo.Rendition) error {
+	if img.Digest == "" {
+		return fmt.Errorf("image without digest: %w", imgrepo.ErrInvalidArgument)
+	}
 	img.Id = primitive.NewObjectID().Hex()
 
+	unlock := ir.lockBlob(img.Digest)
+	defer unlock()
+
+	if err := ir.acquire(img, r, renditions); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
 	_, err := ir.col.InsertOne(ctx, img)
 	if err != nil {
+		if rerr := ir.release(ctx, img); rerr != nil {
+			return fmt.Errorf("unable to release blob %s: %v: %w", img.Digest, rerr, err)
+		}
 		return fmt.Errorf("%q: %w", "unable to upload image to registry", err)
 	}
 
-	err = ir.storage.Upload(img)
+	return nil
+}
+
+// acquire adds a reference to the blob of img, streaming it from r if it is
+// not stored yet, along with the renditions it does not have yet, and sets
+// img.Renditions. lockBlob must be held.
+func (ir *ImageRegistry) acquire(img *imgrepo.Image, r io.Reader, renditions []imgrepo.Rendition) error {
+	fctx, fcancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer fcancel()
+
+	b, err := ir.findBlob(fctx, img.Digest)
+	if err != nil {
+		return err
+	}
+
+	// The image is stored first, since the stream may take much longer than
+	// the registry timeout.
+	if b.Refs == 0 {
+		err = ir.storage.Upload(img.Digest, r)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "unable to upload image to storage", err)
+		}
+	}
+
+	added, err := uploadRenditions(ir.storage, img.Digest, b.Renditions, renditions)
 	if err != nil {
-		return fmt.Errorf("%q: %w", "unable to upload image to storage", err)
+		if b.Refs == 0 {
+			if derr := ir.storage.Delete(img.Digest); derr != nil {
+				return fmt.Errorf("unable to remove file %s: %v: %w", img.Digest, derr, err)
+			}
+		}
+		return err
+	}
+
+	sizes := append(append([]int{}, b.Renditions...), added...)
+	sort.Ints(sizes)
+	img.Renditions = sizes
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = ir.blobs.UpdateOne(ctx,
+		bson.M{"_id": img.Digest},
+		bson.M{
+			"$inc":      bson.M{"refs": 1},
+			"$addToSet": bson.M{"renditions": bson.M{"$each": added}},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		for _, size := range added {
+			ir.storage.Delete(imgrepo.RenditionId(img.Digest, size))
+		}
+		if b.Refs == 0 {
+			if derr := ir.storage.Delete(img.Digest); derr != nil {
+				return fmt.Errorf("unable to remove file %s: %v: %w", img.Digest, derr, err)
+			}
+		}
+		return fmt.Errorf("%q: %w", "unable to reference blob", err)
 	}
 
 	return nil
 }
 
-func (ir *ImageRegistry) Download(requester, id string) (*imgrepo.Image, error) {
+// uploadRenditions stores the renditions of the blob whose sizes are not in
+// sizes, and returns the sizes stored. If one fails, the ones already stored
+// are deleted again.
+func uploadRenditions(is imgrepo.ImageStorage, blobId string, sizes []int, renditions []imgrepo.Rendition) ([]int, error) {
+	added := []int{}
+	for _, rd := range renditions {
+		if containsSize(sizes, rd.Size) || containsSize(added, rd.Size) {
+			continue
+		}
+
+		err := is.Upload(imgrepo.RenditionId(blobId, rd.Size), bytes.NewReader(rd.Data))
+		if err != nil {
+			for _, size := range added {
+				is.Delete(imgrepo.RenditionId(blobId, size))
+			}
+			return nil, fmt.Errorf("%q: %w", "unable to upload rendition to storage", err)
+		}
+		added = append(added, rd.Size)
+	}
+
+	return added, nil
+}
+
+func containsSize(sizes []int, size int) bool {
+	for _, s := range sizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+func (ir *ImageRegistry) Download(requester, id string) (*imgrepo.Image, io.ReadCloser, error) {
+	return ir.download(requester, id, 0)
+}
+
+func (ir *ImageRegistry) Rendition(requester, id string, size int) (*imgrepo.Image, io.ReadCloser, error) {
+	if size <= 0 {
+		return nil, nil, fmt.Errorf("rendition size %d: %w", size, imgrepo.ErrInvalidArgument)
+	}
+	return ir.download(requester, id, size)
+}
+
+// download streams the image, or its rendition of the given size if the
+// size is not 0.
+func (ir *ImageRegistry) download(requester, id string, size int) (*imgrepo.Image, io.ReadCloser, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var img imgrepo.Image
-	err := ir.col.FindOne(ctx, bson.M{"_id": id}).Decode(&img)
+	img, err := ir.find(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("%q: %w", "unable to find file", err)
+		return nil, nil, err
 	}
 
-	if img.Owner != requester && img.Access != imgrepo.Public {
-		return nil, fmt.Errorf("unable to access file: %s", id)
+	ok, err := ir.viewable(img, requester)
+	if err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, fmt.Errorf("unable to access file %s: %w", id, imgrepo.ErrPermissionDenied)
 	}
 
-	raw, err := ir.storage.Download(id)
+	sid := blobId(img)
+	if size != 0 {
+		if !containsSize(img.Renditions, size) {
+			return nil, nil, fmt.Errorf("rendition %d of file %s: %w", size, id, imgrepo.ErrNotFound)
+		}
+		sid = imgrepo.RenditionId(sid, size)
+	}
+
+	rc, err := ir.storage.Download(sid)
 	if err != nil {
-		return nil, fmt.Errorf("%q: %w", "unable to download file", err)
+		return nil, nil, fmt.Errorf("%q: %w", "unable to download file", err)
 	}
 
-	img.Raw = raw
+	return img, rc, nil
+}
 
-	return &img, nil
+// viewable reports whether the requester may view the image, looking up the
+// groups of the requester only if the image is shared with groups.
+func (ir *ImageRegistry) viewable(img *imgrepo.Image, requester string) (bool, error) {
+	if img.Viewable(requester, nil) || len(img.Groups) == 0 {
+		return img.Viewable(requester, nil), nil
+	}
+
+	groups, err := ir.groups.Groups(requester)
+	if err != nil {
+		return false, fmt.Errorf("%q: %w", "unable to find groups", err)
+	}
+
+	return img.Viewable(requester, groups), nil
+}
+
+// granted returns the filter selecting the entries shared with the requester,
+// a member of the groups.
+func granted(requester string, groups []string) bson.A {
+	res := bson.A{bson.M{"readers": requester}}
+	if len(groups) > 0 {
+		res = append(res, bson.M{"groups": bson.M{"$in": groups}})
+	}
+	return res
+}
+
+// viewable returns the filter selecting the entries viewable by the
+// requester, a member of the groups. It is shared with AlbumRegistry.
+func viewable(requester string, groups []string) bson.M {
+	return bson.M{
+		"$or": append(bson.A{
+			bson.M{"access": imgrepo.Public},
+			bson.M{"owner": requester},
+		}, granted(requester, groups)...),
+	}
 }
 
-func (ir *ImageRegistry) List(size int, requester, lastId string) ([]*imgrepo.Image, error) {
+func (ir *ImageRegistry) List(size int, requester, lastId string, filter imgrepo.ListFilter) ([]*imgrepo.Image, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("negative page size %d: %w", size, imgrepo.ErrInvalidArgument)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// The groups of the requester are looked up once, rather than for
+	// each entry.
+	groups, err := ir.groups.Groups(requester)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find groups", err)
+	}
+
 	// Filters and pagination.
-	filters := bson.M{
-		"$or": bson.A{
-			bson.M{"access": imgrepo.Public},
-			bson.M{"owner": requester},
-		},
+	filters := viewable(requester, groups)
+	if filter.Shared {
+		filters = bson.M{"owner": bson.M{"$ne": requester}, "$or": granted(requester, groups)}
 	}
 	if len(lastId) > 0 {
 		filters["_id"] = bson.M{"$lt": lastId}
 	}
 
+	// An empty $all matches nothing, so empty lists are left out.
+	tags := bson.M{}
+	if len(filter.Any) > 0 {
+		tags["$in"] = filter.Any
+	}
+	if len(filter.All) > 0 {
+		tags["$all"] = filter.All
+	}
+	if len(tags) > 0 {
+		filters["tags"] = tags
+	}
+
 	// Query options.
 	var opts []*options.FindOptions
 	opts = append(opts, options.Find().SetSort(bson.M{"_id": -1}))
@@ -117,3 +485,287 @@ func (ir *ImageRegistry) List(size int, requester, lastId string) ([]*imgrepo.Im
 
 	return res, nil
 }
+
+func (ir *ImageRegistry) Find(requester string, ids ...string) ([]*imgrepo.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	groups, err := ir.groups.Groups(requester)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find groups", err)
+	}
+
+	filters := viewable(requester, groups)
+	filters["_id"] = bson.M{"$in": ids}
+
+	cursor, err := ir.col.Find(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "cursor not found", err)
+	}
+	defer cursor.Close(ctx)
+
+	found := make(map[string]*imgrepo.Image)
+	for cursor.Next(ctx) {
+		var img imgrepo.Image
+		if err := cursor.Decode(&img); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+		}
+		found[img.Id] = &img
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+	}
+
+	// The documents are returned in any order.
+	var res []*imgrepo.Image
+	for _, id := range ids {
+		if img, ok := found[id]; ok {
+			res = append(res, img)
+		}
+	}
+
+	return res, nil
+}
+
+func (ir *ImageRegistry) Stat(id string) (*imgrepo.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return ir.find(ctx, id)
+}
+
+// Walk iterates over the whole collection, so it is not bound by the usual
+// registry timeout.
+func (ir *ImageRegistry) Walk(fn func(img *imgrepo.Image) error) error {
+	ctx := context.Background()
+
+	cursor, err := ir.col.Find(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("%q: %w", "cursor not found", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var img imgrepo.Image
+		if err := cursor.Decode(&img); err != nil {
+			return fmt.Errorf("%q: %w", "unable to complete query", err)
+		}
+		if err := fn(&img); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("%q: %w", "unable to complete query", err)
+	}
+
+	return nil
+}
+
+// Update changes the entry atomically, through modify.
+func (ir *ImageRegistry) Update(requester string, img *imgrepo.Image, fields ...imgrepo.Field) (*imgrepo.Image, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%q: %w", "no fields to update", imgrepo.ErrInvalidArgument)
+	}
+
+	set := bson.M{}
+	for _, f := range fields {
+		switch f {
+		case imgrepo.NameField:
+			set["name"] = img.Name
+		case imgrepo.AccessField:
+			set["access"] = img.Access
+		case imgrepo.TakenField:
+			set["metadata.taken"] = img.Metadata.Taken
+		case imgrepo.LocationField:
+			set["metadata.location"] = img.Metadata.Location
+		case imgrepo.TagsField:
+			set["tags"] = img.Tags
+		default:
+			return nil, fmt.Errorf("field %q cannot be updated: %w", f, imgrepo.ErrInvalidArgument)
+		}
+	}
+
+	return ir.modify(requester, img.Id, bson.M{"$set": set})
+}
+
+// modify applies the update to the entry atomically, and only if it is owned
+// by the requester, and returns the updated entry. The owner is checked apart
+// only to tell why nothing changed.
+func (ir *ImageRegistry) modify(requester, id string, update bson.M) (*imgrepo.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var res imgrepo.Image
+	err := ir.col.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "owner": requester},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&res)
+	if err == mongo.ErrNoDocuments {
+		if _, err := ir.find(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unable to update file %s: %w", id, imgrepo.ErrPermissionDenied)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to update file", err)
+	}
+
+	return &res, nil
+}
+
+// Replace acquires the new blob before the entry is swapped, and releases the
+// old one after, restoring the entry if it cannot be, so that a failure never
+// leaves an unreferenced blob behind. The entry is only swapped if its digest
+// is unchanged, which also covers the updates of other servers.
+func (ir *ImageRegistry) Replace(requester, digest string, img *imgrepo.Image, r io.Reader, renditions ...imgrepo.Rendition) (*imgrepo.Image, error) {
+	if img.Digest == "" {
+		return nil, fmt.Errorf("image without digest: %w", imgrepo.ErrInvalidArgument)
+	}
+
+	fctx, fcancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer fcancel()
+
+	cur, err := ir.find(fctx, img.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cur.Owner != requester {
+		return nil, fmt.Errorf("unable to replace file %s: %w", img.Id, imgrepo.ErrPermissionDenied)
+	}
+	if cur.Digest != digest {
+		return nil, fmt.Errorf("file %s changed since digest %s: %w", img.Id, digest, imgrepo.ErrConflict)
+	}
+
+	unlock := ir.lockBlobs(blobId(cur), img.Digest)
+	defer unlock()
+
+	if err := ir.acquire(img, r, renditions); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := ir.swap(ctx, img.Id, cur.Digest, img)
+	if err != nil {
+		if rerr := ir.release(ctx, img); rerr != nil {
+			return nil, fmt.Errorf("unable to release blob %s: %v: %w", img.Digest, rerr, err)
+		}
+		return nil, err
+	}
+
+	err = ir.release(ctx, cur)
+	if err != nil {
+		if _, rerr := ir.swap(ctx, img.Id, img.Digest, cur); rerr != nil {
+			return nil, fmt.Errorf("unable to restore entry %s: %v: %w", img.Id, rerr, err)
+		}
+		if rerr := ir.release(ctx, img); rerr != nil {
+			return nil, fmt.Errorf("unable to release blob %s: %v: %w", img.Digest, rerr, err)
+		}
+		return nil, fmt.Errorf("%q: %w", "unable to delete image from storage", err)
+	}
+
+	return res, nil
+}
+
+// swap sets the blob, access and metadata of the entry to those of img, if
+// its digest is still digest, and returns the updated entry.
+func (ir *ImageRegistry) swap(ctx context.Context, id, digest string, img *imgrepo.Image) (*imgrepo.Image, error) {
+	var res imgrepo.Image
+	err := ir.col.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "digest": digestIs(digest)},
+		bson.M{"$set": bson.M{
+			"digest":     img.Digest,
+			"size":       img.Size,
+			"renditions": img.Renditions,
+			"access":     img.Access,
+			"metadata":   img.Metadata,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&res)
+	if err == mongo.ErrNoDocuments {
+		if _, err := ir.find(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("file %s changed since digest %s: %w", id, digest, imgrepo.ErrConflict)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to replace file", err)
+	}
+
+	return &res, nil
+}
+
+func (ir *ImageRegistry) Share(requester, id string, g imgrepo.Grant) (*imgrepo.Image, error) {
+	if g.Empty() {
+		return nil, fmt.Errorf("%q: %w", "no users or groups to share with", imgrepo.ErrInvalidArgument)
+	}
+	return ir.modify(requester, id, bson.M{"$addToSet": grantUpdate(g, true)})
+}
+
+func (ir *ImageRegistry) Unshare(requester, id string, g imgrepo.Grant) (*imgrepo.Image, error) {
+	if g.Empty() {
+		return nil, fmt.Errorf("%q: %w", "no users or groups to unshare", imgrepo.ErrInvalidArgument)
+	}
+	return ir.modify(requester, id, bson.M{"$pullAll": grantUpdate(g, false)})
+}
+
+// grantUpdate returns the fields of an $addToSet, if add is true, or of a
+// $pullAll, changing the lists of a grant. Empty lists are left out, since
+// both refuse null.
+func grantUpdate(g imgrepo.Grant, add bool) bson.M {
+	res := bson.M{}
+	for key, list := range map[string][]string{"readers": g.Readers, "groups": g.Groups} {
+		switch {
+		case len(list) == 0:
+		case add:
+			res[key] = bson.M{"$each": list}
+		default:
+			res[key] = list
+		}
+	}
+	return res
+}
+
+// Delete removes the entry, and the blob if no other entry references it.
+func (ir *ImageRegistry) Delete(requester, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	img, err := ir.find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if img.Owner != requester {
+		return fmt.Errorf("unable to delete file %s: %w", id, imgrepo.ErrPermissionDenied)
+	}
+
+	unlock := ir.lockBlob(blobId(img))
+	defer unlock()
+
+	// The entry is removed before the blob, and restored if the blob cannot
+	// be released, so that a failure never leaves an unreferenced blob behind.
+	// Only the entry found is removed, since its blob may have been replaced
+	// while waiting for the lock.
+	res, err := ir.col.DeleteOne(ctx, bson.M{"_id": id, "digest": digestIs(img.Digest)})
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to delete image from registry", err)
+	}
+	if res.DeletedCount == 0 {
+		if _, err := ir.find(ctx, id); err != nil {
+			return err
+		}
+		return fmt.Errorf("file %s changed while deleting: %w", id, imgrepo.ErrConflict)
+	}
+
+	err = ir.release(ctx, img)
+	if err != nil {
+		if _, rerr := ir.col.InsertOne(ctx, img); rerr != nil {
+			return fmt.Errorf("unable to restore entry %s: %v: %w", id, rerr, err)
+		}
+		return fmt.Errorf("%q: %w", "unable to delete image from storage", err)
+	}
+
+	return nil
+}
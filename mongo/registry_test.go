@@ -1,101 +1,133 @@
 package mongo
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/algao1/imgrepo"
+	"github.com/algao1/imgrepo/memory"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/joho/godotenv"
 )
 
-type mockImageStorage struct {
-	store map[string][]byte
-}
-
-func (m *mockImageStorage) Upload(img *imgrepo.Image) error {
-	m.store[img.Id] = img.Raw
-	return nil
-}
-
-func (m *mockImageStorage) Download(id string) ([]byte, error) {
-	data, ok := m.store[id]
-	if !ok {
-		return nil, fmt.Errorf("unable to find file")
-	}
-
-	return data, nil
-}
-
 func randomBytes(len int) []byte {
 	token := make([]byte, len)
 	rand.Read(token)
 	return token
 }
 
-func tmpImageRegistry(is imgrepo.ImageStorage) (*ImageRegistry, error) {
+func tmpImageRegistry(is imgrepo.ImageStorage, gs imgrepo.GroupService) (*ImageRegistry, error) {
 	err := godotenv.Load("../.env")
 	if err != nil {
 		panic(err)
 	}
 
-	return NewImageRegistry(is, os.Getenv("MONGO_URI"), os.Getenv("MONGO_DB"), "_test")
+	return NewImageRegistry(is, gs, os.Getenv("MONGO_URI"), os.Getenv("MONGO_DB"), "_test")
 }
 
 func TestImageUploadDownload(t *testing.T) {
 	tests := map[string]struct {
 		requester string
 		want      *imgrepo.Image
+		raw       []byte
 		expectErr bool
 	}{
 		"owner access public": {
 			requester: "test",
-			want:      &imgrepo.Image{Owner: "test", Access: imgrepo.Public, Raw: randomBytes(1000)},
+			want:      &imgrepo.Image{Owner: "test", Access: imgrepo.Public},
+			raw:       randomBytes(1000),
 			expectErr: false,
 		},
 		"owner access private": {
 			requester: "test",
-			want:      &imgrepo.Image{Owner: "test", Access: imgrepo.Private, Raw: randomBytes(1000)},
+			want:      &imgrepo.Image{Owner: "test", Access: imgrepo.Private},
+			raw:       randomBytes(1000),
 			expectErr: false,
 		},
 		"other access public": {
 			requester: "test2",
-			want:      &imgrepo.Image{Owner: "test", Access: imgrepo.Public, Raw: randomBytes(1000)},
+			want:      &imgrepo.Image{Owner: "test", Access: imgrepo.Public},
+			raw:       randomBytes(1000),
 			expectErr: false,
 		},
 		"other access private": {
 			requester: "test2",
-			want:      &imgrepo.Image{Owner: "test", Access: imgrepo.Private, Raw: randomBytes(1000)},
+			want:      &imgrepo.Image{Owner: "test", Access: imgrepo.Private},
+			raw:       randomBytes(1000),
 			expectErr: true,
 		},
+		"metadata": {
+			requester: "test",
+			want: &imgrepo.Image{Owner: "test", Access: imgrepo.Public, Metadata: imgrepo.Metadata{
+				MimeType:    "image/jpeg",
+				Width:       640,
+				Height:      480,
+				Taken:       time.Date(2021, 5, 17, 10, 30, 0, 0, time.UTC),
+				Make:        "Imgrepo",
+				Model:       "Camera 1",
+				Orientation: 6,
+				Location:    &imgrepo.Location{Latitude: 48.8583, Longitude: -122.42},
+			}},
+			raw:       randomBytes(1000),
+			expectErr: false,
+		},
 	}
 
-	ir, err := tmpImageRegistry(&mockImageStorage{store: make(map[string][]byte)})
+	ir, err := tmpImageRegistry(memory.NewImageStorage(), memory.NewGroupService())
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer ir.col.Drop(context.TODO())
+	defer ir.blobs.Drop(context.TODO())
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			err = ir.Upload(tc.want)
+			tc.want.Digest = digest(tc.raw)
+			err = ir.Upload(tc.want, bytes.NewReader(tc.raw))
 			if err != nil && !tc.expectErr {
 				t.Fatal(err)
 			}
 
-			got, err := ir.Download(tc.requester, tc.want.Id)
+			got, rc, err := ir.Download(tc.requester, tc.want.Id)
 			if err != nil && !tc.expectErr {
 				t.Fatal(err)
-			} else if diff := cmp.Diff(tc.want, got); !tc.expectErr && diff != "" {
+			} else if err != nil {
+				return
+			}
+			defer rc.Close()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Fatalf("Upload() and Download() mismatch (-want +got):\n%s", diff)
 			}
+
+			raw, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatal(err)
+			} else if !bytes.Equal(raw, tc.raw) {
+				t.Fatal("Upload() and Download() raw image mismatch")
+			}
 		})
 	}
 }
 
+// digest returns the hex encoded SHA-256 of raw.
+func digest(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
 func cmpSlices(s1, s2 []*imgrepo.Image) error {
 	if len(s1) != len(s2) {
 		return fmt.Errorf("slices have varying lengths")
@@ -112,18 +144,21 @@ func cmpSlices(s1, s2 []*imgrepo.Image) error {
 
 func TestListImages(t *testing.T) {
 	images := []*imgrepo.Image{
-		{Owner: "test", Access: imgrepo.Public},
+		{Owner: "test", Access: imgrepo.Public, Tags: []string{"fruit", "red"}},
 		{Owner: "test", Access: imgrepo.Private},
-		{Owner: "test2", Access: imgrepo.Public},
-		{Owner: "test2", Access: imgrepo.Private},
+		{Owner: "test2", Access: imgrepo.Public, Tags: []string{"fruit", "yellow"}},
+		{Owner: "test2", Access: imgrepo.Private, Tags: []string{"red"}},
 		{Owner: "test3", Access: imgrepo.Private},
 		{Owner: "test3", Access: imgrepo.Private},
+		{Owner: "test4", Access: imgrepo.Public, Tags: []string{"red"}},
 		{Owner: "test4", Access: imgrepo.Public},
-		{Owner: "test4", Access: imgrepo.Public},
+		{Owner: "test5", Access: imgrepo.Private, Grant: imgrepo.Grant{Readers: []string{"test6"}}},
+		{Owner: "test5", Access: imgrepo.Private, Grant: imgrepo.Grant{Groups: []string{"team"}}},
 	}
 
 	tests := map[string]struct {
 		requester string
+		filter    imgrepo.ListFilter
 		want      []int
 	}{
 		"owner: test": {
@@ -138,16 +173,79 @@ func TestListImages(t *testing.T) {
 			requester: "test3",
 			want:      []int{0, 2, 4, 5, 6, 7},
 		},
+		"reader": {
+			requester: "test6",
+			want:      []int{0, 2, 6, 7, 8},
+		},
+		"shared": {
+			requester: "test6",
+			filter:    imgrepo.ListFilter{Shared: true},
+			want:      []int{8},
+		},
+		"shared with tags": {
+			requester: "test6",
+			filter:    imgrepo.ListFilter{TagFilter: imgrepo.TagFilter{Any: []string{"red"}}, Shared: true},
+			want:      []int{},
+		},
+		"not shared": {
+			requester: "test2",
+			filter:    imgrepo.ListFilter{Shared: true},
+			want:      []int{},
+		},
+		"group member": {
+			requester: "test7",
+			want:      []int{0, 2, 6, 7, 9},
+		},
+		"shared with group": {
+			requester: "test7",
+			filter:    imgrepo.ListFilter{Shared: true},
+			want:      []int{9},
+		},
+		"shared with own group": {
+			requester: "test5",
+			filter:    imgrepo.ListFilter{Shared: true},
+			want:      []int{},
+		},
+		"any tag": {
+			requester: "test2",
+			filter:    imgrepo.ListFilter{TagFilter: imgrepo.TagFilter{Any: []string{"red", "yellow"}}},
+			want:      []int{0, 2, 3, 6},
+		},
+		"all tags": {
+			requester: "test2",
+			filter:    imgrepo.ListFilter{TagFilter: imgrepo.TagFilter{All: []string{"fruit", "red"}}},
+			want:      []int{0},
+		},
+		"any and all tags": {
+			requester: "test2",
+			filter:    imgrepo.ListFilter{TagFilter: imgrepo.TagFilter{Any: []string{"red", "yellow"}, All: []string{"fruit"}}},
+			want:      []int{0, 2},
+		},
+		"unknown tag": {
+			requester: "test2",
+			filter:    imgrepo.ListFilter{TagFilter: imgrepo.TagFilter{Any: []string{"green"}}},
+			want:      []int{},
+		},
 	}
 
-	ir, err := tmpImageRegistry(&mockImageStorage{store: make(map[string][]byte)})
+	gs := memory.NewGroupService()
+	if _, err := gs.Create("test5", "team"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.AddMembers("test5", "team", "test7"); err != nil {
+		t.Fatal(err)
+	}
+	ir, err := tmpImageRegistry(memory.NewImageStorage(), gs)
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer ir.col.Drop(context.TODO())
+	defer ir.blobs.Drop(context.TODO())
 
 	for idx := range images {
-		err := ir.Upload(images[len(images)-idx-1])
+		img := images[len(images)-idx-1]
+		img.Digest = digest(nil)
+		err := ir.Upload(img, bytes.NewReader(nil))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -155,7 +253,259 @@ func TestListImages(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			got, err := ir.List(20, tc.requester, "")
+			got, err := ir.List(20, tc.requester, "", tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := make([]*imgrepo.Image, len(tc.want))
+			for i, idx := range tc.want {
+				want[i] = images[idx]
+			}
+
+			if err := cmpSlices(want, got); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestDeleteImage(t *testing.T) {
+	tests := map[string]struct {
+		requester string
+		expectErr bool
+	}{
+		"owner": {requester: "test", expectErr: false},
+		"other": {requester: "test2", expectErr: true},
+	}
+
+	ir, err := tmpImageRegistry(memory.NewImageStorage(), memory.NewGroupService())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ir.col.Drop(context.TODO())
+	defer ir.blobs.Drop(context.TODO())
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw := randomBytes(100)
+			img := &imgrepo.Image{Owner: "test", Access: imgrepo.Public, Digest: digest(raw)}
+			if err := ir.Upload(img, bytes.NewReader(raw)); err != nil {
+				t.Fatal(err)
+			}
+
+			err := ir.Delete(tc.requester, img.Id)
+			if err != nil && !tc.expectErr {
+				t.Fatal(err)
+			} else if err == nil && tc.expectErr {
+				t.Fatal("expected error")
+			}
+
+			_, rc, err := ir.Download("test", img.Id)
+			if err == nil {
+				rc.Close()
+			}
+
+			if tc.expectErr && err != nil {
+				t.Fatalf("image lost after failed delete: %v", err)
+			} else if !tc.expectErr && err == nil {
+				t.Fatal("image still present after delete")
+			}
+		})
+	}
+}
+
+func TestDeduplication(t *testing.T) {
+	is := memory.NewImageStorage()
+	ir, err := tmpImageRegistry(is, memory.NewGroupService())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ir.col.Drop(context.TODO())
+	defer ir.blobs.Drop(context.TODO())
+
+	raw := randomBytes(1000)
+	first := &imgrepo.Image{Name: "a.png", Owner: "test", Access: imgrepo.Public, Digest: digest(raw)}
+	second := &imgrepo.Image{Name: "b.png", Owner: "test2", Access: imgrepo.Private, Digest: digest(raw)}
+
+	if err := ir.Upload(first, bytes.NewReader(raw)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The blob is already stored, so the second upload must not read it.
+	if err := ir.Upload(second, iotest.ErrReader(iotest.ErrTimeout)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ir.Delete("test", first.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	_, rc, err := ir.Download("test2", second.Id)
+	if err != nil {
+		t.Fatalf("blob lost while still referenced: %v", err)
+	}
+	rc.Close()
+
+	if err := ir.Delete("test2", second.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := is.Download(first.Digest); err == nil {
+		t.Fatal("blob still present after deleting every entry")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	loc := &imgrepo.Location{Latitude: 48.858333, Longitude: -122.42}
+
+	tests := map[string]struct {
+		requester string
+		stale     bool // the entry changed since its digest was read
+		shared    bool // another entry references the original blob
+		wantErr   error
+	}{
+		"owner":        {requester: "test"},
+		"shared blob":  {requester: "test", shared: true},
+		"other":        {requester: "test2", wantErr: imgrepo.ErrPermissionDenied},
+		"stale digest": {requester: "test", stale: true, wantErr: imgrepo.ErrConflict},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := memory.NewImageStorage()
+			ir, err := tmpImageRegistry(is, memory.NewGroupService())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ir.col.Drop(context.TODO())
+			defer ir.blobs.Drop(context.TODO())
+
+			raw := randomBytes(100)
+			img := &imgrepo.Image{Name: "a.png", Owner: "test", Access: imgrepo.Private, Digest: digest(raw), Metadata: imgrepo.Metadata{Location: loc}}
+			if err := ir.Upload(img, bytes.NewReader(raw)); err != nil {
+				t.Fatal(err)
+			}
+			if tc.shared {
+				other := &imgrepo.Image{Owner: "test2", Access: imgrepo.Private, Digest: digest(raw)}
+				if err := ir.Upload(other, bytes.NewReader(raw)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			old := img.Digest
+			if tc.stale {
+				old = digest(nil)
+			}
+
+			stripped := randomBytes(80)
+			repl := &imgrepo.Image{Id: img.Id, Access: imgrepo.Public, Digest: digest(stripped), Size: int64(len(stripped))}
+			got, err := ir.Replace(tc.requester, old, repl, bytes.NewReader(stripped), imgrepo.Rendition{Size: 128, Data: []byte("rendition")})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Replace() = _, %v, want %v", err, tc.wantErr)
+			}
+
+			// A failed replace leaves the entry, and its blob, as they were.
+			want, wantRaw := img, raw
+			if err == nil {
+				want = &imgrepo.Image{
+					Id:         img.Id,
+					Name:       "a.png",
+					Owner:      "test",
+					Access:     imgrepo.Public,
+					Digest:     repl.Digest,
+					Size:       repl.Size,
+					Renditions: []int{128},
+				}
+				wantRaw = stripped
+
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Fatalf("Replace() mismatch (-want +got):\n%s", diff)
+				}
+			}
+
+			dl, rc, err := ir.Download("test", img.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, dl); diff != "" {
+				t.Fatalf("Download() mismatch (-want +got):\n%s", diff)
+			}
+			if !bytes.Equal(data, wantRaw) {
+				t.Fatal("Replace() and Download() raw image mismatch")
+			}
+
+			// The original blob is only deleted once nothing references it.
+			rc, serr := is.Download(img.Digest)
+			if serr == nil {
+				rc.Close()
+			}
+			if kept := serr == nil; kept != (tc.wantErr != nil || tc.shared) {
+				t.Errorf("original blob kept = %v, want %v", kept, tc.wantErr != nil || tc.shared)
+			}
+		})
+	}
+}
+
+func TestBlobLocks(t *testing.T) {
+	ir := &ImageRegistry{locks: make(map[string]*blobLock)}
+
+	// Concurrent callers wait on the same lock, which is dropped once the
+	// last one releases it.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := ir.lockBlobs("a", "b")
+			unlock()
+		}()
+	}
+	wg.Wait()
+
+	if n := len(ir.locks); n != 0 {
+		t.Fatalf("%d blob locks left after every lock was released", n)
+	}
+}
+
+func TestFindImages(t *testing.T) {
+	ir, err := tmpImageRegistry(memory.NewImageStorage(), memory.NewGroupService())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ir.col.Drop(context.TODO())
+	defer ir.blobs.Drop(context.TODO())
+
+	images := []*imgrepo.Image{
+		{Owner: "test", Access: imgrepo.Public},
+		{Owner: "test", Access: imgrepo.Private},
+		{Owner: "test2", Access: imgrepo.Public},
+	}
+	for _, img := range images {
+		img.Digest = digest(nil)
+		if err := ir.Upload(img, bytes.NewReader(nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		requester string
+		ids       []string
+		want      []int
+	}{
+		"owner":   {requester: "test", ids: []string{images[2].Id, images[1].Id, images[0].Id}, want: []int{2, 1, 0}},
+		"other":   {requester: "test2", ids: []string{images[0].Id, images[1].Id, images[2].Id}, want: []int{0, 2}},
+		"missing": {requester: "test", ids: []string{"missing", images[0].Id}, want: []int{0}},
+		"none":    {requester: "test", ids: nil, want: nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ir.Find(tc.requester, tc.ids...)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -170,4 +520,405 @@ func TestListImages(t *testing.T) {
 			}
 		})
 	}
+
+	var walked int
+	err = ir.Walk(func(img *imgrepo.Image) error {
+		walked++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if walked != len(images) {
+		t.Fatalf("Walk() visited %d images, want %d", walked, len(images))
+	}
+
+	// Stat ignores access, so the private image is found all the same.
+	got, err := ir.Stat(images[1].Id)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := cmpSlices(images[1:2], []*imgrepo.Image{got}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ir.Stat("missing"); !errors.Is(err, imgrepo.ErrNotFound) {
+		t.Fatalf("Stat() = _, %v, want %v", err, imgrepo.ErrNotFound)
+	}
+}
+
+func TestRenditions(t *testing.T) {
+	is := memory.NewImageStorage()
+	ir, err := tmpImageRegistry(is, memory.NewGroupService())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ir.col.Drop(context.TODO())
+	defer ir.blobs.Drop(context.TODO())
+
+	raw := randomBytes(1000)
+	first := &imgrepo.Image{Name: "a.png", Owner: "test", Access: imgrepo.Private, Digest: digest(raw)}
+	second := &imgrepo.Image{Name: "b.png", Owner: "test", Access: imgrepo.Private, Digest: digest(raw)}
+
+	small, large := randomBytes(10), randomBytes(100)
+	if err := ir.Upload(first, bytes.NewReader(raw), imgrepo.Rendition{Size: 512, Data: large}); err != nil {
+		t.Fatal(err)
+	}
+	// Renditions the blob already has are not stored again.
+	if err := ir.Upload(second, bytes.NewReader(raw),
+		imgrepo.Rendition{Size: 128, Data: small},
+		imgrepo.Rendition{Size: 512, Data: randomBytes(100)},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]int{128, 512}, second.Renditions); diff != "" {
+		t.Fatalf("Upload() renditions mismatch (-want +got):\n%s", diff)
+	}
+
+	tests := map[string]struct {
+		requester string
+		id        string
+		size      int
+		want      []byte
+		wantErr   error
+	}{
+		"rendition":     {requester: "test", id: second.Id, size: 512, want: large},
+		"small":         {requester: "test", id: second.Id, size: 128, want: small},
+		"missing size":  {requester: "test", id: first.Id, size: 128, wantErr: imgrepo.ErrNotFound},
+		"invalid size":  {requester: "test", id: first.Id, size: 0, wantErr: imgrepo.ErrInvalidArgument},
+		"private":       {requester: "test2", id: first.Id, size: 512, wantErr: imgrepo.ErrPermissionDenied},
+		"missing image": {requester: "test", id: "missing", size: 512, wantErr: imgrepo.ErrNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, rc, err := ir.Rendition(tc.requester, tc.id, tc.size)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			} else if !bytes.Equal(data, tc.want) {
+				t.Fatal("Upload() and Rendition() data mismatch")
+			}
+		})
+	}
+
+	for _, img := range []*imgrepo.Image{first, second} {
+		if err := ir.Delete("test", img.Id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, id := range []string{first.Digest, imgrepo.RenditionId(first.Digest, 128), imgrepo.RenditionId(first.Digest, 512)} {
+		if _, err := is.Download(id); err == nil {
+			t.Errorf("blob %s not deleted", id)
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	taken := time.Date(2021, 5, 17, 10, 30, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		requester string
+		id        string
+		update    imgrepo.Image
+		fields    []imgrepo.Field
+		want      func(img *imgrepo.Image)
+		wantErr   error
+	}{
+		"rename": {
+			requester: "test",
+			update:    imgrepo.Image{Name: "renamed.jpg", Access: imgrepo.Public},
+			fields:    []imgrepo.Field{imgrepo.NameField},
+			want:      func(img *imgrepo.Image) { img.Name = "renamed.jpg" },
+		},
+		"make public": {
+			requester: "test",
+			update:    imgrepo.Image{Access: imgrepo.Public},
+			fields:    []imgrepo.Field{imgrepo.AccessField},
+			want:      func(img *imgrepo.Image) { img.Access = imgrepo.Public },
+		},
+		"edit metadata": {
+			requester: "test",
+			update:    imgrepo.Image{Metadata: imgrepo.Metadata{Taken: taken.Add(time.Hour)}},
+			fields:    []imgrepo.Field{imgrepo.TakenField, imgrepo.LocationField},
+			want: func(img *imgrepo.Image) {
+				img.Metadata.Taken = taken.Add(time.Hour)
+				img.Metadata.Location = nil
+			},
+		},
+		"retag": {
+			requester: "test",
+			update:    imgrepo.Image{Tags: []string{"fruit", "red"}},
+			fields:    []imgrepo.Field{imgrepo.TagsField},
+			want:      func(img *imgrepo.Image) { img.Tags = []string{"fruit", "red"} },
+		},
+		"other": {
+			requester: "test2",
+			update:    imgrepo.Image{Name: "renamed.jpg"},
+			fields:    []imgrepo.Field{imgrepo.NameField},
+			wantErr:   imgrepo.ErrPermissionDenied,
+		},
+		"missing": {
+			requester: "test",
+			id:        "missing",
+			fields:    []imgrepo.Field{imgrepo.NameField},
+			wantErr:   imgrepo.ErrNotFound,
+		},
+		"no fields": {
+			requester: "test",
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+		"immutable field": {
+			requester: "test",
+			update:    imgrepo.Image{Owner: "test2"},
+			fields:    []imgrepo.Field{"owner"},
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+	}
+
+	ir, err := tmpImageRegistry(memory.NewImageStorage(), memory.NewGroupService())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ir.col.Drop(context.TODO())
+	defer ir.blobs.Drop(context.TODO())
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw := randomBytes(100)
+			img := &imgrepo.Image{
+				Name:   "image.jpg",
+				Owner:  "test",
+				Access: imgrepo.Private,
+				Digest: digest(raw),
+				Metadata: imgrepo.Metadata{
+					MimeType: "image/jpeg",
+					Taken:    taken,
+					Location: &imgrepo.Location{Latitude: 48.858333, Longitude: -122.42},
+				},
+			}
+			if err := ir.Upload(img, bytes.NewReader(raw)); err != nil {
+				t.Fatal(err)
+			}
+
+			upd := tc.update
+			upd.Id = img.Id
+			if tc.id != "" {
+				upd.Id = tc.id
+			}
+
+			got, err := ir.Update(tc.requester, &upd, tc.fields...)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Update() = _, %v, want %v", err, tc.wantErr)
+			}
+
+			want := *img
+			if tc.want != nil {
+				tc.want(&want)
+			}
+			if err == nil {
+				if diff := cmp.Diff(&want, got); diff != "" {
+					t.Errorf("Update() mismatch (-want +got):\n%s", diff)
+				}
+			}
+
+			// Failed updates leave the entry unchanged.
+			stored, err := ir.Find("test", img.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]*imgrepo.Image{&want}, stored); diff != "" {
+				t.Errorf("Find() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShare(t *testing.T) {
+	tests := map[string]struct {
+		requester string
+		id        string
+		unshare   bool
+		grant     imgrepo.Grant
+		want      imgrepo.Grant
+		wantErr   error
+	}{
+		"share": {
+			requester: "test",
+			grant:     imgrepo.Grant{Readers: []string{"test3"}},
+			want:      imgrepo.Grant{Readers: []string{"test2", "test3"}},
+		},
+		"share again": {
+			requester: "test",
+			grant:     imgrepo.Grant{Readers: []string{"test2", "test3", "test3"}},
+			want:      imgrepo.Grant{Readers: []string{"test2", "test3"}},
+		},
+		"share with group": {
+			requester: "test",
+			grant:     imgrepo.Grant{Groups: []string{"team"}},
+			want:      imgrepo.Grant{Readers: []string{"test2"}, Groups: []string{"team"}},
+		},
+		"unshare": {
+			requester: "test",
+			unshare:   true,
+			grant:     imgrepo.Grant{Readers: []string{"test2", "test3"}},
+		},
+		"unshare group": {
+			requester: "test",
+			unshare:   true,
+			grant:     imgrepo.Grant{Groups: []string{"team"}},
+			want:      imgrepo.Grant{Readers: []string{"test2"}},
+		},
+		"other": {
+			requester: "test2",
+			grant:     imgrepo.Grant{Readers: []string{"test3"}},
+			want:      imgrepo.Grant{Readers: []string{"test2"}},
+			wantErr:   imgrepo.ErrPermissionDenied,
+		},
+		"missing": {
+			requester: "test",
+			id:        "missing",
+			grant:     imgrepo.Grant{Readers: []string{"test3"}},
+			want:      imgrepo.Grant{Readers: []string{"test2"}},
+			wantErr:   imgrepo.ErrNotFound,
+		},
+		"no users": {
+			requester: "test",
+			want:      imgrepo.Grant{Readers: []string{"test2"}},
+			wantErr:   imgrepo.ErrInvalidArgument,
+		},
+	}
+
+	gs := memory.NewGroupService()
+	if _, err := gs.Create("test3", "team"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.AddMembers("test3", "team", "test4"); err != nil {
+		t.Fatal(err)
+	}
+
+	ir, err := tmpImageRegistry(memory.NewImageStorage(), gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ir.col.Drop(context.TODO())
+	defer ir.blobs.Drop(context.TODO())
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw := randomBytes(100)
+			img := &imgrepo.Image{Owner: "test", Access: imgrepo.Private, Digest: digest(raw), Grant: imgrepo.Grant{Readers: []string{"test2"}}}
+			if err := ir.Upload(img, bytes.NewReader(raw)); err != nil {
+				t.Fatal(err)
+			}
+
+			id := img.Id
+			if tc.id != "" {
+				id = tc.id
+			}
+
+			share := ir.Share
+			if tc.unshare {
+				share = ir.Unshare
+			}
+			if _, err := share(tc.requester, id, tc.grant); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Share() = _, %v, want %v", err, tc.wantErr)
+			}
+
+			stored, err := ir.Find("test", img.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, stored[0].Grant, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Share() grant mismatch (-want +got):\n%s", diff)
+			}
+
+			// Only the readers, and the members of the groups, may download
+			// the private image.
+			for _, user := range []string{"test2", "test3", "test4"} {
+				_, rc, err := ir.Download(user, img.Id)
+				if err == nil {
+					rc.Close()
+				}
+
+				groups, _ := gs.Groups(user)
+				if viewable := tc.want.Includes(user, groups); (err == nil) != viewable {
+					t.Errorf("Download(%q) = _, _, %v, want viewable %v", user, err, viewable)
+				}
+			}
+		})
+	}
+}
+
+// flakyStorage fails to delete the object with the given id once.
+type flakyStorage struct {
+	imgrepo.ImageStorage
+	failId string
+}
+
+func (fs *flakyStorage) Delete(id string) error {
+	if id == fs.failId {
+		fs.failId = ""
+		return errors.New("storage unavailable")
+	}
+	return fs.ImageStorage.Delete(id)
+}
+
+func TestDeleteRetry(t *testing.T) {
+	raw := randomBytes(1000)
+
+	tests := map[string]struct {
+		failId string
+	}{
+		"rendition": {failId: imgrepo.RenditionId(digest(raw), 512)},
+		"blob":      {failId: digest(raw)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			is := &flakyStorage{ImageStorage: memory.NewImageStorage()}
+			ir, err := tmpImageRegistry(is, memory.NewGroupService())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ir.col.Drop(context.TODO())
+			defer ir.blobs.Drop(context.TODO())
+
+			img := &imgrepo.Image{Owner: "test", Access: imgrepo.Private, Digest: digest(raw)}
+			err = ir.Upload(img, bytes.NewReader(raw),
+				imgrepo.Rendition{Size: 128, Data: randomBytes(10)},
+				imgrepo.Rendition{Size: 512, Data: randomBytes(100)},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The first attempt fails partway, after deleting some objects.
+			is.failId = tc.failId
+			if err := ir.Delete("test", img.Id); err == nil {
+				t.Fatal("Delete() = nil, want error")
+			}
+			if _, err := ir.Stat(img.Id); err != nil {
+				t.Fatalf("Stat() after failed Delete() = _, %v", err)
+			}
+
+			if err := ir.Delete("test", img.Id); err != nil {
+				t.Fatalf("Delete() retry = %v", err)
+			}
+			if _, err := ir.Stat(img.Id); !errors.Is(err, imgrepo.ErrNotFound) {
+				t.Fatalf("Stat() after Delete() = _, %v, want %v", err, imgrepo.ErrNotFound)
+			}
+			for _, id := range []string{digest(raw), imgrepo.RenditionId(digest(raw), 128), imgrepo.RenditionId(digest(raw), 512)} {
+				if _, err := is.Download(id); !errors.Is(err, imgrepo.ErrNotFound) {
+					t.Errorf("Download(%s) after Delete() = _, %v, want %v", id, err, imgrepo.ErrNotFound)
+				}
+			}
+		})
+	}
 }
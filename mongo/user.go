@@ -18,13 +18,17 @@ type UserService struct {
 
 var _ imgrepo.UserService = (*UserService)(nil)
 
+// Credentials is the stored account, where accounts registered before roles
+// existed have the user role, and are enabled.
 type Credentials struct {
 	Username string
 	Password []byte
+	Role     imgrepo.Role
+	Disabled bool
 }
 
 func connect(ctx context.Context, uri string) (*mongo.Client, error) {
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetRegistry(_Registry))
 	if err != nil {
 		return nil, fmt.Errorf("%q: %w", "unable to connect to collection", err)
 	}
@@ -49,11 +53,15 @@ func (us *UserService) Register(user, password string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if user == "" || password == "" {
+		return fmt.Errorf("username and password must be non-empty: %w", imgrepo.ErrInvalidArgument)
+	}
+
 	var cred Credentials
 
 	err := us.col.FindOne(ctx, bson.M{"username": user}).Decode(&cred)
 	if err == nil {
-		return fmt.Errorf("username already exists: %s", user)
+		return fmt.Errorf("username %s: %w", user, imgrepo.ErrAlreadyExists)
 	} else if err != mongo.ErrNoDocuments {
 		return fmt.Errorf("%q: %w", "unexpected error", err)
 	}
@@ -79,14 +87,103 @@ func (us *UserService) Login(user, password string) error {
 
 	err := us.col.FindOne(ctx, bson.M{"username": user}).Decode(&cred)
 	if err == mongo.ErrNoDocuments {
-		return fmt.Errorf("incorrect username or password")
+		return fmt.Errorf("incorrect username or password: %w", imgrepo.ErrUnauthenticated)
 	} else if err != nil {
 		return fmt.Errorf("%q: %w", "unexpected error", err)
 	}
 
 	err = bcrypt.CompareHashAndPassword(cred.Password, []byte(password))
 	if err != nil {
-		return fmt.Errorf("incorrect username or password")
+		return fmt.Errorf("incorrect username or password: %w", imgrepo.ErrUnauthenticated)
+	}
+
+	// The password is checked first, so that only the owner of the account
+	// learns that it is disabled.
+	if cred.Disabled {
+		return fmt.Errorf("account %s is disabled: %w", user, imgrepo.ErrPermissionDenied)
+	}
+
+	return nil
+}
+
+func (us *UserService) Exists(user string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	n, err := us.col.CountDocuments(ctx, bson.M{"username": user}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("%q: %w", "unexpected error", err)
+	}
+
+	return n > 0, nil
+}
+
+func (us *UserService) Account(user string) (*imgrepo.Account, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var cred Credentials
+
+	err := us.col.FindOne(ctx, bson.M{"username": user}).Decode(&cred)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("user %s: %w", user, imgrepo.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unexpected error", err)
+	}
+
+	return &imgrepo.Account{Username: cred.Username, Role: cred.Role, Disabled: cred.Disabled}, nil
+}
+
+func (us *UserService) Accounts() ([]*imgrepo.Account, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := us.col.Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "username", Value: 1}}).SetProjection(bson.M{"password": 0}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "cursor not found", err)
+	}
+	defer cursor.Close(ctx)
+
+	var res []*imgrepo.Account
+	for cursor.Next(ctx) {
+		var cred Credentials
+		if err := cursor.Decode(&cred); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+		}
+		res = append(res, &imgrepo.Account{Username: cred.Username, Role: cred.Role, Disabled: cred.Disabled})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to complete query", err)
+	}
+
+	return res, nil
+}
+
+func (us *UserService) SetRole(user string, role imgrepo.Role) error {
+	if role < imgrepo.UserRole || role > imgrepo.AdminRole {
+		return fmt.Errorf("unknown role %d: %w", role, imgrepo.ErrInvalidArgument)
+	}
+
+	return us.update(user, bson.M{"role": role})
+}
+
+func (us *UserService) SetDisabled(user string, disabled bool) error {
+	return us.update(user, bson.M{"disabled": disabled})
+}
+
+// update sets the fields of the account of the user.
+func (us *UserService) update(user string, fields bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := us.col.UpdateOne(ctx, bson.M{"username": user}, bson.M{"$set": fields})
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to update account", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("user %s: %w", user, imgrepo.ErrNotFound)
 	}
 
 	return nil
@@ -2,9 +2,12 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
+	"github.com/algao1/imgrepo"
+	"github.com/google/go-cmp/cmp"
 	"github.com/joho/godotenv"
 )
 
@@ -79,3 +82,126 @@ func TestLoginUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUserExists(t *testing.T) {
+	tests := map[string]struct {
+		username string
+		want     bool
+	}{
+		"registered":   {username: "admin", want: true},
+		"unregistered": {username: "admin2", want: false},
+	}
+
+	us, err := tmpUserService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer us.col.Drop(context.TODO())
+
+	// Setup existing user account.
+	us.Register("admin", "password")
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := us.Exists(tc.username)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("Exists() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetAccount(t *testing.T) {
+	tests := map[string]struct {
+		username     string
+		role         imgrepo.Role
+		disabled     bool
+		want         *imgrepo.Account
+		wantErr      error
+		wantLoginErr error
+	}{
+		"promote": {
+			username: "admin",
+			role:     imgrepo.AdminRole,
+			want:     &imgrepo.Account{Username: "admin", Role: imgrepo.AdminRole},
+		},
+		"disable": {
+			username:     "admin",
+			disabled:     true,
+			want:         &imgrepo.Account{Username: "admin", Disabled: true},
+			wantLoginErr: imgrepo.ErrPermissionDenied,
+		},
+		"unknown role": {username: "admin", role: 42, wantErr: imgrepo.ErrInvalidArgument},
+		"missing user": {username: "admin2", wantErr: imgrepo.ErrNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			us, err := tmpUserService()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer us.col.Drop(context.TODO())
+
+			// Setup existing user account.
+			us.Register("admin", "password")
+
+			err = us.SetRole(tc.username, tc.role)
+			if err == nil {
+				err = us.SetDisabled(tc.username, tc.disabled)
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("SetRole() and SetDisabled() = %v, want %v", err, tc.wantErr)
+			} else if err != nil {
+				return
+			}
+
+			got, err := us.Account(tc.username)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Account() mismatch (-want +got):\n%s", diff)
+			}
+
+			if err := us.Login(tc.username, "password"); !errors.Is(err, tc.wantLoginErr) {
+				t.Errorf("Login() = %v, want %v", err, tc.wantLoginErr)
+			}
+		})
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	us, err := tmpUserService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer us.col.Drop(context.TODO())
+
+	// Setup existing user accounts.
+	us.Register("mod", "password")
+	us.Register("admin", "password")
+	if err := us.SetRole("mod", imgrepo.ModeratorRole); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := us.Accounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*imgrepo.Account{
+		{Username: "admin"},
+		{Username: "mod", Role: imgrepo.ModeratorRole},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Accounts() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := us.Account("missing"); !errors.Is(err, imgrepo.ErrNotFound) {
+		t.Errorf("Account() = _, %v, want %v", err, imgrepo.ErrNotFound)
+	}
+}
@@ -2,30 +2,65 @@ package proto
 
 import (
 	context "context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
-	"log"
 	sync "sync"
 	"time"
 
 	"github.com/algao1/imgrepo"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 const _ChunkSize = 128 * 1024
 const _PageSize = 10
 
+// _StreamTimeout bounds uploads and downloads, which can take much longer
+// than the other calls for large images.
+const _StreamTimeout = 30 * time.Minute
+
+// _MaxAttempts and _RetryDelay determine how often, and after how long,
+// an upload interrupted by a transient error is resumed.
+const (
+	_MaxAttempts = 5
+	_RetryDelay  = time.Second
+)
+
 type ImageRepoClient struct {
 	Owner string
 	Token string
 
 	client RepoClient
+	admin  AdminClient
 	mu     sync.RWMutex
 }
 
 var _ imgrepo.ImageClient = (*ImageRepoClient)(nil)
+var _ imgrepo.AdminClient = (*ImageRepoClient)(nil)
+
+func NewImageRepoClient(client RepoClient, admin AdminClient) *ImageRepoClient {
+	return &ImageRepoClient{client: client, admin: admin}
+}
+
+// tokenAuth attaches a session token to each RPC as a bearer token.
+type tokenAuth struct {
+	token string
+}
 
-func NewImageRepoClient(client RepoClient) *ImageRepoClient {
-	return &ImageRepoClient{client: client}
+func (t tokenAuth) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (tokenAuth) RequireTransportSecurity() bool {
+	return false
+}
+
+// auth returns a call option carrying the session token, irc.mu must be held.
+func (irc *ImageRepoClient) auth() grpc.CallOption {
+	return grpc.PerRPCCredentials(tokenAuth{token: irc.Token})
 }
 
 func (irc *ImageRepoClient) Register(username, password string) error {
@@ -39,7 +74,7 @@ func (irc *ImageRepoClient) Register(username, password string) error {
 
 	_, err := irc.client.Register(ctx, req)
 	if err != nil {
-		return fmt.Errorf("%v.Register(_) = _, %v: ", irc.client, err)
+		return newError("Register", err)
 	}
 
 	return nil
@@ -56,7 +91,7 @@ func (irc *ImageRepoClient) Login(username, password string) error {
 
 	resp, err := irc.client.Login(ctx, req)
 	if err != nil {
-		return fmt.Errorf("%v.Login(_) = _, %v: ", irc.client, err)
+		return newError("Login", err)
 	}
 
 	irc.Owner = username
@@ -65,134 +100,957 @@ func (irc *ImageRepoClient) Login(username, password string) error {
 	return nil
 }
 
-func (irc *ImageRepoClient) Upload(image *imgrepo.Image) error {
+// Upload uploads the image from r with a resumable upload. If the upload is
+// interrupted by a transient error, it is resumed from the offset committed
+// by the server. The returned error mentions the upload id, which can be
+// passed to ResumeUpload to finish the upload later.
+//
+// The digest of r is declared to the server, which rejects the upload if
+// the data it received has a different digest.
+//
+// The privacy policy decides what metadata the server strips from the image.
+//
+// Returns the ids of the likely duplicates of the image owned by the user.
+// If the policy skips duplicates and there are any, image.Id is left empty.
+func (irc *ImageRepoClient) Upload(image *imgrepo.Image, r io.ReadSeeker, policy imgrepo.DuplicatePolicy, privacy imgrepo.Privacy) ([]string, error) {
+	digest, err := digestOf(r)
+	if err != nil {
+		return nil, err
+	}
+	image.Digest = digest
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	irc.mu.RLock()
 	defer irc.mu.RUnlock()
 
-	stream, err := irc.client.UploadImage(ctx)
+	req := &BeginUploadRequest{
+		FileInfo: &FileInfo{
+			FileName: image.Name,
+			Access:   int32(image.Access),
+			Digest:   image.Digest,
+			Tags:     image.Tags,
+		},
+	}
+
+	resp, err := irc.client.BeginUpload(ctx, req, irc.auth())
 	if err != nil {
-		log.Fatalf("%v.UploadImage(_) = _, %v", irc.client, err)
+		return nil, newError("BeginUpload", err)
 	}
 
-	finfo := Upload{
-		Event: &Upload_Info{
-			Info: &Upload_UploadInfo{
-				Token: irc.Token,
-				FileInfo: &FileInfo{
-					FileName: image.Name,
-					Owner:    image.Owner,
-					Access:   int32(image.Access),
-				},
-			},
-		},
+	uresp, err := irc.resumeUpload(resp.UploadId, r, policy, privacy)
+	if err != nil {
+		return nil, err
+	}
+	image.Id = uresp.Id
+
+	return uresp.Duplicates, nil
+}
+
+// digestOf returns the hex encoded SHA-256 of r, and rewinds r.
+func digestOf(r io.ReadSeeker) (string, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("%q: %w", "unable to seek image", err)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", fmt.Errorf("%q: %w", "unable to read image", err)
 	}
 
-	if err := stream.Send(&finfo); err != nil {
-		log.Fatalf("%v.Send(%v) = %v", stream, image.Name, err)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("%q: %w", "unable to seek image", err)
 	}
 
-	for cByte := 0; cByte < len(image.Raw); cByte += _ChunkSize {
-		var chunk []byte
-		if cByte+_ChunkSize > len(image.Raw) {
-			chunk = image.Raw[cByte:]
-		} else {
-			chunk = image.Raw[cByte : cByte+_ChunkSize]
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ResumeUpload continues the upload with the given id, which may have been
+// started by another client, from the offset committed by the server.
+// Returns the ids of the likely duplicates, like Upload.
+func (irc *ImageRepoClient) ResumeUpload(uploadId string, r io.ReadSeeker, policy imgrepo.DuplicatePolicy, privacy imgrepo.Privacy) ([]string, error) {
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	resp, err := irc.resumeUpload(uploadId, r, policy, privacy)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Duplicates, nil
+}
+
+// resumeUpload writes r to the upload, retrying after transient errors,
+// and commits it.
+func (irc *ImageRepoClient) resumeUpload(uploadId string, r io.ReadSeeker, policy imgrepo.DuplicatePolicy, privacy imgrepo.Privacy) (*UploadResponse, error) {
+	var err error
+	for attempt := 0; attempt < _MaxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * _RetryDelay)
 		}
 
-		uchunk := Upload{
-			Event: &Upload_Chunk_{
-				Chunk: &Upload_Chunk{
-					Chunk: chunk,
-				},
-			},
+		var offset int64
+		offset, err = irc.queryUpload(uploadId)
+		if err == nil {
+			err = irc.writeUpload(uploadId, offset, r)
+		}
+		if err == nil {
+			break
+		}
+		if !isTransient(err) {
+			return nil, fmt.Errorf("upload %s: %w", uploadId, err)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("upload %s: %w", uploadId, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _StreamTimeout)
+	defer cancel()
+
+	req := &CommitUploadRequest{
+		UploadId:    uploadId,
+		OnDuplicate: DuplicatePolicy(policy),
+		Privacy:     Privacy(privacy),
+	}
+
+	resp, err := irc.client.CommitUpload(ctx, req, irc.auth())
+	if err != nil {
+		return nil, fmt.Errorf("upload %s: %w", uploadId, newError("CommitUpload", err))
+	}
+
+	return resp, nil
+}
+
+// queryUpload returns the committed offset of the upload.
+func (irc *ImageRepoClient) queryUpload(uploadId string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := irc.client.QueryUpload(ctx, &QueryUploadRequest{UploadId: uploadId}, irc.auth())
+	if err != nil {
+		return 0, newError("QueryUpload", err)
+	}
+
+	return resp.CommittedOffset, nil
+}
+
+// writeUpload streams r to the upload in chunks, starting at offset.
+func (irc *ImageRepoClient) writeUpload(uploadId string, offset int64, r io.ReadSeeker) error {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("%q: %w", "unable to seek image", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _StreamTimeout)
+	defer cancel()
+
+	stream, err := irc.client.WriteUpload(ctx, irc.auth())
+	if err != nil {
+		return newError("WriteUpload", err)
+	}
+
+	// Send returns io.EOF when the server aborts the stream, in which case the
+	// error is reported by CloseAndRecv.
+	buf := make([]byte, _ChunkSize)
+	for sent := false; err == nil; sent = true {
+		n, rerr := io.ReadFull(r, buf)
+		if rerr == io.EOF && sent {
+			break
+		} else if rerr != nil && rerr != io.EOF && rerr != io.ErrUnexpectedEOF {
+			return fmt.Errorf("%q: %w", "unable to read image", rerr)
 		}
 
-		if err := stream.Send(&uchunk); err != nil {
-			return fmt.Errorf("%v.Send(%v) = %v", stream, image.Name, err)
+		// The first chunk is sent even if empty, so that the server knows
+		// which upload the stream belongs to.
+		err = stream.Send(&UploadChunk{UploadId: uploadId, Offset: offset, Data: buf[:n]})
+		offset += int64(n)
+
+		if rerr != nil {
+			break
 		}
 	}
+	if err != nil && err != io.EOF {
+		return newError("WriteUpload", err)
+	}
 
 	_, err = stream.CloseAndRecv()
 	if err != nil {
-		return fmt.Errorf("%v.CloseAndRecv() got error %v, want %v", stream, err, nil)
+		return newError("WriteUpload", err)
 	}
 
 	return nil
 }
 
-func (irc *ImageRepoClient) Download(id string) (*imgrepo.Image, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Download streams the image with the given id into w, and returns its
+// file info. Once the stream ends, the digest of the received data is
+// checked against the one in the file info, and imgrepo.ErrDigestMismatch
+// is returned if they differ.
+func (irc *ImageRepoClient) Download(id string, rendition int, w io.Writer) (*imgrepo.Image, error) {
+	return irc.download(&DownloadRequest{Id: id, Rendition: int32(rendition)}, w)
+}
+
+// _TransformFormats maps the formats of transforms to their enum values.
+var _TransformFormats = map[string]Transform_Format{
+	"":     Transform_ORIGINAL,
+	"jpeg": Transform_JPEG,
+	"png":  Transform_PNG,
+	"webp": Transform_WEBP,
+	"gif":  Transform_GIF,
+}
+
+// Transform downloads the image converted by t on the server.
+func (irc *ImageRepoClient) Transform(id string, t imgrepo.Transform, w io.Writer) (*imgrepo.Image, error) {
+	format, ok := _TransformFormats[t.Format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q: %w", t.Format, imgrepo.ErrInvalidArgument)
+	}
+
+	return irc.download(&DownloadRequest{
+		Id: id,
+		Transform: &Transform{
+			Width:   int32(t.Width),
+			Height:  int32(t.Height),
+			Fit:     Transform_Fit(t.Fit),
+			Format:  format,
+			Quality: int32(t.Quality),
+		},
+	}, w)
+}
+
+// download streams the file requested to w, and checks its digest if it is
+// the original.
+func (irc *ImageRepoClient) download(req *DownloadRequest, w io.Writer) (*imgrepo.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _StreamTimeout)
 	defer cancel()
 
 	irc.mu.RLock()
 	defer irc.mu.RUnlock()
 
-	req := &DownloadRequest{
-		Token:  irc.Token,
-		Sender: irc.Owner,
-		Id:     id,
+	stream, err := irc.client.DownloadImage(ctx, req, irc.auth())
+	if err != nil {
+		return nil, newError("DownloadImage", err)
 	}
 
-	stream, err := irc.client.DownloadImage(ctx, req)
+	// The digest is the one of the original, not of renditions or transforms.
+	return receive("DownloadImage", stream, req.Rendition == 0 && req.Transform == nil, w)
+}
+
+// DownloadShared streams the image of the share link with the given token,
+// or its rendition of the given size, into w, like Download. No session is
+// needed, and each call counts as a download of the link.
+func (irc *ImageRepoClient) DownloadShared(token string, rendition int, w io.Writer) (*imgrepo.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _StreamTimeout)
+	defer cancel()
+
+	req := &DownloadSharedRequest{Token: token, Rendition: int32(rendition)}
+
+	stream, err := irc.client.DownloadShared(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("%v.DownloadImage(_) = _, %v", irc.client, err)
+		return nil, newError("DownloadShared", err)
 	}
 
+	return receive("DownloadShared", stream, rendition == 0, w)
+}
+
+// receive writes the file streamed by the RPC method to w, and checks its
+// digest if it is the original.
+func receive(method string, stream Repo_DownloadImageClient, original bool, w io.Writer) (*imgrepo.Image, error) {
 	img := imgrepo.Image{}
+	h := sha256.New()
 
 	for {
 		dl, err := stream.Recv()
 		if err == io.EOF {
-			return &img, nil
+			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("%v.DownloadImage(_) = _, %v", irc.client, err)
+			return nil, newError(method, err)
 		}
 
 		// Handles the 2 types of events (UploadInfo & Chunk).
 		switch dl.GetEvent().(type) {
 		case *Download_FileInfo:
-			finfo := dl.GetFileInfo()
-
-			img.Id = finfo.Id
-			img.Name = finfo.FileName
-			img.Owner = finfo.Owner
-			img.Access = imgrepo.Permission(finfo.Access)
+			img = *toImage(dl.GetFileInfo())
 
 		case *Download_Chunk:
-			img.Raw = append(img.Raw, dl.GetChunk()...)
+			h.Write(dl.GetChunk())
+			if _, err := w.Write(dl.GetChunk()); err != nil {
+				return nil, fmt.Errorf("%q: %w", "unable to write image", err)
+			}
 		}
 	}
+
+	// Images uploaded before digests were recorded have none to check.
+	if sum := hex.EncodeToString(h.Sum(nil)); original && img.Digest != "" && sum != img.Digest {
+		return nil, fmt.Errorf("image %s: digest %s does not match %s: %w", img.Id, sum, img.Digest, imgrepo.ErrDigestMismatch)
+	}
+
+	return &img, nil
 }
 
-func (irc *ImageRepoClient) List(lastId string) ([]*imgrepo.Image, error) {
+// List returns a page of the images viewable by the user, and selected by the
+// filter, after the image with id lastId.
+func (irc *ImageRepoClient) List(lastId string, filter imgrepo.ListFilter) ([]*imgrepo.Image, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
 	req := &ListRequest{
-		Token:  irc.Token,
-		Sender: irc.Owner,
-		Size:   int32(_PageSize),
-		LastId: lastId,
+		Size:    int32(_PageSize),
+		LastId:  lastId,
+		AnyTags: filter.Any,
+		AllTags: filter.All,
+		Shared:  filter.Shared,
 	}
 
-	resp, err := irc.client.ListImages(ctx, req)
+	resp, err := irc.client.ListImages(ctx, req, irc.auth())
 	if err != nil {
-		return nil, fmt.Errorf("%v.ListImages(_) = _, %v: ", irc.client, err)
+		return nil, newError("ListImages", err)
 	}
 
 	imgs := make([]*imgrepo.Image, len(resp.Files))
-	for idx, img := range resp.Files {
-		imgs[idx] = &imgrepo.Image{
-			Id:     img.Id,
-			Name:   img.FileName,
-			Owner:  img.Owner,
-			Access: imgrepo.Permission(img.Access),
+	for idx, finfo := range resp.Files {
+		imgs[idx] = toImage(finfo)
+	}
+
+	return imgs, nil
+}
+
+// toImage converts the file info of an image.
+func toImage(finfo *FileInfo) *imgrepo.Image {
+	return &imgrepo.Image{
+		Id:     finfo.GetId(),
+		Name:   finfo.GetFileName(),
+		Owner:  finfo.GetOwner(),
+		Access: imgrepo.Permission(finfo.GetAccess()),
+		Digest: finfo.GetDigest(),
+
+		Renditions: toSizes(finfo.GetRenditions()),
+		Metadata:   toMetadata(finfo.GetMetadata()),
+		Tags:       finfo.GetTags(),
+		Grant:      imgrepo.Grant{Readers: finfo.GetReaders(), Groups: finfo.GetReaderGroups()},
+	}
+}
+
+// toMetadata converts the metadata of an image, where a capture time of 0
+// means it is unknown.
+func toMetadata(md *Metadata) imgrepo.Metadata {
+	res := imgrepo.Metadata{
+		MimeType:    md.GetMimeType(),
+		Width:       int(md.GetWidth()),
+		Height:      int(md.GetHeight()),
+		Make:        md.GetMake(),
+		Model:       md.GetModel(),
+		Orientation: int(md.GetOrientation()),
+	}
+	if md.GetTaken() != 0 {
+		res.Taken = time.Unix(md.GetTaken(), 0).UTC()
+	}
+	if loc := md.GetLocation(); loc != nil {
+		res.Location = &imgrepo.Location{Latitude: loc.GetLatitude(), Longitude: loc.GetLongitude()}
+	}
+
+	return res
+}
+
+func toSizes(sizes []int32) []int {
+	var res []int
+	for _, size := range sizes {
+		res = append(res, int(size))
+	}
+	return res
+}
+
+// _FieldPaths maps the fields of images to their paths in update masks.
+var _FieldPaths = map[imgrepo.Field]string{
+	imgrepo.NameField:     "file_name",
+	imgrepo.AccessField:   "access",
+	imgrepo.TagsField:     "tags",
+	imgrepo.TakenField:    "metadata.taken",
+	imgrepo.LocationField: "metadata.location",
+}
+
+// Update sets the given fields of the image with id img.Id to their values
+// in img, and returns the updated image. Only the owner may update an image.
+func (irc *ImageRepoClient) Update(img *imgrepo.Image, fields ...imgrepo.Field) (*imgrepo.Image, error) {
+	mask := &fieldmaskpb.FieldMask{}
+	for _, f := range fields {
+		path, ok := _FieldPaths[f]
+		if !ok {
+			return nil, fmt.Errorf("field %q cannot be updated: %w", f, imgrepo.ErrInvalidArgument)
 		}
+		mask.Paths = append(mask.Paths, path)
+	}
+
+	// A capture time of 0 means it is unknown.
+	md := &Metadata{}
+	if !img.Metadata.Taken.IsZero() {
+		md.Taken = img.Metadata.Taken.Unix()
+	}
+	if loc := img.Metadata.Location; loc != nil {
+		md.Location = &Location{Latitude: loc.Latitude, Longitude: loc.Longitude}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	req := &UpdateRequest{
+		Id: img.Id,
+		FileInfo: &FileInfo{
+			FileName: img.Name,
+			Access:   int32(img.Access),
+			Metadata: md,
+			Tags:     img.Tags,
+		},
+		UpdateMask: mask,
+	}
+
+	resp, err := irc.client.UpdateImage(ctx, req, irc.auth())
+	if err != nil {
+		return nil, newError("UpdateImage", err)
+	}
+
+	return toImage(resp), nil
+}
+
+// Share grants the users and groups of g read access to the image with the
+// given id, and returns the updated image. Only the owner may share an image.
+func (irc *ImageRepoClient) Share(id string, g imgrepo.Grant) (*imgrepo.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	req := &ShareRequest{Id: id, Users: g.Readers, Groups: g.Groups}
+
+	resp, err := irc.client.ShareImage(ctx, req, irc.auth())
+	if err != nil {
+		return nil, newError("ShareImage", err)
+	}
+
+	return toImage(resp), nil
+}
+
+// Unshare revokes the read access of the users and groups of g to the image
+// with the given id, and returns the updated image.
+func (irc *ImageRepoClient) Unshare(id string, g imgrepo.Grant) (*imgrepo.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	req := &ShareRequest{Id: id, Users: g.Readers, Groups: g.Groups}
+
+	resp, err := irc.client.UnshareImage(ctx, req, irc.auth())
+	if err != nil {
+		return nil, newError("UnshareImage", err)
+	}
+
+	return toImage(resp), nil
+}
+
+// toGroup converts a group.
+func toGroup(g *Group) *imgrepo.Group {
+	return &imgrepo.Group{Name: g.GetName(), Admins: g.GetAdmins(), Members: g.GetMembers()}
+}
+
+// CreateGroup creates a group administered by the user.
+func (irc *ImageRepoClient) CreateGroup(name string) (*imgrepo.Group, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	resp, err := irc.client.CreateGroup(ctx, &GroupRequest{Name: name}, irc.auth())
+	if err != nil {
+		return nil, newError("CreateGroup", err)
+	}
+
+	return toGroup(resp), nil
+}
+
+// Group returns a group the user is a member of.
+func (irc *ImageRepoClient) Group(name string) (*imgrepo.Group, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	resp, err := irc.client.GetGroup(ctx, &GroupRequest{Name: name}, irc.auth())
+	if err != nil {
+		return nil, newError("GetGroup", err)
+	}
+
+	return toGroup(resp), nil
+}
+
+// Groups returns the names of the groups the user is a member of.
+func (irc *ImageRepoClient) Groups() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	resp, err := irc.client.ListGroups(ctx, &emptypb.Empty{}, irc.auth())
+	if err != nil {
+		return nil, newError("ListGroups", err)
+	}
+
+	return resp.Names, nil
+}
+
+func (irc *ImageRepoClient) AddMembers(name string, users ...string) (*imgrepo.Group, error) {
+	return irc.changeGroup("AddGroupMembers", irc.client.AddGroupMembers, name, users)
+}
+
+func (irc *ImageRepoClient) RemoveMembers(name string, users ...string) (*imgrepo.Group, error) {
+	return irc.changeGroup("RemoveGroupMembers", irc.client.RemoveGroupMembers, name, users)
+}
+
+func (irc *ImageRepoClient) AddAdmins(name string, users ...string) (*imgrepo.Group, error) {
+	return irc.changeGroup("AddGroupAdmins", irc.client.AddGroupAdmins, name, users)
+}
+
+func (irc *ImageRepoClient) RemoveAdmins(name string, users ...string) (*imgrepo.Group, error) {
+	return irc.changeGroup("RemoveGroupAdmins", irc.client.RemoveGroupAdmins, name, users)
+}
+
+// changeGroup calls the RPC with the given name, which changes the users of
+// a group, and returns the updated group.
+func (irc *ImageRepoClient) changeGroup(rpc string,
+	call func(context.Context, *GroupMembersRequest, ...grpc.CallOption) (*Group, error),
+	name string, users []string) (*imgrepo.Group, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	resp, err := call(ctx, &GroupMembersRequest{Name: name, Users: users}, irc.auth())
+	if err != nil {
+		return nil, newError(rpc, err)
+	}
+
+	return toGroup(resp), nil
+}
+
+// DeleteGroup deletes a group administered by the user.
+func (irc *ImageRepoClient) DeleteGroup(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	_, err := irc.client.DeleteGroup(ctx, &GroupRequest{Name: name}, irc.auth())
+	if err != nil {
+		return newError("DeleteGroup", err)
+	}
+
+	return nil
+}
+
+// toAlbum converts an album.
+func toAlbum(album *Album) *imgrepo.Album {
+	return &imgrepo.Album{
+		Id:     album.GetId(),
+		Name:   album.GetName(),
+		Owner:  album.GetOwner(),
+		Access: imgrepo.Permission(album.GetAccess()),
+		Images: album.GetImageIds(),
+		Grant:  imgrepo.Grant{Readers: album.GetReaders(), Groups: album.GetReaderGroups()},
+	}
+}
+
+// CreateAlbum creates an album owned by the user, holding the images with
+// the given ids, which the user must own.
+func (irc *ImageRepoClient) CreateAlbum(name string, access imgrepo.Permission, imageIds ...string) (*imgrepo.Album, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	req := &CreateAlbumRequest{Name: name, Access: int32(access), ImageIds: imageIds}
+
+	resp, err := irc.client.CreateAlbum(ctx, req, irc.auth())
+	if err != nil {
+		return nil, newError("CreateAlbum", err)
+	}
+
+	return toAlbum(resp), nil
+}
+
+// Albums returns a page of the albums viewable by the user, after the album
+// with id lastId.
+func (irc *ImageRepoClient) Albums(lastId string) ([]*imgrepo.Album, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	resp, err := irc.client.ListAlbums(ctx, &ListAlbumsRequest{Size: int32(_PageSize), LastId: lastId}, irc.auth())
+	if err != nil {
+		return nil, newError("ListAlbums", err)
+	}
+
+	albums := make([]*imgrepo.Album, len(resp.Albums))
+	for idx, album := range resp.Albums {
+		albums[idx] = toAlbum(album)
+	}
+
+	return albums, nil
+}
+
+// AlbumImages returns a page of the images of the album with the given id,
+// after the image with id lastId.
+func (irc *ImageRepoClient) AlbumImages(id, lastId string) ([]*imgrepo.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	req := &ListAlbumImagesRequest{Id: id, Size: int32(_PageSize), LastId: lastId}
+
+	resp, err := irc.client.ListAlbumImages(ctx, req, irc.auth())
+	if err != nil {
+		return nil, newError("ListAlbumImages", err)
+	}
+
+	imgs := make([]*imgrepo.Image, len(resp.Files))
+	for idx, finfo := range resp.Files {
+		imgs[idx] = toImage(finfo)
 	}
 
 	return imgs, nil
 }
+
+// DownloadFromAlbum is like Download, but downloads an image through the
+// album with id albumId, which lets its viewers download it whatever its
+// access.
+func (irc *ImageRepoClient) DownloadFromAlbum(albumId, id string, rendition int, w io.Writer) (*imgrepo.Image, error) {
+	return irc.download(&DownloadRequest{Id: id, Rendition: int32(rendition), AlbumId: albumId}, w)
+}
+
+// AddToAlbum adds images owned by the user to one of their albums.
+func (irc *ImageRepoClient) AddToAlbum(id string, imageIds ...string) (*imgrepo.Album, error) {
+	return irc.changeAlbum("AddAlbumImages", func(ctx context.Context) (*Album, error) {
+		return irc.client.AddAlbumImages(ctx, &AlbumImagesRequest{Id: id, ImageIds: imageIds}, irc.auth())
+	})
+}
+
+// RemoveFromAlbum removes images from an album owned by the user.
+func (irc *ImageRepoClient) RemoveFromAlbum(id string, imageIds ...string) (*imgrepo.Album, error) {
+	return irc.changeAlbum("RemoveAlbumImages", func(ctx context.Context) (*Album, error) {
+		return irc.client.RemoveAlbumImages(ctx, &AlbumImagesRequest{Id: id, ImageIds: imageIds}, irc.auth())
+	})
+}
+
+// ShareAlbum grants the users and groups read access to an album owned by
+// the user, and so to all of its images.
+func (irc *ImageRepoClient) ShareAlbum(id string, g imgrepo.Grant) (*imgrepo.Album, error) {
+	return irc.changeAlbum("ShareAlbum", func(ctx context.Context) (*Album, error) {
+		return irc.client.ShareAlbum(ctx, &ShareRequest{Id: id, Users: g.Readers, Groups: g.Groups}, irc.auth())
+	})
+}
+
+// UnshareAlbum revokes the read access of the users and groups to an album
+// owned by the user.
+func (irc *ImageRepoClient) UnshareAlbum(id string, g imgrepo.Grant) (*imgrepo.Album, error) {
+	return irc.changeAlbum("UnshareAlbum", func(ctx context.Context) (*Album, error) {
+		return irc.client.UnshareAlbum(ctx, &ShareRequest{Id: id, Users: g.Readers, Groups: g.Groups}, irc.auth())
+	})
+}
+
+// changeAlbum makes the call to the named RPC, which changes an album, and
+// returns the updated album.
+func (irc *ImageRepoClient) changeAlbum(rpc string, call func(ctx context.Context) (*Album, error)) (*imgrepo.Album, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	resp, err := call(ctx)
+	if err != nil {
+		return nil, newError(rpc, err)
+	}
+
+	return toAlbum(resp), nil
+}
+
+// DeleteAlbum deletes an album owned by the user, but not its images.
+func (irc *ImageRepoClient) DeleteAlbum(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	_, err := irc.client.DeleteAlbum(ctx, &AlbumRequest{Id: id}, irc.auth())
+	if err != nil {
+		return newError("DeleteAlbum", err)
+	}
+
+	return nil
+}
+
+// toShareLink converts a share link, where times of 0 mean none.
+func toShareLink(link *ShareLink) *imgrepo.ShareLink {
+	res := &imgrepo.ShareLink{
+		Id:           link.GetId(),
+		Token:        link.GetToken(),
+		ImageId:      link.GetImageId(),
+		Created:      time.Unix(link.GetCreated(), 0).UTC(),
+		MaxDownloads: int(link.GetMaxDownloads()),
+		Downloads:    int(link.GetDownloads()),
+	}
+	if link.GetExpires() != 0 {
+		res.Expires = time.Unix(link.GetExpires(), 0).UTC()
+	}
+
+	return res
+}
+
+// CreateShareLink creates a link to the image with the given id, which
+// anyone holding its token can download the image with. The link expires
+// at expires, unless it is zero, and after maxDownloads downloads, unless
+// it is 0. Only the owner may create links to an image.
+func (irc *ImageRepoClient) CreateShareLink(id string, expires time.Time, maxDownloads int) (*imgrepo.ShareLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	req := &CreateShareLinkRequest{Id: id, MaxDownloads: int32(maxDownloads)}
+	if !expires.IsZero() {
+		req.Expires = expires.Unix()
+	}
+
+	resp, err := irc.client.CreateShareLink(ctx, req, irc.auth())
+	if err != nil {
+		return nil, newError("CreateShareLink", err)
+	}
+
+	return toShareLink(resp), nil
+}
+
+// ShareLinks returns the links created by the user, newest first.
+func (irc *ImageRepoClient) ShareLinks() ([]*imgrepo.ShareLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	resp, err := irc.client.ListShareLinks(ctx, &emptypb.Empty{}, irc.auth())
+	if err != nil {
+		return nil, newError("ListShareLinks", err)
+	}
+
+	links := make([]*imgrepo.ShareLink, len(resp.Links))
+	for idx, link := range resp.Links {
+		links[idx] = toShareLink(link)
+	}
+
+	return links, nil
+}
+
+// RevokeShareLink deletes the link with the given id, created by the user.
+func (irc *ImageRepoClient) RevokeShareLink(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	_, err := irc.client.RevokeShareLink(ctx, &RevokeShareLinkRequest{Id: id}, irc.auth())
+	if err != nil {
+		return newError("RevokeShareLink", err)
+	}
+
+	return nil
+}
+
+func (irc *ImageRepoClient) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	req := &DeleteRequest{Id: id}
+
+	_, err := irc.client.DeleteImage(ctx, req, irc.auth())
+	if err != nil {
+		return newError("DeleteImage", err)
+	}
+
+	return nil
+}
+
+// Similar returns the viewable images whose perceptual hash is within
+// maxDist of the query, closest first. The query is the image with the
+// given id if r is nil, and the image read from r otherwise.
+func (irc *ImageRepoClient) Similar(id string, r io.Reader, maxDist int) ([]imgrepo.Match, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _StreamTimeout)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	stream, err := irc.client.SearchSimilar(ctx, irc.auth())
+	if err != nil {
+		return nil, newError("SearchSimilar", err)
+	}
+
+	query := &SearchRequest{
+		Event: &SearchRequest_Query{
+			Query: &SearchQuery{Id: id, MaxDistance: int32(maxDist)},
+		},
+	}
+
+	// Send returns io.EOF when the server aborts the stream, in which case the
+	// error is reported by CloseAndRecv.
+	err = stream.Send(query)
+
+	buf := make([]byte, _ChunkSize)
+	for r != nil && err == nil {
+		n, rerr := r.Read(buf)
+		if n > 0 {
+			err = stream.Send(&SearchRequest{Event: &SearchRequest_Chunk{Chunk: buf[:n]}})
+		}
+		if rerr == io.EOF {
+			break
+		} else if rerr != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to read image", rerr)
+		}
+	}
+	if err != nil && err != io.EOF {
+		return nil, newError("SearchSimilar", err)
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, newError("SearchSimilar", err)
+	}
+
+	matches := make([]imgrepo.Match, len(resp.Images))
+	for idx, sim := range resp.Images {
+		matches[idx] = imgrepo.Match{Image: toImage(sim.FileInfo), Distance: int(sim.Distance)}
+	}
+
+	return matches, nil
+}
+
+// Users returns every account, sorted by username. Only moderators and
+// admins may list users.
+func (irc *ImageRepoClient) Users() ([]*imgrepo.Account, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	resp, err := irc.admin.ListUsers(ctx, &emptypb.Empty{}, irc.auth())
+	if err != nil {
+		return nil, newError("ListUsers", err)
+	}
+
+	accounts := make([]*imgrepo.Account, len(resp.Users))
+	for idx, user := range resp.Users {
+		accounts[idx] = &imgrepo.Account{
+			Username: user.GetUsername(),
+			Role:     imgrepo.Role(user.GetRole()),
+			Disabled: user.GetDisabled(),
+		}
+	}
+
+	return accounts, nil
+}
+
+// SetRole sets the role of another account. Only admins may set roles.
+func (irc *ImageRepoClient) SetRole(username string, role imgrepo.Role) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	_, err := irc.admin.SetRole(ctx, &SetRoleRequest{Username: username, Role: int32(role)}, irc.auth())
+	if err != nil {
+		return newError("SetRole", err)
+	}
+
+	return nil
+}
+
+// SetDisabled disables another account, or enables it again. Only admins
+// may disable accounts.
+func (irc *ImageRepoClient) SetDisabled(username string, disabled bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	_, err := irc.admin.SetDisabled(ctx, &SetDisabledRequest{Username: username, Disabled: disabled}, irc.auth())
+	if err != nil {
+		return newError("SetDisabled", err)
+	}
+
+	return nil
+}
+
+// ForceDelete deletes the image with the given id, whoever owns it. Only
+// moderators and admins may delete the images of others.
+func (irc *ImageRepoClient) ForceDelete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	_, err := irc.admin.ForceDeleteImage(ctx, &DeleteRequest{Id: id}, irc.auth())
+	if err != nil {
+		return newError("ForceDeleteImage", err)
+	}
+
+	return nil
+}
+
+// StorageUsage returns the storage used by every account, largest first.
+// Only admins may view the usage.
+func (irc *ImageRepoClient) StorageUsage() ([]imgrepo.Usage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	irc.mu.RLock()
+	defer irc.mu.RUnlock()
+
+	resp, err := irc.admin.StorageUsage(ctx, &emptypb.Empty{}, irc.auth())
+	if err != nil {
+		return nil, newError("StorageUsage", err)
+	}
+
+	usage := make([]imgrepo.Usage, len(resp.Usage))
+	for idx, u := range resp.Usage {
+		usage[idx] = imgrepo.Usage{Username: u.GetUsername(), Images: int(u.GetImages()), Bytes: u.GetBytes()}
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,63 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/algao1/imgrepo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// _CodeErrors maps gRPC status codes back to the imgrepo sentinel errors.
+var _CodeErrors = map[codes.Code]error{
+	codes.NotFound:           imgrepo.ErrNotFound,
+	codes.PermissionDenied:   imgrepo.ErrPermissionDenied,
+	codes.AlreadyExists:      imgrepo.ErrAlreadyExists,
+	codes.Unauthenticated:    imgrepo.ErrUnauthenticated,
+	codes.InvalidArgument:    imgrepo.ErrInvalidArgument,
+	codes.DataLoss:           imgrepo.ErrDigestMismatch,
+	codes.OutOfRange:         imgrepo.ErrTooLarge,
+	codes.FailedPrecondition: imgrepo.ErrExpired,
+	codes.Aborted:            imgrepo.ErrConflict,
+}
+
+// Error is returned by ImageRepoClient when an RPC fails. It matches the
+// imgrepo sentinel error for its code, so errors.Is(err, imgrepo.ErrNotFound)
+// can be used to check for a missing image.
+type Error struct {
+	Method  string
+	Code    codes.Code
+	Message string
+}
+
+// newError wraps an error returned by the RPC method.
+func newError(method string, err error) *Error {
+	st := status.Convert(err)
+	return &Error{Method: method, Code: st.Code(), Message: st.Message()}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Method, e.Code, e.Message)
+}
+
+// Is reports whether target is the sentinel error corresponding to e.Code.
+func (e *Error) Is(target error) bool {
+	sentinel, ok := _CodeErrors[e.Code]
+	return ok && sentinel == target
+}
+
+// isTransient reports whether the RPC that failed with err may succeed
+// if retried. Conflicts are not, since the image changed since it was read.
+func isTransient(err error) bool {
+	var rerr *Error
+	if !errors.As(err, &rerr) {
+		return false
+	}
+
+	switch rerr.Code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package proto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/algao1/imgrepo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorIs(t *testing.T) {
+	tests := map[string]struct {
+		code codes.Code
+		want error
+	}{
+		"not found":         {code: codes.NotFound, want: imgrepo.ErrNotFound},
+		"permission denied": {code: codes.PermissionDenied, want: imgrepo.ErrPermissionDenied},
+		"already exists":    {code: codes.AlreadyExists, want: imgrepo.ErrAlreadyExists},
+		"unauthenticated":   {code: codes.Unauthenticated, want: imgrepo.ErrUnauthenticated},
+		"invalid argument":  {code: codes.InvalidArgument, want: imgrepo.ErrInvalidArgument},
+		"digest mismatch":   {code: codes.DataLoss, want: imgrepo.ErrDigestMismatch},
+		"too large":         {code: codes.OutOfRange, want: imgrepo.ErrTooLarge},
+		"expired":           {code: codes.FailedPrecondition, want: imgrepo.ErrExpired},
+		"conflict":          {code: codes.Aborted, want: imgrepo.ErrConflict},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var err error = newError("Test", status.Error(tc.code, name))
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("errors.Is(%v, %v) = false, want true", err, tc.want)
+			}
+
+			var rerr *Error
+			if !errors.As(err, &rerr) || rerr.Code != tc.code {
+				t.Fatalf("errors.As(%v) did not expose code %v", err, tc.code)
+			}
+		})
+	}
+
+	err := newError("Test", status.Error(codes.Internal, "boom"))
+	if errors.Is(err, imgrepo.ErrNotFound) {
+		t.Fatal("internal error matched imgrepo.ErrNotFound")
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := map[codes.Code]bool{
+		codes.Unavailable:      true,
+		codes.DeadlineExceeded: true,
+		codes.Aborted:          false,
+		codes.NotFound:         false,
+	}
+
+	for code, want := range tests {
+		if got := isTransient(newError("Test", status.Error(code, "x"))); got != want {
+			t.Errorf("isTransient(%v) = %v, want %v", code, got, want)
+		}
+	}
+}
@@ -22,8 +22,57 @@ type RepoClient interface {
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
 	UploadImage(ctx context.Context, opts ...grpc.CallOption) (Repo_UploadImageClient, error)
+	// Resumable uploads: BeginUpload returns an upload id, chunks are then
+	// written with their offsets, possibly over several WriteUpload calls,
+	// and CommitUpload adds the image to the repository. QueryUpload reports
+	// the committed offset to resume from after a failure.
+	BeginUpload(ctx context.Context, in *BeginUploadRequest, opts ...grpc.CallOption) (*UploadStatus, error)
+	WriteUpload(ctx context.Context, opts ...grpc.CallOption) (Repo_WriteUploadClient, error)
+	QueryUpload(ctx context.Context, in *QueryUploadRequest, opts ...grpc.CallOption) (*UploadStatus, error)
+	CommitUpload(ctx context.Context, in *CommitUploadRequest, opts ...grpc.CallOption) (*UploadResponse, error)
 	DownloadImage(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (Repo_DownloadImageClient, error)
 	ListImages(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	UpdateImage(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*FileInfo, error)
+	// ShareImage grants users and groups read access to an image, and
+	// UnshareImage revokes it. Both respond with the updated file info.
+	ShareImage(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*FileInfo, error)
+	UnshareImage(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*FileInfo, error)
+	// Groups are named sets of users that images can be shared with. Only
+	// their admins may change them, but any member may leave. ListGroups
+	// lists the groups of the requester.
+	CreateGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*Group, error)
+	GetGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*Group, error)
+	ListGroups(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListGroupsResponse, error)
+	AddGroupMembers(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (*Group, error)
+	RemoveGroupMembers(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (*Group, error)
+	AddGroupAdmins(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (*Group, error)
+	RemoveGroupAdmins(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (*Group, error)
+	DeleteGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Albums are named collections of images with an access of their own, so
+	// that a public or shared album shows the images of its owner whatever
+	// their access. Only their owner may change them, and only add the images
+	// they own. The images of an album are downloaded with DownloadImage,
+	// giving the id of the album.
+	CreateAlbum(ctx context.Context, in *CreateAlbumRequest, opts ...grpc.CallOption) (*Album, error)
+	ListAlbums(ctx context.Context, in *ListAlbumsRequest, opts ...grpc.CallOption) (*ListAlbumsResponse, error)
+	ListAlbumImages(ctx context.Context, in *ListAlbumImagesRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	AddAlbumImages(ctx context.Context, in *AlbumImagesRequest, opts ...grpc.CallOption) (*Album, error)
+	RemoveAlbumImages(ctx context.Context, in *AlbumImagesRequest, opts ...grpc.CallOption) (*Album, error)
+	ShareAlbum(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*Album, error)
+	UnshareAlbum(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*Album, error)
+	DeleteAlbum(ctx context.Context, in *AlbumRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Share links let anyone holding their token download an image without
+	// an account. Each DownloadShared counts a download of the link, which
+	// fails once the link has expired or reached its maximum downloads.
+	CreateShareLink(ctx context.Context, in *CreateShareLinkRequest, opts ...grpc.CallOption) (*ShareLink, error)
+	ListShareLinks(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListShareLinksResponse, error)
+	RevokeShareLink(ctx context.Context, in *RevokeShareLinkRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	DownloadShared(ctx context.Context, in *DownloadSharedRequest, opts ...grpc.CallOption) (Repo_DownloadSharedClient, error)
+	DeleteImage(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// SearchSimilar finds the viewable images whose perceptual hash is within
+	// a distance of the query. The first message holds the query, which names
+	// an existing image, or is followed by the chunks of a query image.
+	SearchSimilar(ctx context.Context, opts ...grpc.CallOption) (Repo_SearchSimilarClient, error)
 }
 
 type repoClient struct {
@@ -63,7 +112,7 @@ func (c *repoClient) UploadImage(ctx context.Context, opts ...grpc.CallOption) (
 
 type Repo_UploadImageClient interface {
 	Send(*Upload) error
-	CloseAndRecv() (*empty.Empty, error)
+	CloseAndRecv() (*UploadResponse, error)
 	grpc.ClientStream
 }
 
@@ -75,19 +124,80 @@ func (x *repoUploadImageClient) Send(m *Upload) error {
 	return x.ClientStream.SendMsg(m)
 }
 
-func (x *repoUploadImageClient) CloseAndRecv() (*empty.Empty, error) {
+func (x *repoUploadImageClient) CloseAndRecv() (*UploadResponse, error) {
 	if err := x.ClientStream.CloseSend(); err != nil {
 		return nil, err
 	}
-	m := new(empty.Empty)
+	m := new(UploadResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
+func (c *repoClient) BeginUpload(ctx context.Context, in *BeginUploadRequest, opts ...grpc.CallOption) (*UploadStatus, error) {
+	out := new(UploadStatus)
+	err := c.cc.Invoke(ctx, "/proto.Repo/BeginUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) WriteUpload(ctx context.Context, opts ...grpc.CallOption) (Repo_WriteUploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Repo_ServiceDesc.Streams[1], "/proto.Repo/WriteUpload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repoWriteUploadClient{stream}
+	return x, nil
+}
+
+type Repo_WriteUploadClient interface {
+	Send(*UploadChunk) error
+	CloseAndRecv() (*UploadStatus, error)
+	grpc.ClientStream
+}
+
+type repoWriteUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *repoWriteUploadClient) Send(m *UploadChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *repoWriteUploadClient) CloseAndRecv() (*UploadStatus, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *repoClient) QueryUpload(ctx context.Context, in *QueryUploadRequest, opts ...grpc.CallOption) (*UploadStatus, error) {
+	out := new(UploadStatus)
+	err := c.cc.Invoke(ctx, "/proto.Repo/QueryUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) CommitUpload(ctx context.Context, in *CommitUploadRequest, opts ...grpc.CallOption) (*UploadResponse, error) {
+	out := new(UploadResponse)
+	err := c.cc.Invoke(ctx, "/proto.Repo/CommitUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *repoClient) DownloadImage(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (Repo_DownloadImageClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Repo_ServiceDesc.Streams[1], "/proto.Repo/DownloadImage", opts...)
+	stream, err := c.cc.NewStream(ctx, &Repo_ServiceDesc.Streams[2], "/proto.Repo/DownloadImage", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -95,214 +205,1513 @@ func (c *repoClient) DownloadImage(ctx context.Context, in *DownloadRequest, opt
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Repo_DownloadImageClient interface {
+	Recv() (*Download, error)
+	grpc.ClientStream
+}
+
+type repoDownloadImageClient struct {
+	grpc.ClientStream
+}
+
+func (x *repoDownloadImageClient) Recv() (*Download, error) {
+	m := new(Download)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *repoClient) ListImages(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/proto.Repo/ListImages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) UpdateImage(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*FileInfo, error) {
+	out := new(FileInfo)
+	err := c.cc.Invoke(ctx, "/proto.Repo/UpdateImage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) ShareImage(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*FileInfo, error) {
+	out := new(FileInfo)
+	err := c.cc.Invoke(ctx, "/proto.Repo/ShareImage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) UnshareImage(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*FileInfo, error) {
+	out := new(FileInfo)
+	err := c.cc.Invoke(ctx, "/proto.Repo/UnshareImage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) CreateGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/proto.Repo/CreateGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) GetGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/proto.Repo/GetGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) ListGroups(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
+	out := new(ListGroupsResponse)
+	err := c.cc.Invoke(ctx, "/proto.Repo/ListGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) AddGroupMembers(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/proto.Repo/AddGroupMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) RemoveGroupMembers(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/proto.Repo/RemoveGroupMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) AddGroupAdmins(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/proto.Repo/AddGroupAdmins", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) RemoveGroupAdmins(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/proto.Repo/RemoveGroupAdmins", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) DeleteGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Repo/DeleteGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) CreateAlbum(ctx context.Context, in *CreateAlbumRequest, opts ...grpc.CallOption) (*Album, error) {
+	out := new(Album)
+	err := c.cc.Invoke(ctx, "/proto.Repo/CreateAlbum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) ListAlbums(ctx context.Context, in *ListAlbumsRequest, opts ...grpc.CallOption) (*ListAlbumsResponse, error) {
+	out := new(ListAlbumsResponse)
+	err := c.cc.Invoke(ctx, "/proto.Repo/ListAlbums", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) ListAlbumImages(ctx context.Context, in *ListAlbumImagesRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/proto.Repo/ListAlbumImages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) AddAlbumImages(ctx context.Context, in *AlbumImagesRequest, opts ...grpc.CallOption) (*Album, error) {
+	out := new(Album)
+	err := c.cc.Invoke(ctx, "/proto.Repo/AddAlbumImages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) RemoveAlbumImages(ctx context.Context, in *AlbumImagesRequest, opts ...grpc.CallOption) (*Album, error) {
+	out := new(Album)
+	err := c.cc.Invoke(ctx, "/proto.Repo/RemoveAlbumImages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) ShareAlbum(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*Album, error) {
+	out := new(Album)
+	err := c.cc.Invoke(ctx, "/proto.Repo/ShareAlbum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) UnshareAlbum(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*Album, error) {
+	out := new(Album)
+	err := c.cc.Invoke(ctx, "/proto.Repo/UnshareAlbum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) DeleteAlbum(ctx context.Context, in *AlbumRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Repo/DeleteAlbum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) CreateShareLink(ctx context.Context, in *CreateShareLinkRequest, opts ...grpc.CallOption) (*ShareLink, error) {
+	out := new(ShareLink)
+	err := c.cc.Invoke(ctx, "/proto.Repo/CreateShareLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) ListShareLinks(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListShareLinksResponse, error) {
+	out := new(ListShareLinksResponse)
+	err := c.cc.Invoke(ctx, "/proto.Repo/ListShareLinks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) RevokeShareLink(ctx context.Context, in *RevokeShareLinkRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Repo/RevokeShareLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) DownloadShared(ctx context.Context, in *DownloadSharedRequest, opts ...grpc.CallOption) (Repo_DownloadSharedClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Repo_ServiceDesc.Streams[3], "/proto.Repo/DownloadShared", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repoDownloadSharedClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Repo_DownloadSharedClient interface {
+	Recv() (*Download, error)
+	grpc.ClientStream
+}
+
+type repoDownloadSharedClient struct {
+	grpc.ClientStream
+}
+
+func (x *repoDownloadSharedClient) Recv() (*Download, error) {
+	m := new(Download)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *repoClient) DeleteImage(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Repo/DeleteImage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repoClient) SearchSimilar(ctx context.Context, opts ...grpc.CallOption) (Repo_SearchSimilarClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Repo_ServiceDesc.Streams[4], "/proto.Repo/SearchSimilar", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &repoSearchSimilarClient{stream}
+	return x, nil
+}
+
+type Repo_SearchSimilarClient interface {
+	Send(*SearchRequest) error
+	CloseAndRecv() (*SearchResponse, error)
+	grpc.ClientStream
+}
+
+type repoSearchSimilarClient struct {
+	grpc.ClientStream
+}
+
+func (x *repoSearchSimilarClient) Send(m *SearchRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *repoSearchSimilarClient) CloseAndRecv() (*SearchResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SearchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RepoServer is the server API for Repo service.
+// All implementations must embed UnimplementedRepoServer
+// for forward compatibility
+type RepoServer interface {
+	Register(context.Context, *RegisterRequest) (*empty.Empty, error)
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	UploadImage(Repo_UploadImageServer) error
+	// Resumable uploads: BeginUpload returns an upload id, chunks are then
+	// written with their offsets, possibly over several WriteUpload calls,
+	// and CommitUpload adds the image to the repository. QueryUpload reports
+	// the committed offset to resume from after a failure.
+	BeginUpload(context.Context, *BeginUploadRequest) (*UploadStatus, error)
+	WriteUpload(Repo_WriteUploadServer) error
+	QueryUpload(context.Context, *QueryUploadRequest) (*UploadStatus, error)
+	CommitUpload(context.Context, *CommitUploadRequest) (*UploadResponse, error)
+	DownloadImage(*DownloadRequest, Repo_DownloadImageServer) error
+	ListImages(context.Context, *ListRequest) (*ListResponse, error)
+	UpdateImage(context.Context, *UpdateRequest) (*FileInfo, error)
+	// ShareImage grants users and groups read access to an image, and
+	// UnshareImage revokes it. Both respond with the updated file info.
+	ShareImage(context.Context, *ShareRequest) (*FileInfo, error)
+	UnshareImage(context.Context, *ShareRequest) (*FileInfo, error)
+	// Groups are named sets of users that images can be shared with. Only
+	// their admins may change them, but any member may leave. ListGroups
+	// lists the groups of the requester.
+	CreateGroup(context.Context, *GroupRequest) (*Group, error)
+	GetGroup(context.Context, *GroupRequest) (*Group, error)
+	ListGroups(context.Context, *empty.Empty) (*ListGroupsResponse, error)
+	AddGroupMembers(context.Context, *GroupMembersRequest) (*Group, error)
+	RemoveGroupMembers(context.Context, *GroupMembersRequest) (*Group, error)
+	AddGroupAdmins(context.Context, *GroupMembersRequest) (*Group, error)
+	RemoveGroupAdmins(context.Context, *GroupMembersRequest) (*Group, error)
+	DeleteGroup(context.Context, *GroupRequest) (*empty.Empty, error)
+	// Albums are named collections of images with an access of their own, so
+	// that a public or shared album shows the images of its owner whatever
+	// their access. Only their owner may change them, and only add the images
+	// they own. The images of an album are downloaded with DownloadImage,
+	// giving the id of the album.
+	CreateAlbum(context.Context, *CreateAlbumRequest) (*Album, error)
+	ListAlbums(context.Context, *ListAlbumsRequest) (*ListAlbumsResponse, error)
+	ListAlbumImages(context.Context, *ListAlbumImagesRequest) (*ListResponse, error)
+	AddAlbumImages(context.Context, *AlbumImagesRequest) (*Album, error)
+	RemoveAlbumImages(context.Context, *AlbumImagesRequest) (*Album, error)
+	ShareAlbum(context.Context, *ShareRequest) (*Album, error)
+	UnshareAlbum(context.Context, *ShareRequest) (*Album, error)
+	DeleteAlbum(context.Context, *AlbumRequest) (*empty.Empty, error)
+	// Share links let anyone holding their token download an image without
+	// an account. Each DownloadShared counts a download of the link, which
+	// fails once the link has expired or reached its maximum downloads.
+	CreateShareLink(context.Context, *CreateShareLinkRequest) (*ShareLink, error)
+	ListShareLinks(context.Context, *empty.Empty) (*ListShareLinksResponse, error)
+	RevokeShareLink(context.Context, *RevokeShareLinkRequest) (*empty.Empty, error)
+	DownloadShared(*DownloadSharedRequest, Repo_DownloadSharedServer) error
+	DeleteImage(context.Context, *DeleteRequest) (*empty.Empty, error)
+	// SearchSimilar finds the viewable images whose perceptual hash is within
+	// a distance of the query. The first message holds the query, which names
+	// an existing image, or is followed by the chunks of a query image.
+	SearchSimilar(Repo_SearchSimilarServer) error
+	mustEmbedUnimplementedRepoServer()
+}
+
+// UnimplementedRepoServer must be embedded to have forward compatible implementations.
+type UnimplementedRepoServer struct {
+}
+
+func (UnimplementedRepoServer) Register(context.Context, *RegisterRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedRepoServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedRepoServer) UploadImage(Repo_UploadImageServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadImage not implemented")
+}
+func (UnimplementedRepoServer) BeginUpload(context.Context, *BeginUploadRequest) (*UploadStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BeginUpload not implemented")
+}
+func (UnimplementedRepoServer) WriteUpload(Repo_WriteUploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method WriteUpload not implemented")
+}
+func (UnimplementedRepoServer) QueryUpload(context.Context, *QueryUploadRequest) (*UploadStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryUpload not implemented")
+}
+func (UnimplementedRepoServer) CommitUpload(context.Context, *CommitUploadRequest) (*UploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitUpload not implemented")
+}
+func (UnimplementedRepoServer) DownloadImage(*DownloadRequest, Repo_DownloadImageServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadImage not implemented")
+}
+func (UnimplementedRepoServer) ListImages(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListImages not implemented")
+}
+func (UnimplementedRepoServer) UpdateImage(context.Context, *UpdateRequest) (*FileInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateImage not implemented")
+}
+func (UnimplementedRepoServer) ShareImage(context.Context, *ShareRequest) (*FileInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareImage not implemented")
+}
+func (UnimplementedRepoServer) UnshareImage(context.Context, *ShareRequest) (*FileInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnshareImage not implemented")
+}
+func (UnimplementedRepoServer) CreateGroup(context.Context, *GroupRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGroup not implemented")
+}
+func (UnimplementedRepoServer) GetGroup(context.Context, *GroupRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (UnimplementedRepoServer) ListGroups(context.Context, *empty.Empty) (*ListGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroups not implemented")
+}
+func (UnimplementedRepoServer) AddGroupMembers(context.Context, *GroupMembersRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddGroupMembers not implemented")
+}
+func (UnimplementedRepoServer) RemoveGroupMembers(context.Context, *GroupMembersRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveGroupMembers not implemented")
+}
+func (UnimplementedRepoServer) AddGroupAdmins(context.Context, *GroupMembersRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddGroupAdmins not implemented")
+}
+func (UnimplementedRepoServer) RemoveGroupAdmins(context.Context, *GroupMembersRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveGroupAdmins not implemented")
+}
+func (UnimplementedRepoServer) DeleteGroup(context.Context, *GroupRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroup not implemented")
+}
+func (UnimplementedRepoServer) CreateAlbum(context.Context, *CreateAlbumRequest) (*Album, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAlbum not implemented")
+}
+func (UnimplementedRepoServer) ListAlbums(context.Context, *ListAlbumsRequest) (*ListAlbumsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlbums not implemented")
+}
+func (UnimplementedRepoServer) ListAlbumImages(context.Context, *ListAlbumImagesRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlbumImages not implemented")
+}
+func (UnimplementedRepoServer) AddAlbumImages(context.Context, *AlbumImagesRequest) (*Album, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAlbumImages not implemented")
+}
+func (UnimplementedRepoServer) RemoveAlbumImages(context.Context, *AlbumImagesRequest) (*Album, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveAlbumImages not implemented")
+}
+func (UnimplementedRepoServer) ShareAlbum(context.Context, *ShareRequest) (*Album, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareAlbum not implemented")
+}
+func (UnimplementedRepoServer) UnshareAlbum(context.Context, *ShareRequest) (*Album, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnshareAlbum not implemented")
+}
+func (UnimplementedRepoServer) DeleteAlbum(context.Context, *AlbumRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlbum not implemented")
+}
+func (UnimplementedRepoServer) CreateShareLink(context.Context, *CreateShareLinkRequest) (*ShareLink, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateShareLink not implemented")
+}
+func (UnimplementedRepoServer) ListShareLinks(context.Context, *empty.Empty) (*ListShareLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShareLinks not implemented")
+}
+func (UnimplementedRepoServer) RevokeShareLink(context.Context, *RevokeShareLinkRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeShareLink not implemented")
+}
+func (UnimplementedRepoServer) DownloadShared(*DownloadSharedRequest, Repo_DownloadSharedServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadShared not implemented")
+}
+func (UnimplementedRepoServer) DeleteImage(context.Context, *DeleteRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteImage not implemented")
+}
+func (UnimplementedRepoServer) SearchSimilar(Repo_SearchSimilarServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchSimilar not implemented")
+}
+func (UnimplementedRepoServer) mustEmbedUnimplementedRepoServer() {}
+
+// UnsafeRepoServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RepoServer will
+// result in compilation errors.
+type UnsafeRepoServer interface {
+	mustEmbedUnimplementedRepoServer()
+}
+
+func RegisterRepoServer(s grpc.ServiceRegistrar, srv RepoServer) {
+	s.RegisterService(&Repo_ServiceDesc, srv)
+}
+
+func _Repo_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/Register",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_UploadImage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RepoServer).UploadImage(&repoUploadImageServer{stream})
+}
+
+type Repo_UploadImageServer interface {
+	SendAndClose(*UploadResponse) error
+	Recv() (*Upload, error)
+	grpc.ServerStream
+}
+
+type repoUploadImageServer struct {
+	grpc.ServerStream
+}
+
+func (x *repoUploadImageServer) SendAndClose(m *UploadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *repoUploadImageServer) Recv() (*Upload, error) {
+	m := new(Upload)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Repo_BeginUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).BeginUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/BeginUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).BeginUpload(ctx, req.(*BeginUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_WriteUpload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RepoServer).WriteUpload(&repoWriteUploadServer{stream})
+}
+
+type Repo_WriteUploadServer interface {
+	SendAndClose(*UploadStatus) error
+	Recv() (*UploadChunk, error)
+	grpc.ServerStream
+}
+
+type repoWriteUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *repoWriteUploadServer) SendAndClose(m *UploadStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *repoWriteUploadServer) Recv() (*UploadChunk, error) {
+	m := new(UploadChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Repo_QueryUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).QueryUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/QueryUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).QueryUpload(ctx, req.(*QueryUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_CommitUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).CommitUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/CommitUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).CommitUpload(ctx, req.(*CommitUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_DownloadImage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RepoServer).DownloadImage(m, &repoDownloadImageServer{stream})
+}
+
+type Repo_DownloadImageServer interface {
+	Send(*Download) error
+	grpc.ServerStream
+}
+
+type repoDownloadImageServer struct {
+	grpc.ServerStream
+}
+
+func (x *repoDownloadImageServer) Send(m *Download) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Repo_ListImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).ListImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/ListImages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).ListImages(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_UpdateImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).UpdateImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/UpdateImage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).UpdateImage(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_ShareImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).ShareImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/ShareImage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).ShareImage(ctx, req.(*ShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_UnshareImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).UnshareImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/UnshareImage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).UnshareImage(ctx, req.(*ShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).CreateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/CreateGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).CreateGroup(ctx, req.(*GroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/GetGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).GetGroup(ctx, req.(*GroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/ListGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).ListGroups(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_AddGroupMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).AddGroupMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/AddGroupMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).AddGroupMembers(ctx, req.(*GroupMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_RemoveGroupMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).RemoveGroupMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/RemoveGroupMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).RemoveGroupMembers(ctx, req.(*GroupMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_AddGroupAdmins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).AddGroupAdmins(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/AddGroupAdmins",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).AddGroupAdmins(ctx, req.(*GroupMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_RemoveGroupAdmins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).RemoveGroupAdmins(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/RemoveGroupAdmins",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).RemoveGroupAdmins(ctx, req.(*GroupMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).DeleteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/DeleteGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).DeleteGroup(ctx, req.(*GroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_CreateAlbum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAlbumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).CreateAlbum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/CreateAlbum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).CreateAlbum(ctx, req.(*CreateAlbumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_ListAlbums_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlbumsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).ListAlbums(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/ListAlbums",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).ListAlbums(ctx, req.(*ListAlbumsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_ListAlbumImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlbumImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).ListAlbumImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/ListAlbumImages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).ListAlbumImages(ctx, req.(*ListAlbumImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_AddAlbumImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlbumImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).AddAlbumImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/AddAlbumImages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).AddAlbumImages(ctx, req.(*AlbumImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_RemoveAlbumImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlbumImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).RemoveAlbumImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/RemoveAlbumImages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).RemoveAlbumImages(ctx, req.(*AlbumImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_ShareAlbum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).ShareAlbum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/ShareAlbum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).ShareAlbum(ctx, req.(*ShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_UnshareAlbum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).UnshareAlbum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/UnshareAlbum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).UnshareAlbum(ctx, req.(*ShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_DeleteAlbum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlbumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).DeleteAlbum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/DeleteAlbum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).DeleteAlbum(ctx, req.(*AlbumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_CreateShareLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateShareLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).CreateShareLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/CreateShareLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).CreateShareLink(ctx, req.(*CreateShareLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_ListShareLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).ListShareLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/ListShareLinks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).ListShareLinks(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_RevokeShareLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeShareLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).RevokeShareLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/RevokeShareLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).RevokeShareLink(ctx, req.(*RevokeShareLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_DownloadShared_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadSharedRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RepoServer).DownloadShared(m, &repoDownloadSharedServer{stream})
+}
+
+type Repo_DownloadSharedServer interface {
+	Send(*Download) error
+	grpc.ServerStream
+}
+
+type repoDownloadSharedServer struct {
+	grpc.ServerStream
+}
+
+func (x *repoDownloadSharedServer) Send(m *Download) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Repo_DeleteImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepoServer).DeleteImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Repo/DeleteImage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepoServer).DeleteImage(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Repo_SearchSimilar_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RepoServer).SearchSimilar(&repoSearchSimilarServer{stream})
+}
+
+type Repo_SearchSimilarServer interface {
+	SendAndClose(*SearchResponse) error
+	Recv() (*SearchRequest, error)
+	grpc.ServerStream
+}
+
+type repoSearchSimilarServer struct {
+	grpc.ServerStream
+}
+
+func (x *repoSearchSimilarServer) SendAndClose(m *SearchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *repoSearchSimilarServer) Recv() (*SearchRequest, error) {
+	m := new(SearchRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Repo_ServiceDesc is the grpc.ServiceDesc for Repo service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Repo_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.Repo",
+	HandlerType: (*RepoServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _Repo_Register_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _Repo_Login_Handler,
+		},
+		{
+			MethodName: "BeginUpload",
+			Handler:    _Repo_BeginUpload_Handler,
+		},
+		{
+			MethodName: "QueryUpload",
+			Handler:    _Repo_QueryUpload_Handler,
+		},
+		{
+			MethodName: "CommitUpload",
+			Handler:    _Repo_CommitUpload_Handler,
+		},
+		{
+			MethodName: "ListImages",
+			Handler:    _Repo_ListImages_Handler,
+		},
+		{
+			MethodName: "UpdateImage",
+			Handler:    _Repo_UpdateImage_Handler,
+		},
+		{
+			MethodName: "ShareImage",
+			Handler:    _Repo_ShareImage_Handler,
+		},
+		{
+			MethodName: "UnshareImage",
+			Handler:    _Repo_UnshareImage_Handler,
+		},
+		{
+			MethodName: "CreateGroup",
+			Handler:    _Repo_CreateGroup_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _Repo_GetGroup_Handler,
+		},
+		{
+			MethodName: "ListGroups",
+			Handler:    _Repo_ListGroups_Handler,
+		},
+		{
+			MethodName: "AddGroupMembers",
+			Handler:    _Repo_AddGroupMembers_Handler,
+		},
+		{
+			MethodName: "RemoveGroupMembers",
+			Handler:    _Repo_RemoveGroupMembers_Handler,
+		},
+		{
+			MethodName: "AddGroupAdmins",
+			Handler:    _Repo_AddGroupAdmins_Handler,
+		},
+		{
+			MethodName: "RemoveGroupAdmins",
+			Handler:    _Repo_RemoveGroupAdmins_Handler,
+		},
+		{
+			MethodName: "DeleteGroup",
+			Handler:    _Repo_DeleteGroup_Handler,
+		},
+		{
+			MethodName: "CreateAlbum",
+			Handler:    _Repo_CreateAlbum_Handler,
+		},
+		{
+			MethodName: "ListAlbums",
+			Handler:    _Repo_ListAlbums_Handler,
+		},
+		{
+			MethodName: "ListAlbumImages",
+			Handler:    _Repo_ListAlbumImages_Handler,
+		},
+		{
+			MethodName: "AddAlbumImages",
+			Handler:    _Repo_AddAlbumImages_Handler,
+		},
+		{
+			MethodName: "RemoveAlbumImages",
+			Handler:    _Repo_RemoveAlbumImages_Handler,
+		},
+		{
+			MethodName: "ShareAlbum",
+			Handler:    _Repo_ShareAlbum_Handler,
+		},
+		{
+			MethodName: "UnshareAlbum",
+			Handler:    _Repo_UnshareAlbum_Handler,
+		},
+		{
+			MethodName: "DeleteAlbum",
+			Handler:    _Repo_DeleteAlbum_Handler,
+		},
+		{
+			MethodName: "CreateShareLink",
+			Handler:    _Repo_CreateShareLink_Handler,
+		},
+		{
+			MethodName: "ListShareLinks",
+			Handler:    _Repo_ListShareLinks_Handler,
+		},
+		{
+			MethodName: "RevokeShareLink",
+			Handler:    _Repo_RevokeShareLink_Handler,
+		},
+		{
+			MethodName: "DeleteImage",
+			Handler:    _Repo_DeleteImage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadImage",
+			Handler:       _Repo_UploadImage_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WriteUpload",
+			Handler:       _Repo_WriteUpload_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadImage",
+			Handler:       _Repo_DownloadImage_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DownloadShared",
+			Handler:       _Repo_DownloadShared_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SearchSimilar",
+			Handler:       _Repo_SearchSimilar_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proto/imgrepo.proto",
+}
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	ListUsers(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	SetRole(ctx context.Context, in *SetRoleRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	SetDisabled(ctx context.Context, in *SetDisabledRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ForceDeleteImage(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	StorageUsage(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*StorageUsageResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) ListUsers(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, "/proto.Admin/ListUsers", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return x, nil
+	return out, nil
 }
 
-type Repo_DownloadImageClient interface {
-	Recv() (*Download, error)
-	grpc.ClientStream
+func (c *adminClient) SetRole(ctx context.Context, in *SetRoleRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Admin/SetRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type repoDownloadImageClient struct {
-	grpc.ClientStream
+func (c *adminClient) SetDisabled(ctx context.Context, in *SetDisabledRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Admin/SetDisabled", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (x *repoDownloadImageClient) Recv() (*Download, error) {
-	m := new(Download)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func (c *adminClient) ForceDeleteImage(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Admin/ForceDeleteImage", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return m, nil
+	return out, nil
 }
 
-func (c *repoClient) ListImages(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
-	out := new(ListResponse)
-	err := c.cc.Invoke(ctx, "/proto.Repo/ListImages", in, out, opts...)
+func (c *adminClient) StorageUsage(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*StorageUsageResponse, error) {
+	out := new(StorageUsageResponse)
+	err := c.cc.Invoke(ctx, "/proto.Admin/StorageUsage", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// RepoServer is the server API for Repo service.
-// All implementations must embed UnimplementedRepoServer
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
 // for forward compatibility
-type RepoServer interface {
-	Register(context.Context, *RegisterRequest) (*empty.Empty, error)
-	Login(context.Context, *LoginRequest) (*LoginResponse, error)
-	UploadImage(Repo_UploadImageServer) error
-	DownloadImage(*DownloadRequest, Repo_DownloadImageServer) error
-	ListImages(context.Context, *ListRequest) (*ListResponse, error)
-	mustEmbedUnimplementedRepoServer()
+type AdminServer interface {
+	ListUsers(context.Context, *empty.Empty) (*ListUsersResponse, error)
+	SetRole(context.Context, *SetRoleRequest) (*empty.Empty, error)
+	SetDisabled(context.Context, *SetDisabledRequest) (*empty.Empty, error)
+	ForceDeleteImage(context.Context, *DeleteRequest) (*empty.Empty, error)
+	StorageUsage(context.Context, *empty.Empty) (*StorageUsageResponse, error)
+	mustEmbedUnimplementedAdminServer()
 }
 
-// UnimplementedRepoServer must be embedded to have forward compatible implementations.
-type UnimplementedRepoServer struct {
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
 }
 
-func (UnimplementedRepoServer) Register(context.Context, *RegisterRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+func (UnimplementedAdminServer) ListUsers(context.Context, *empty.Empty) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
 }
-func (UnimplementedRepoServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+func (UnimplementedAdminServer) SetRole(context.Context, *SetRoleRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRole not implemented")
 }
-func (UnimplementedRepoServer) UploadImage(Repo_UploadImageServer) error {
-	return status.Errorf(codes.Unimplemented, "method UploadImage not implemented")
+func (UnimplementedAdminServer) SetDisabled(context.Context, *SetDisabledRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDisabled not implemented")
 }
-func (UnimplementedRepoServer) DownloadImage(*DownloadRequest, Repo_DownloadImageServer) error {
-	return status.Errorf(codes.Unimplemented, "method DownloadImage not implemented")
+func (UnimplementedAdminServer) ForceDeleteImage(context.Context, *DeleteRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForceDeleteImage not implemented")
 }
-func (UnimplementedRepoServer) ListImages(context.Context, *ListRequest) (*ListResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListImages not implemented")
+func (UnimplementedAdminServer) StorageUsage(context.Context, *empty.Empty) (*StorageUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StorageUsage not implemented")
 }
-func (UnimplementedRepoServer) mustEmbedUnimplementedRepoServer() {}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
 
-// UnsafeRepoServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to RepoServer will
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
 // result in compilation errors.
-type UnsafeRepoServer interface {
-	mustEmbedUnimplementedRepoServer()
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
 }
 
-func RegisterRepoServer(s grpc.ServiceRegistrar, srv RepoServer) {
-	s.RegisterService(&Repo_ServiceDesc, srv)
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
 }
 
-func _Repo_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterRequest)
+func _Admin_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RepoServer).Register(ctx, in)
+		return srv.(AdminServer).ListUsers(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Repo/Register",
+		FullMethod: "/proto.Admin/ListUsers",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RepoServer).Register(ctx, req.(*RegisterRequest))
+		return srv.(AdminServer).ListUsers(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Repo_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginRequest)
+func _Admin_SetRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRoleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RepoServer).Login(ctx, in)
+		return srv.(AdminServer).SetRole(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Repo/Login",
+		FullMethod: "/proto.Admin/SetRole",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RepoServer).Login(ctx, req.(*LoginRequest))
+		return srv.(AdminServer).SetRole(ctx, req.(*SetRoleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Repo_UploadImage_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(RepoServer).UploadImage(&repoUploadImageServer{stream})
-}
-
-type Repo_UploadImageServer interface {
-	SendAndClose(*empty.Empty) error
-	Recv() (*Upload, error)
-	grpc.ServerStream
-}
-
-type repoUploadImageServer struct {
-	grpc.ServerStream
-}
-
-func (x *repoUploadImageServer) SendAndClose(m *empty.Empty) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func (x *repoUploadImageServer) Recv() (*Upload, error) {
-	m := new(Upload)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _Admin_SetDisabled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDisabledRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
-}
-
-func _Repo_DownloadImage_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(DownloadRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+	if interceptor == nil {
+		return srv.(AdminServer).SetDisabled(ctx, in)
 	}
-	return srv.(RepoServer).DownloadImage(m, &repoDownloadImageServer{stream})
-}
-
-type Repo_DownloadImageServer interface {
-	Send(*Download) error
-	grpc.ServerStream
-}
-
-type repoDownloadImageServer struct {
-	grpc.ServerStream
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Admin/SetDisabled",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetDisabled(ctx, req.(*SetDisabledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *repoDownloadImageServer) Send(m *Download) error {
-	return x.ServerStream.SendMsg(m)
+func _Admin_ForceDeleteImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ForceDeleteImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Admin/ForceDeleteImage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ForceDeleteImage(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Repo_ListImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListRequest)
+func _Admin_StorageUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RepoServer).ListImages(ctx, in)
+		return srv.(AdminServer).StorageUsage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Repo/ListImages",
+		FullMethod: "/proto.Admin/StorageUsage",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RepoServer).ListImages(ctx, req.(*ListRequest))
+		return srv.(AdminServer).StorageUsage(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// Repo_ServiceDesc is the grpc.ServiceDesc for Repo service.
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
-var Repo_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "proto.Repo",
-	HandlerType: (*RepoServer)(nil),
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.Admin",
+	HandlerType: (*AdminServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Register",
-			Handler:    _Repo_Register_Handler,
+			MethodName: "ListUsers",
+			Handler:    _Admin_ListUsers_Handler,
 		},
 		{
-			MethodName: "Login",
-			Handler:    _Repo_Login_Handler,
+			MethodName: "SetRole",
+			Handler:    _Admin_SetRole_Handler,
 		},
 		{
-			MethodName: "ListImages",
-			Handler:    _Repo_ListImages_Handler,
+			MethodName: "SetDisabled",
+			Handler:    _Admin_SetDisabled_Handler,
 		},
-	},
-	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "UploadImage",
-			Handler:       _Repo_UploadImage_Handler,
-			ClientStreams: true,
+			MethodName: "ForceDeleteImage",
+			Handler:    _Admin_ForceDeleteImage_Handler,
 		},
 		{
-			StreamName:    "DownloadImage",
-			Handler:       _Repo_DownloadImage_Handler,
-			ServerStreams: true,
+			MethodName: "StorageUsage",
+			Handler:    _Admin_StorageUsage_Handler,
 		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/imgrepo.proto",
 }
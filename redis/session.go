@@ -35,12 +35,12 @@ func NewSessionService(addr, port, pass string, db int) (*SessionService, error)
 	return &SessionService{rdb: rdb}, nil
 }
 
-func (s *SessionService) NewSession() (string, error) {
+func (s *SessionService) NewSession(username string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
 	uuid := uuid.NewString()
-	_, err := s.rdb.Set(ctx, uuid, true, 30*time.Minute).Result()
+	_, err := s.rdb.Set(ctx, uuid, username, 30*time.Minute).Result()
 	if err != nil {
 		return "", fmt.Errorf("%q: %w", "unable to set session", err)
 	}
@@ -48,14 +48,16 @@ func (s *SessionService) NewSession() (string, error) {
 	return uuid, nil
 }
 
-func (s *SessionService) IsSession(uuid string) error {
+func (s *SessionService) IsSession(uuid string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 500*time.Millisecond)
 	defer cancel()
 
-	_, err := s.rdb.Get(ctx, uuid).Result()
-	if err != nil {
-		return fmt.Errorf("%q: %w", "no session found", err)
+	username, err := s.rdb.Get(ctx, uuid).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("no session found: %w", imgrepo.ErrUnauthenticated)
+	} else if err != nil {
+		return "", fmt.Errorf("%q: %w", "unable to get session", err)
 	}
 
-	return nil
+	return username, nil
 }